@@ -0,0 +1,231 @@
+package autocomplete
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/text"
+	"github.com/platx/geonames/value"
+)
+
+const (
+	defaultMaxRows = 10
+	maxRowsLimit   = 100
+	// maxSortedCandidates longer prefixes matching more names are looked up in the list of the indexed prefix
+	maxSortedCandidates = 256
+)
+
+// Query is the typed text to complete.
+type Query struct {
+	// Prefix start of the name, compared case, diacritics and script insensitive
+	Prefix string
+	// MaxRows maximal number of suggestions, default is 10, max is 100
+	MaxRows int
+	// CountryBias places of this country are ranked higher, see WithCountryBoost
+	CountryBias value.CountryCode
+	// Language only alternate names of this language are matched besides the main names, empty matches all names
+	Language string
+}
+
+// Suggestion is a place whose name starts with the queried prefix.
+type Suggestion struct {
+	download.GeoName
+
+	// Name the matched name of the place
+	Name string
+	// Language of the matched alternate name, empty for names without language
+	Language string
+	// Display label of the place without repeated parts, e.g. `Paris, Île-de-France, France`,
+	// the second admin division is added when an earlier suggestion has the same label
+	Display string
+	// Score population weighted by the feature code, multiplied by the boost for the bias country
+	Score float64
+}
+
+// candidate is an entry in the top suggestions.
+type candidate struct {
+	entry int
+	score float64
+}
+
+// Complete returns the best ranked places with a name starting with the prefix, each place is returned once.
+func (idx *Index) Complete(q Query) []Suggestion {
+	prefix := text.Normalize(q.Prefix)
+	if prefix == "" {
+		return nil
+	}
+
+	limit := q.MaxRows
+	if limit <= 0 {
+		limit = defaultMaxRows
+	}
+
+	limit = min(limit, maxRowsLimit)
+
+	boost := 1.0
+	if q.CountryBias != "" {
+		boost = max(idx.countryBoost, 1)
+	}
+
+	top := make([]candidate, 0, limit+1)
+	seen := make(map[int]struct{})
+	candidates := idx.candidates(prefix)
+	typed := strings.ToLower(strings.TrimSpace(q.Prefix))
+
+	// candidates are ranked best first, so the lookup stops as soon as no later place can make it to the top
+	for i, id := range candidates {
+		e := &idx.entries[id]
+		p := &idx.places[e.place]
+
+		if len(top) == limit && p.rank*boost <= top[limit-1].score {
+			break
+		}
+
+		if _, ok := seen[e.place]; ok || !e.matchLanguage(q.Language) {
+			continue
+		}
+
+		seen[e.place] = struct{}{}
+		id = idx.preferTyped(candidates[i:], typed, q.Language)
+
+		score := p.rank
+		if q.CountryBias != "" && p.item.CountryCode == q.CountryBias {
+			score *= boost
+		}
+
+		i, _ := slices.BinarySearchFunc(top, score, func(c candidate, score float64) int {
+			// equal scores stay in rank order
+			if c.score >= score {
+				return -1
+			}
+
+			return 1
+		})
+
+		top = slices.Insert(top, i, candidate{entry: id, score: score})
+		top = top[:min(len(top), limit)]
+	}
+
+	return idx.suggestions(top)
+}
+
+// candidates returns the ids of the entries with the key prefix, best ranked first.
+func (idx *Index) candidates(prefix string) []int {
+	runes := []rune(prefix)
+	if len(runes) <= indexedPrefixLength {
+		return idx.prefixes[prefix]
+	}
+
+	start := sort.Search(len(idx.entries), func(i int) bool {
+		return idx.entries[i].key >= prefix
+	})
+	end := start + sort.Search(len(idx.entries)-start, func(i int) bool {
+		return !strings.HasPrefix(idx.entries[start+i].key, prefix)
+	})
+
+	if end-start > maxSortedCandidates {
+		// filtering the ranked list of the shorter prefix is cheaper than sorting many names
+		res := make([]int, 0, end-start)
+
+		for _, id := range idx.prefixes[string(runes[:indexedPrefixLength])] {
+			if strings.HasPrefix(idx.entries[id].key, prefix) {
+				res = append(res, id)
+			}
+		}
+
+		return res
+	}
+
+	res := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		res = append(res, i)
+	}
+
+	slices.SortFunc(res, idx.compareIDs)
+
+	return res
+}
+
+// preferTyped returns the first name of the place starting with the typed text as is, e.g. `Москва` rather than
+// `Moskau` for `Моск`, or the first name of the place when there is none. The names of a place are adjacent.
+func (idx *Index) preferTyped(candidates []int, typed, language string) int {
+	place := idx.entries[candidates[0]].place
+
+	for _, id := range candidates {
+		e := &idx.entries[id]
+		if e.place != place {
+			break
+		}
+
+		if e.matchLanguage(language) && strings.HasPrefix(strings.ToLower(e.name), typed) {
+			return id
+		}
+	}
+
+	return candidates[0]
+}
+
+// matchLanguage reports whether the name is searched for the language, main names always are.
+func (e *entry) matchLanguage(language string) bool {
+	return language == "" || e.kind != kindAlternateName || e.language == language
+}
+
+func (idx *Index) suggestions(top []candidate) []Suggestion {
+	res := make([]Suggestion, 0, len(top))
+	displays := make(map[string]struct{}, len(top))
+
+	for _, c := range top {
+		e := &idx.entries[c.entry]
+		item := idx.places[e.place].item
+
+		display := idx.display(item, e.name, false)
+		if _, ok := displays[display]; ok {
+			display = idx.display(item, e.name, true)
+		}
+
+		displays[display] = struct{}{}
+
+		res = append(res, Suggestion{
+			GeoName:  item,
+			Name:     e.name,
+			Language: e.language,
+			Display:  display,
+			Score:    c.score,
+		})
+	}
+
+	return res
+}
+
+// display joins the name with the admin division and country names, skipping empty and repeated parts,
+// e.g. `Singapore` instead of `Singapore, Singapore`.
+func (idx *Index) display(item download.GeoName, name string, detailed bool) string {
+	parts := []string{name}
+
+	if detailed {
+		parts = append(parts, idx.gazetteer.AdminDivisionSecond(item).Name)
+	}
+
+	parts = append(parts, idx.gazetteer.AdminDivisionFirst(item).Name)
+
+	if country, ok := idx.gazetteer.Country(item.CountryCode); ok {
+		parts = append(parts, country.Name)
+	}
+
+	res := make([]string, 0, len(parts))
+	keys := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		key := text.Normalize(part)
+		if key == "" || slices.Contains(keys, key) {
+			continue
+		}
+
+		res = append(res, part)
+		keys = append(keys, key)
+	}
+
+	return strings.Join(res, ", ")
+}
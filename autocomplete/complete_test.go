@@ -0,0 +1,228 @@
+package autocomplete
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Index_Complete(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex(t)
+
+	tests := []struct {
+		name     string
+		given    Query
+		exp      []uint64
+		names    []string
+		displays []string
+	}{
+		{
+			name:     "short prefix",
+			given:    Query{Prefix: "par"},
+			exp:      []uint64{2988507, 3171457, 4717560, 2968815},
+			displays: []string{"Paris, Île-de-France, France", "Parma, Emilia-Romagna, Italy", "Paris, Texas, United States", "Paris, Île-de-France, France"},
+		},
+		{
+			name:     "long prefix",
+			given:    Query{Prefix: "Pari"},
+			exp:      []uint64{2988507, 4717560, 2968815},
+			displays: []string{"Paris, Île-de-France, France", "Paris, Texas, United States", "Paris, Île-de-France, France"},
+		},
+		{
+			name:  "country bias",
+			given: Query{Prefix: "par", CountryBias: value.CountryCodeUnitedStates},
+			exp:   []uint64{2988507, 4717560, 3171457, 2968815},
+		},
+		{
+			name:  "max rows",
+			given: Query{Prefix: "par", MaxRows: 1},
+			exp:   []uint64{2988507},
+		},
+		{
+			name:     "same display",
+			given:    Query{Prefix: "spring"},
+			exp:      []uint64{4250542, 4409896, 4250544},
+			displays: []string{"Springfield, Illinois, United States", "Springfield, Missouri, United States", "Springfield, Clark County, Illinois, United States"},
+		},
+		{
+			name:     "repeated display parts",
+			given:    Query{Prefix: "sing"},
+			exp:      []uint64{1880252},
+			displays: []string{"Singapore"},
+		},
+		{
+			name:     "feature importance",
+			given:    Query{Prefix: "mo"},
+			exp:      []uint64{524901, 2867714, 2993458, 2994154},
+			names:    []string{"Moscow", "Monaco di Baviera", "Monaco", "Mont Blanc"},
+			displays: []string{"Moscow, Russia", "Monaco di Baviera, Bavaria, Germany", "Monaco", "Mont Blanc, Auvergne-Rhône-Alpes, France"},
+		},
+		{
+			name:  "language",
+			given: Query{Prefix: "mo", Language: "de"},
+			exp:   []uint64{524901, 2993458, 2994154},
+			names: []string{"Moscow", "Monaco", "Mont Blanc"},
+		},
+		{
+			name:  "language of alternate name",
+			given: Query{Prefix: "muni", Language: "en"},
+			exp:   []uint64{2867714},
+			names: []string{"Munich"},
+		},
+		{
+			name:  "other language",
+			given: Query{Prefix: "muni", Language: "de"},
+			exp:   []uint64{},
+		},
+		{
+			name:  "ascii name",
+			given: Query{Prefix: "muen"},
+			exp:   []uint64{2867714},
+			names: []string{"Muenchen"},
+		},
+		{
+			name:  "diacritics",
+			given: Query{Prefix: "Münch"},
+			exp:   []uint64{2867714},
+			names: []string{"München"},
+		},
+		{
+			name:     "other script",
+			given:    Query{Prefix: "Моск"},
+			exp:      []uint64{524901},
+			names:    []string{"Москва"},
+			displays: []string{"Москва, Moscow, Russia"},
+		},
+		{
+			name:  "link",
+			given: Query{Prefix: "https"},
+			exp:   []uint64{},
+		},
+		{
+			name:  "empty",
+			given: Query{Prefix: " - "},
+			exp:   []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := idx.Complete(tt.given)
+
+			ids := make([]uint64, 0, len(actual))
+			names := make([]string, 0, len(actual))
+			displays := make([]string, 0, len(actual))
+
+			for _, item := range actual {
+				ids = append(ids, item.ID)
+				names = append(names, item.Name)
+				displays = append(displays, item.Display)
+			}
+
+			assert.Equal(t, tt.exp, ids)
+
+			if tt.names != nil {
+				assert.Equal(t, tt.names, names)
+			}
+
+			if tt.displays != nil {
+				assert.Equal(t, tt.displays, displays)
+			}
+		})
+	}
+}
+
+func Test_Index_Complete_Score(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex(t, WithCountryBoost(10))
+
+	actual := idx.Complete(Query{Prefix: "paris", CountryBias: value.CountryCodeUnitedStates})
+
+	require.Len(t, actual, 3)
+	assert.InDelta(t, 2138552*8, actual[0].Score, 1e-6)
+	assert.InDelta(t, 24172*2*10, actual[1].Score, 1e-6)
+}
+
+func Test_Index_Complete_BruteForce(t *testing.T) {
+	t.Parallel()
+
+	places := randomPlaces(5000)
+
+	idx, err := Build(Source{Places: download.FromSlice(places)})
+	require.NoError(t, err)
+
+	// short prefixes use the precomputed lists, long ones the sorted names or the filtered lists
+	for _, prefix := range []string{"b", "ka", "lo", "man", "rizu", "sato", "vil", "ville", "zuzuzu", "elburgx"} {
+		expected := make([]download.GeoName, 0)
+
+		for _, item := range places {
+			if strings.HasPrefix(item.Name, prefix) {
+				expected = append(expected, item)
+			}
+		}
+
+		slices.SortStableFunc(expected, func(a, b download.GeoName) int {
+			return cmp.Compare(b.Population, a.Population)
+		})
+
+		actual := idx.Complete(Query{Prefix: prefix, MaxRows: 20})
+
+		require.Len(t, actual, min(len(expected), 20), prefix)
+
+		for i, item := range actual {
+			assert.Equal(t, expected[i].ID, item.ID, prefix)
+		}
+	}
+}
+
+func Benchmark_Index_Complete(b *testing.B) {
+	// about the size of cities1000
+	idx, err := Build(Source{Places: download.FromSlice(randomPlaces(150000))})
+	require.NoError(b, err)
+
+	prefixes := []string{"b", "ka", "los", "mane", "rizu", "toelburg", "Ville"}
+
+	b.ResetTimer()
+
+	for i := range b.N {
+		idx.Complete(Query{Prefix: prefixes[i%len(prefixes)], CountryBias: value.CountryCodeGermany})
+	}
+}
+
+func randomPlaces(size int) []download.GeoName {
+	syllables := []string{"ba", "ka", "lo", "ma", "ne", "ri", "sa", "to", "vi", "zu", "an", "el", "or", "burg", "ville"}
+	random := rand.New(rand.NewPCG(1, 2))
+	res := make([]download.GeoName, size)
+
+	for i := range res {
+		var name strings.Builder
+
+		for range 2 + random.IntN(3) {
+			name.WriteString(syllables[random.IntN(len(syllables))])
+		}
+
+		res[i] = download.GeoName{
+			ID:          uint64(i),
+			Name:        name.String(),
+			NameASCII:   name.String(),
+			FeatureCode: "PPL",
+			CountryCode: value.CountryCodeGermany,
+			Population:  random.Int64N(1000000),
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,271 @@
+// Package autocomplete provides an in-memory prefix index over GeoNames records for search as you type,
+// an offline replacement for calling the search webservice with NameStartsWith on every keystroke.
+package autocomplete
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/internal/text"
+)
+
+const (
+	defaultCountryBoost = 100
+	// indexedPrefixLength prefixes up to this number of runes have precomputed candidate lists,
+	// longer ones are looked up in the sorted names
+	indexedPrefixLength = 3
+)
+
+var ErrMissingPlaces = errors.New("places iterator is required")
+
+// featureWeights multiplies the population of places with important feature codes, e.g. capitals.
+var featureWeights = map[string]float64{
+	"PCLI":  16,
+	"PPLC":  8,
+	"PPLA":  4,
+	"PPLG":  4,
+	"ADM1":  2,
+	"PPLA2": 2,
+	"PPLA3": 1.5,
+	"PPLA4": 1.2,
+}
+
+// Source lists the records to suggest and the files used to localize and label the suggestions.
+type Source struct {
+	// Places records to suggest, usually cities1000 or cities5000, required
+	Places download.Iterator[download.GeoName]
+	// AlternateNames names in other languages, used by Query.Language, optional
+	AlternateNames download.Iterator[download.AlternateName]
+	// AdminDivisionFirst admin1CodesASCII.txt entries, used by the display string, optional
+	AdminDivisionFirst download.Iterator[download.AdminDivision]
+	// AdminDivisionSecond admin2Codes.txt entries, used to tell apart places of the same name, optional
+	AdminDivisionSecond download.Iterator[download.AdminDivision]
+	// Countries countryInfo.txt entries, used by the display string, optional
+	Countries download.Iterator[download.Country]
+}
+
+// Index suggests places by the start of their names from precomputed prefix lists. Complete never writes to it,
+// so the suggestions of many users can be computed in parallel.
+type Index struct {
+	places    []place
+	gazetteer *gazetteer.Gazetteer
+	// entries all names sorted by key
+	entries []entry
+	// prefixes maps short key prefixes to the ids of the entries having them, best ranked first
+	prefixes     map[string][]int
+	countryBoost float64
+}
+
+type place struct {
+	item download.GeoName
+	rank float64
+}
+
+// entry is a single name of a place.
+type entry struct {
+	key      string
+	name     string
+	language string
+	kind     kind
+	place    int
+}
+
+// kind of name, main names are preferred over alternate names of the same place.
+type kind uint8
+
+const (
+	kindName kind = iota
+	kindASCIIName
+	kindAlternateName
+)
+
+type Option func(*Index)
+
+// WithCountryBoost sets the factor the rank of places in Query.CountryBias is multiplied with, default is 100.
+func WithCountryBoost(boost float64) Option {
+	return func(idx *Index) {
+		idx.countryBoost = boost
+	}
+}
+
+// Build reads all source iterators and builds the index.
+func Build(src Source, opts ...Option) (*Index, error) {
+	var err error
+
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	res := &Index{
+		places:       make([]place, 0),
+		gazetteer:    nil,
+		entries:      make([]entry, 0),
+		prefixes:     make(map[string][]int),
+		countryBoost: defaultCountryBoost,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	if res.gazetteer, err = gazetteer.Build(src.AdminDivisionFirst, src.AdminDivisionSecond, src.Countries); err != nil {
+		return nil, err
+	}
+
+	b := builder{
+		index: res,
+		byID:  make(map[uint64]int),
+		seen:  make(map[seenKey]struct{}),
+	}
+
+	if err = b.places(src.Places); err != nil {
+		return nil, err
+	}
+
+	if err = b.alternateNames(src.AlternateNames); err != nil {
+		return nil, err
+	}
+
+	res.sort()
+
+	return res, nil
+}
+
+// Len returns the number of indexed places.
+func (idx *Index) Len() int {
+	return len(idx.places)
+}
+
+// sort orders the entries by key and builds the prefix lists.
+func (idx *Index) sort() {
+	slices.SortFunc(idx.entries, func(a, b entry) int {
+		return cmp.Or(cmp.Compare(a.key, b.key), idx.compareRank(a, b))
+	})
+
+	ranked := make([]int, len(idx.entries))
+	for i := range ranked {
+		ranked[i] = i
+	}
+
+	slices.SortFunc(ranked, idx.compareIDs)
+
+	for _, id := range ranked {
+		runes := []rune(idx.entries[id].key)
+
+		for length := 1; length <= min(len(runes), indexedPrefixLength); length++ {
+			prefix := string(runes[:length])
+			idx.prefixes[prefix] = append(idx.prefixes[prefix], id)
+		}
+	}
+}
+
+// compareIDs orders entry ids by rank, see compareRank.
+func (idx *Index) compareIDs(a, b int) int {
+	return cmp.Or(idx.compareRank(idx.entries[a], idx.entries[b]), cmp.Compare(a, b))
+}
+
+// compareRank orders by place rank, then keeps the names of a place together with main names first.
+func (idx *Index) compareRank(a, b entry) int {
+	return cmp.Or(
+		cmp.Compare(idx.places[b.place].rank, idx.places[a.place].rank),
+		cmp.Compare(a.place, b.place),
+		cmp.Compare(a.kind, b.kind),
+		cmp.Compare(a.language, b.language),
+		cmp.Compare(a.name, b.name),
+	)
+}
+
+// builder collects the places and their names.
+type builder struct {
+	index *Index
+	byID  map[uint64]int
+	// seen normalized names per place and language, so that e.g. an ascii name equal to the name is added once
+	seen map[seenKey]struct{}
+}
+
+type seenKey struct {
+	place    int
+	key      string
+	language string
+}
+
+func (b *builder) places(items download.Iterator[download.GeoName]) error {
+	for item, err := range items {
+		if err != nil {
+			return fmt.Errorf("read places => %w", err)
+		}
+
+		id := len(b.index.places)
+
+		b.byID[item.ID] = id
+		b.index.places = append(b.index.places, place{
+			item: item,
+			rank: rank(item),
+		})
+
+		b.add(id, item.Name, kindName, "")
+		b.add(id, item.NameASCII, kindASCIIName, "")
+
+		for _, alternate := range item.AlternateNames {
+			b.add(id, alternate, kindAlternateName, "")
+		}
+	}
+
+	return nil
+}
+
+func (b *builder) alternateNames(items download.Iterator[download.AlternateName]) error {
+	if items == nil {
+		return nil
+	}
+
+	for item, err := range items {
+		if err != nil {
+			return fmt.Errorf("read alternate names => %w", err)
+		}
+
+		id, ok := b.byID[item.GeoNameID]
+		if !ok || !gazetteer.IsName(item) {
+			continue
+		}
+
+		b.add(id, item.Value, kindAlternateName, item.Language)
+	}
+
+	return nil
+}
+
+func (b *builder) add(id int, name string, kind kind, language string) {
+	key := text.Normalize(name)
+	if key == "" {
+		return
+	}
+
+	seen := seenKey{place: id, key: key, language: language}
+	if _, ok := b.seen[seen]; ok {
+		return
+	}
+
+	b.seen[seen] = struct{}{}
+	b.index.entries = append(b.index.entries, entry{
+		key:      key,
+		name:     name,
+		language: language,
+		kind:     kind,
+		place:    id,
+	})
+}
+
+// rank returns the importance of the place, its population weighted by the feature code.
+func rank(item download.GeoName) float64 {
+	weight, ok := featureWeights[item.FeatureCode]
+	if !ok {
+		weight = 1
+	}
+
+	return float64(max(item.Population, 0)+1) * weight
+}
@@ -0,0 +1,75 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil/fixture"
+)
+
+func newTestIndex(t *testing.T, opts ...Option) *Index {
+	t.Helper()
+
+	res, err := Build(Source{
+		Places:              download.FromSlice(fixture.Places()),
+		AlternateNames:      download.FromSlice(fixture.AlternateNames()),
+		AdminDivisionFirst:  download.FromSlice(fixture.AdminDivisionsFirst()),
+		AdminDivisionSecond: download.FromSlice(fixture.AdminDivisionsSecond()),
+		Countries:           download.FromSlice(fixture.Countries()),
+	}, opts...)
+
+	require.NoError(t, err)
+
+	return res
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, len(fixture.Places()), newTestIndex(t).Len())
+	})
+
+	tests := []struct {
+		name string
+		src  Source
+		err  string
+	}{
+		{
+			name: "missing places",
+			src:  Source{},
+			err:  ErrMissingPlaces.Error(),
+		},
+		{
+			name: "places error",
+			src:  Source{Places: fixture.Failing(fixture.Places(), assert.AnError)},
+			err:  "read places => " + assert.AnError.Error(),
+		},
+		{
+			name: "alternate names error",
+			src:  Source{Places: download.FromSlice(fixture.Places()), AlternateNames: fixture.Failing(fixture.AlternateNames(), assert.AnError)},
+			err:  "read alternate names => " + assert.AnError.Error(),
+		},
+		{
+			name: "countries error",
+			src:  Source{Places: download.FromSlice(fixture.Places()), Countries: fixture.Failing(fixture.Countries(), assert.AnError)},
+			err:  "read countries => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Build(tt.src)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, actual)
+		})
+	}
+}
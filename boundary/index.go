@@ -0,0 +1,297 @@
+// Package boundary tells which country a position lies in by testing it against the polygons of the GeoNames
+// country shapes. Index.CountryCode answers a webservice.CountryCodeRequest without any network call.
+package boundary
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+var (
+	ErrNotFound      = errors.New("no country found")
+	ErrMissingShapes = errors.New("shapes iterator is required")
+	ErrMissingInfo   = errors.New("country info iterator is required")
+)
+
+// Source pairs the shapes with the country info needed to map their GeoNameID to a country.
+type Source struct {
+	// Shapes country boundaries, see download.Client.Shapes
+	Shapes download.Iterator[download.Shape]
+	// Countries country info used to resolve shape ids to countries
+	Countries download.Iterator[download.Country]
+}
+
+// Index resolves positions to countries through a one degree grid of polygon parts. Lookups never modify it,
+// concurrent calls need no locking.
+type Index struct {
+	countries []download.Country
+	parts     []part
+	grid      map[cell][]int
+	buffer    float64
+}
+
+// part is a single polygon of a country with its precomputed bounding box.
+type part struct {
+	country int
+	polygon value.Polygon
+	box     value.BoundingBox
+}
+
+// cell is a one degree grid cell, identified by the floor of its south-west corner.
+type cell struct {
+	lat int
+	lng int
+}
+
+type Option func(*Index)
+
+// WithBuffer sets the default buffer in km for points outside of all countries, e.g. just offshore.
+// A positive buffer expands the countries whereas a negative buffer reduces them, same as
+// webservice.CountryCodeRequest.Radius.
+func WithBuffer(buffer float64) Option {
+	return func(idx *Index) {
+		idx.buffer = buffer
+	}
+}
+
+// Build reads all source iterators and builds the index, shapes of unknown countries are skipped.
+func Build(src Source, opts ...Option) (*Index, error) {
+	res := &Index{
+		countries: make([]download.Country, 0),
+		parts:     make([]part, 0),
+		grid:      make(map[cell][]int),
+		buffer:    0,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	if src.Shapes == nil {
+		return nil, ErrMissingShapes
+	}
+
+	if src.Countries == nil {
+		return nil, ErrMissingInfo
+	}
+
+	byID := make(map[uint64]int)
+
+	for item, err := range src.Countries {
+		if err != nil {
+			return nil, fmt.Errorf("read countries => %w", err)
+		}
+
+		byID[item.ID] = len(res.countries)
+		res.countries = append(res.countries, item)
+	}
+
+	for item, err := range src.Shapes {
+		if err != nil {
+			return nil, fmt.Errorf("read shapes => %w", err)
+		}
+
+		country, ok := byID[item.GeoNameID]
+		if !ok {
+			continue
+		}
+
+		for _, polygon := range item.Geometry {
+			res.add(part{country: country, polygon: polygon, box: polygon.BoundingBox()})
+		}
+	}
+
+	return res, nil
+}
+
+// Download fetches the shapes and country info with the client and builds the index.
+func Download(ctx context.Context, client *download.Client, opts ...Option) (*Index, error) {
+	var (
+		src Source
+		err error
+	)
+
+	if src.Shapes, err = client.Shapes(ctx); err != nil {
+		return nil, fmt.Errorf("download shapes => %w", err)
+	}
+
+	if src.Countries, err = client.CountryInfo(ctx); err != nil {
+		return nil, fmt.Errorf("download country info => %w", err)
+	}
+
+	return Build(src, opts...)
+}
+
+// Lookup returns the country containing the position using the default buffer,
+// ErrNotFound is returned when there is none.
+func (idx *Index) Lookup(position value.Position) (webservice.CountryNearby, error) {
+	return idx.lookup(position, idx.buffer)
+}
+
+// CountryCode mirrors webservice.Client.CountryCode. A set Radius overrides the default buffer,
+// Language is ignored and the names are always the ones from the country info file.
+func (idx *Index) CountryCode(
+	ctx context.Context,
+	req webservice.CountryCodeRequest,
+) (webservice.CountryNearby, error) {
+	if err := ctx.Err(); err != nil {
+		return webservice.CountryNearby{}, err
+	}
+
+	buffer := idx.buffer
+	if req.Radius != nil {
+		buffer = *req.Radius
+	}
+
+	return idx.lookup(req.Position, buffer)
+}
+
+// lookup prefers countries containing the position, the one whose border is farthest wins when shapes of different
+// countries overlap. Otherwise, the nearest country within a positive buffer is returned with its distance.
+func (idx *Index) lookup(position value.Position, buffer float64) (webservice.CountryNearby, error) {
+	position.Longitude = value.NormalizeLongitude(position.Longitude)
+
+	containing := make([]part, 0)
+	overlap := false
+
+	for _, i := range idx.grid[cellOf(position)] {
+		p := idx.parts[i]
+		if !p.polygon.ContainsWithin(p.box, position) {
+			continue
+		}
+
+		overlap = overlap || (len(containing) > 0 && containing[0].country != p.country)
+		containing = append(containing, p)
+	}
+
+	if len(containing) > 0 && buffer >= 0 && !overlap {
+		return idx.result(containing[0].country, 0), nil
+	}
+
+	best, bestDepth := -1, math.Inf(-1)
+
+	for _, p := range containing {
+		depth := p.polygon.Distance(position)
+		if buffer < 0 && depth < -buffer {
+			continue
+		}
+
+		if depth > bestDepth || (depth == bestDepth && idx.less(p.country, best)) {
+			best, bestDepth = p.country, depth
+		}
+	}
+
+	if best != -1 {
+		return idx.result(best, 0), nil
+	}
+
+	if buffer <= 0 {
+		return webservice.CountryNearby{}, ErrNotFound
+	}
+
+	return idx.nearest(position, buffer)
+}
+
+// nearest returns the country nearest to the position outside of all countries, searching the grid cells within
+// the buffer.
+func (idx *Index) nearest(position value.Position, buffer float64) (webservice.CountryNearby, error) {
+	around := value.BoundingBoxAround(position, buffer)
+	seen := make(map[int]struct{})
+	best, bestDistance := -1, math.Inf(1)
+
+	for key := range cellsOf(around) {
+		for _, i := range idx.grid[key] {
+			if _, ok := seen[i]; ok {
+				continue
+			}
+
+			seen[i] = struct{}{}
+
+			p := idx.parts[i]
+			if !around.Intersects(p.box) {
+				continue
+			}
+
+			distance := p.polygon.Distance(position)
+			if distance > buffer {
+				continue
+			}
+
+			if distance < bestDistance || (distance == bestDistance && idx.less(p.country, best)) {
+				best, bestDistance = p.country, distance
+			}
+		}
+	}
+
+	if best == -1 {
+		return webservice.CountryNearby{}, ErrNotFound
+	}
+
+	return idx.result(best, bestDistance), nil
+}
+
+func (idx *Index) add(p part) {
+	idx.parts = append(idx.parts, p)
+
+	for key := range cellsOf(p.box) {
+		idx.grid[key] = append(idx.grid[key], len(idx.parts)-1)
+	}
+}
+
+// less orders countries by code to keep ties deterministic.
+func (idx *Index) less(a, b int) bool {
+	return b == -1 || cmp.Less(idx.countries[a].Code, idx.countries[b].Code)
+}
+
+func (idx *Index) result(country int, distance float64) webservice.CountryNearby {
+	item := idx.countries[country]
+
+	return webservice.CountryNearby{
+		Country: value.Country{
+			ID:   item.ID,
+			Code: item.Code,
+			Name: item.Name,
+		},
+		Languages: item.Languages,
+		Distance:  distance,
+	}
+}
+
+func cellOf(position value.Position) cell {
+	return cell{
+		lat: int(math.Floor(position.Latitude)),
+		lng: int(math.Floor(position.Longitude)),
+	}
+}
+
+// cellsOf returns the grid cells covering the box, wrapping around the antimeridian when the box crosses it.
+// Positions are looked up with normalized longitudes, so there are no cells east of 179.
+func cellsOf(box value.BoundingBox) iter.Seq[cell] {
+	return func(yield func(cell) bool) {
+		west, east := int(math.Floor(box.West)), min(int(math.Floor(box.East)), 179)
+		if box.CrossesAntimeridian() {
+			east += 360
+		}
+
+		for lat := int(math.Floor(box.South)); lat <= int(math.Floor(box.North)); lat++ {
+			for lng := west; lng <= east; lng++ {
+				key := cell{lat: lat, lng: lng}
+				if lng > 179 {
+					key.lng -= 360
+				}
+
+				if !yield(key) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package boundary
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func newTestIndex(t *testing.T, opts ...Option) *Index {
+	t.Helper()
+
+	res, err := Build(Source{
+		Shapes:    download.FromSlice(fixture.Shapes()),
+		Countries: download.FromSlice(fixture.Countries()),
+	}, opts...)
+
+	require.NoError(t, err)
+
+	return res
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  Source
+		err  string
+	}{
+		{
+			name: "missing shapes",
+			src:  Source{Countries: download.FromSlice(fixture.Countries())},
+			err:  ErrMissingShapes.Error(),
+		},
+		{
+			name: "missing countries",
+			src:  Source{Shapes: download.FromSlice(fixture.Shapes())},
+			err:  ErrMissingInfo.Error(),
+		},
+		{
+			name: "countries error",
+			src:  Source{Shapes: download.FromSlice(fixture.Shapes()), Countries: fixture.Failing(fixture.Countries(), assert.AnError)},
+			err:  "read countries => " + assert.AnError.Error(),
+		},
+		{
+			name: "shapes error",
+			src:  Source{Shapes: fixture.Failing(fixture.Shapes(), assert.AnError), Countries: download.FromSlice(fixture.Countries())},
+			err:  "read shapes => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Build(tt.src)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func Test_Download(t *testing.T) {
+	t.Parallel()
+
+	client := download.NewClient(download.WithHTTPClient(testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+		m.On("Do", mock.AnythingOfType("*http.Request")).Once().Return(
+			&http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody},
+			nil,
+		)
+	})))
+
+	actual, err := Download(context.Background(), client)
+
+	require.ErrorIs(t, err, download.ErrUnexpectedStatusCode)
+	require.ErrorContains(t, err, "download shapes => ")
+	assert.Nil(t, actual)
+}
+
+func Test_Index_Lookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		given    value.Position
+		exp      value.CountryCode
+		distance float64
+	}{
+		{
+			name:  "inside",
+			given: value.Position{Latitude: 47, Longitude: 2},
+			exp:   value.CountryCodeFrance,
+		},
+		{
+			name:  "second polygon",
+			given: value.Position{Latitude: 51.7, Longitude: 3.5},
+			exp:   value.CountryCodeBelgium,
+		},
+		{
+			name:  "overlap resolved by depth",
+			given: value.Position{Latitude: 49, Longitude: 7.4},
+			exp:   value.CountryCodeGermany,
+		},
+		{
+			name:  "overlap tie resolved by code",
+			given: value.Position{Latitude: 49, Longitude: 6.75},
+			exp:   value.CountryCodeGermany,
+		},
+		{
+			name:     "offshore within buffer",
+			opts:     []Option{WithBuffer(50)},
+			given:    value.Position{Latitude: 47, Longitude: -5.5},
+			exp:      value.CountryCodeFrance,
+			distance: 37.92,
+		},
+		{
+			name:  "offshore outside buffer",
+			opts:  []Option{WithBuffer(30)},
+			given: value.Position{Latitude: 47, Longitude: -5.5},
+		},
+		{
+			name:  "offshore without buffer",
+			given: value.Position{Latitude: 47, Longitude: -5.5},
+		},
+		{
+			name:  "negative buffer close to border",
+			opts:  []Option{WithBuffer(-10)},
+			given: value.Position{Latitude: 47, Longitude: -4.9},
+		},
+		{
+			name:  "negative buffer far from border",
+			opts:  []Option{WithBuffer(-5)},
+			given: value.Position{Latitude: 47, Longitude: -4.9},
+			exp:   value.CountryCodeFrance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := newTestIndex(t, tt.opts...).Lookup(tt.given)
+
+			if tt.exp == "" {
+				require.ErrorIs(t, err, ErrNotFound)
+				assert.Equal(t, webservice.CountryNearby{}, actual)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, actual.Code)
+			assert.InDelta(t, tt.distance, actual.Distance, 0.01)
+		})
+	}
+}
+
+func Test_Index_Lookup_unknownCountry(t *testing.T) {
+	t.Parallel()
+
+	shapes := append(fixture.Shapes(), download.Shape{GeoNameID: 1, Geometry: value.MultiPolygon{square(0, 80, -30, 30)}})
+
+	idx, err := Build(Source{Shapes: download.FromSlice(shapes), Countries: download.FromSlice(fixture.Countries())})
+
+	require.NoError(t, err)
+
+	actual, err := idx.Lookup(value.Position{Latitude: 10, Longitude: 10})
+
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, webservice.CountryNearby{}, actual)
+}
+
+func Test_Index_CountryCode(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex(t, WithBuffer(10))
+
+	t.Run("result", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := idx.CountryCode(context.Background(), webservice.CountryCodeRequest{
+			Position: value.Position{Latitude: 47, Longitude: 2},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, webservice.CountryNearby{
+			Country:   value.Country{ID: 3017382, Code: value.CountryCodeFrance, Name: "France"},
+			Languages: []string{"fr-FR", "frp"},
+			Distance:  0,
+		}, actual)
+	})
+
+	t.Run("radius overrides buffer", func(t *testing.T) {
+		t.Parallel()
+
+		req := webservice.CountryCodeRequest{Position: value.Position{Latitude: 47, Longitude: -5.5}}
+
+		_, err := idx.CountryCode(context.Background(), req)
+
+		require.ErrorIs(t, err, ErrNotFound)
+
+		req.Radius = value.Ptr(40.0)
+
+		actual, err := idx.CountryCode(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, value.CountryCodeFrance, actual.Code)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		fiji, err := Build(Source{
+			Shapes:    download.FromSlice([]download.Shape{{GeoNameID: 2205218, Geometry: value.MultiPolygon{square(-19, -16, 177, 180)}}}),
+			Countries: download.FromSlice(fixture.Countries()),
+		})
+
+		require.NoError(t, err)
+
+		actual, err := fiji.CountryCode(context.Background(), webservice.CountryCodeRequest{
+			Position: value.Position{Latitude: -18, Longitude: -179.8},
+			Radius:   value.Ptr(30.0),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, value.CountryCodeFiji, actual.Code)
+		assert.InDelta(t, 21.15, actual.Distance, 0.01)
+	})
+
+	t.Run("ring across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		fiji, err := Build(Source{
+			Shapes:    download.FromSlice([]download.Shape{{GeoNameID: 2205218, Geometry: value.MultiPolygon{square(-19, -16, 178, -179)}}}),
+			Countries: download.FromSlice(fixture.Countries()),
+		})
+
+		require.NoError(t, err)
+
+		for _, lng := range []float64{178.5, 180, -179.5} {
+			actual, err := fiji.Lookup(value.Position{Latitude: -18, Longitude: lng})
+
+			require.NoError(t, err)
+			assert.Equal(t, value.CountryCodeFiji, actual.Code)
+			assert.Zero(t, actual.Distance)
+		}
+
+		actual, err := fiji.CountryCode(context.Background(), webservice.CountryCodeRequest{
+			Position: value.Position{Latitude: -18, Longitude: -178.8},
+			Radius:   value.Ptr(30.0),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, value.CountryCodeFiji, actual.Code)
+		assert.InDelta(t, 21.15, actual.Distance, 0.01)
+
+		_, err = fiji.Lookup(value.Position{Latitude: -18, Longitude: 0})
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := idx.CountryCode(ctx, webservice.CountryCodeRequest{})
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func square(south, north, west, east float64) value.Polygon {
+	return value.Polygon{{
+		{Latitude: south, Longitude: west},
+		{Latitude: south, Longitude: east},
+		{Latitude: north, Longitude: east},
+		{Latitude: north, Longitude: west},
+		{Latitude: south, Longitude: west},
+	}}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// config is read from the config file, the environment variables take precedence.
+type config struct {
+	// Username of the webservice account, GEONAMES_USERNAME
+	Username string `json:"username"`
+	// BaseURL of the webservice, e.g. of a geonames serve instance, GEONAMES_BASE_URL
+	BaseURL string `json:"baseURL"`
+	// DownloadURL of the dump files, GEONAMES_DOWNLOAD_URL
+	DownloadURL string `json:"downloadURL"`
+}
+
+// loadConfig reads the config file at the path, GEONAMES_CONFIG or the default location. Only an explicitly
+// given file has to exist.
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	var res config
+
+	path = cmp.Or(path, getenv("GEONAMES_CONFIG"))
+	explicit := path != ""
+
+	if !explicit {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "geonames", "config.json")
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+
+		switch {
+		case err == nil:
+			if err = json.Unmarshal(data, &res); err != nil {
+				return res, fmt.Errorf("parse config %s => %w", path, err)
+			}
+		case explicit || !errors.Is(err, fs.ErrNotExist):
+			return res, fmt.Errorf("read config => %w", err)
+		}
+	}
+
+	res.Username = cmp.Or(getenv("GEONAMES_USERNAME"), res.Username)
+	res.BaseURL = cmp.Or(getenv("GEONAMES_BASE_URL"), res.BaseURL)
+	res.DownloadURL = cmp.Or(getenv("GEONAMES_DOWNLOAD_URL"), res.DownloadURL)
+
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadConfig(t *testing.T) {
+	t.Parallel()
+
+	file := writeTempFile(t, "config.json", `{"username":"file","baseURL":"http://file","downloadURL":"http://dump"}`)
+
+	tests := []struct {
+		name     string
+		path     string
+		env      map[string]string
+		expected config
+	}{
+		{
+			name:     "file",
+			path:     file,
+			env:      map[string]string{},
+			expected: config{Username: "file", BaseURL: "http://file", DownloadURL: "http://dump"},
+		},
+		{
+			name:     "file from environment",
+			env:      map[string]string{"GEONAMES_CONFIG": file},
+			expected: config{Username: "file", BaseURL: "http://file", DownloadURL: "http://dump"},
+		},
+		{
+			name: "environment overrides file",
+			path: file,
+			env: map[string]string{
+				"GEONAMES_USERNAME": "env",
+				"GEONAMES_BASE_URL": "http://env",
+			},
+			expected: config{Username: "env", BaseURL: "http://env", DownloadURL: "http://dump"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := loadConfig(tt.path, func(key string) string {
+				return tt.env[key]
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := loadConfig(filepath.Join(t.TempDir(), "missing.json"), func(string) string { return "" })
+
+		require.Error(t, err)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := loadConfig(writeTempFile(t, "config.json", "{"), func(string) string { return "" })
+
+		require.ErrorContains(t, err, "parse config")
+	})
+}
+
+func Test_run_username(t *testing.T) {
+	t.Parallel()
+
+	config := writeTempFile(t, "config.json", `{"username":"file"}`)
+	fake := newWebserviceServer(t)
+
+	_, _, err := runCLI(t, map[string]string{
+		"GEONAMES_CONFIG":   config,
+		"GEONAMES_BASE_URL": fake.URL(),
+	}, "get", "1")
+	require.NoError(t, err)
+
+	_, _, err = runCLI(t, map[string]string{
+		"GEONAMES_CONFIG":   config,
+		"GEONAMES_BASE_URL": fake.URL(),
+	}, "-username", "flag", "get", "1")
+	require.NoError(t, err)
+
+	requests := fake.Requests("/getJSON")
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "file", requests[0].URL.Query().Get("username"))
+	assert.Equal(t, "flag", requests[1].URL.Query().Get("username"))
+}
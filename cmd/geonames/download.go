@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+)
+
+const (
+	formatTSV     = "tsv"
+	formatCSV     = "csv"
+	formatNDJSON  = "ndjson"
+	formatGeoJSON = "geojson"
+)
+
+const downloadSynopsis = `download [flags] <dump>
+
+Dumps:
+  allCountries, cities500, cities1000, cities5000, cities15000, noCountry, modifications or a country code
+  such as FR for places, deletes, alternateNames, alternateNamesModifications, alternateNamesDeletes,
+  admin1, admin2, admin5, countryInfo, featureCodes, hierarchy, languages, shapes, timeZones and userTags.
+  The filters and the geojson format apply to places only, the tsv of places is the format of the dump.`
+
+var errPlacesOnly = errors.New("supported by place dumps only")
+
+// placeFilter selects places of the dumps by the filter flags.
+type placeFilter struct {
+	countries     []value.CountryCode
+	featureClass  []string
+	featureCode   []string
+	minPopulation int64
+	geohash       value.Geohash
+}
+
+// register adds the filter flags.
+func (f *placeFilter) register(flags *flag.FlagSet) {
+	flags.Func("country", "comma separated country codes, e.g. FR,BE", func(s string) error {
+		f.countries = value.ParseMultipleValues[value.CountryCode](strings.ToUpper(s))
+
+		return nil
+	})
+	flags.Func("feature-class", "comma separated feature classes, e.g. P,A", func(s string) error {
+		f.featureClass = value.ParseMultipleValues[string](s)
+
+		return nil
+	})
+	flags.Func("feature-code", "comma separated feature codes, e.g. PPLC,ADM1", func(s string) error {
+		f.featureCode = value.ParseMultipleValues[string](s)
+
+		return nil
+	})
+	flags.Int64Var(&f.minPopulation, "min-population", 0, "minimal population")
+	flags.Func("geohash", "geohash cell containing the places, e.g. u09", func(s string) error {
+		f.geohash = value.Geohash(strings.ToLower(s))
+
+		return nil
+	})
+}
+
+// active reports whether any filter is set.
+func (f *placeFilter) active() bool {
+	return len(f.countries) > 0 || len(f.featureClass) > 0 || len(f.featureCode) > 0 ||
+		f.minPopulation > 0 || f.geohash != ""
+}
+
+// match reports whether the place passes all filters.
+func (f *placeFilter) match(item download.GeoName) bool {
+	switch {
+	case len(f.countries) > 0 && !slices.Contains(f.countries, item.CountryCode):
+		return false
+	case len(f.featureClass) > 0 && !slices.Contains(f.featureClass, item.FeatureClass):
+		return false
+	case len(f.featureCode) > 0 && !slices.Contains(f.featureCode, item.FeatureCode):
+		return false
+	case item.Population < f.minPopulation:
+		return false
+	case f.geohash != "" && value.EncodeGeohash(item.Position, len(f.geohash)) != f.geohash:
+		return false
+	default:
+		return true
+	}
+}
+
+func downloadCommand(ctx context.Context, a *app, args []string) error {
+	var filter placeFilter
+
+	flags := newFlagSet(a, downloadSynopsis)
+	format := flags.String("format", formatTSV, "output format, tsv, csv, ndjson or geojson")
+	output := flags.String("o", "", "output file, default is stdout")
+	language := flags.String("lang", "en", "language of featureCodes")
+	filter.register(flags)
+
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	if !slices.Contains([]string{formatTSV, formatCSV, formatNDJSON, formatGeoJSON}, *format) {
+		return fmt.Errorf("%w, unknown format %q", errUsage, *format)
+	}
+
+	client := a.download()
+	name := flags.Arg(0)
+
+	var (
+		places  download.Iterator[download.GeoName]
+		records download.Iterator[any]
+		err     error
+	)
+
+	if isPlaceDump(name) {
+		places, err = placeDump(ctx, client, name)
+	} else {
+		if filter.active() || *format == formatGeoJSON {
+			return fmt.Errorf("%w, %s is %w", errUsage, name, errPlacesOnly)
+		}
+
+		records, err = recordDump(ctx, client, name, *language)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.writeOutput(*output, func(w io.Writer) (int, error) {
+		if places != nil {
+			return writePlaces(w, *format, download.Filter(places, filter.match))
+		}
+
+		return writeRecords(w, *format, records)
+	})
+}
+
+// isPlaceDump reports whether the dump contains places, country dumps are named by their code.
+func isPlaceDump(name string) bool {
+	switch name {
+	case "allCountries", value.Cities500, value.Cities1000, value.Cities5000, value.Cities15000,
+		"noCountry", "no-country", "modifications":
+		return true
+	default:
+		return len(name) == 2 && strings.ToUpper(name) == name
+	}
+}
+
+// placeDump returns the places of the dump.
+func placeDump(ctx context.Context, client *download.Client, name string) (download.Iterator[download.GeoName], error) {
+	var (
+		res download.Iterator[download.GeoName]
+		err error
+	)
+
+	switch name {
+	case "allCountries":
+		res, err = client.AllCountries(ctx)
+	case value.Cities500, value.Cities1000, value.Cities5000, value.Cities15000:
+		res, err = client.Cities(ctx, value.Cities(name))
+	case "noCountry", "no-country":
+		res, err = client.NoCountry(ctx)
+	case "modifications":
+		res, err = client.Modifications(ctx)
+	default:
+		res, err = client.ByCountry(ctx, value.CountryCode(name))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("download %s => %w", name, err)
+	}
+
+	return res, nil
+}
+
+// recordDump returns the records of the dumps other than places.
+func recordDump(ctx context.Context, client *download.Client, name, language string) (download.Iterator[any], error) {
+	var (
+		res download.Iterator[any]
+		err error
+	)
+
+	switch name {
+	case "deletes":
+		res, err = records(client.Deletes(ctx))
+	case "alternateNames":
+		res, err = records(client.AlternateNames(ctx))
+	case "alternateNamesModifications":
+		res, err = records(client.AlternateNamesModifications(ctx))
+	case "alternateNamesDeletes":
+		res, err = records(client.AlternateNamesDeletes(ctx))
+	case "admin1":
+		res, err = records(client.AdminDivisionFirst(ctx))
+	case "admin2":
+		res, err = records(client.AdminDivisionSecond(ctx))
+	case "admin5":
+		res, err = records(client.AdminDivisionFifth(ctx))
+	case "countryInfo":
+		res, err = records(client.CountryInfo(ctx))
+	case "featureCodes":
+		res, err = records(client.FeatureCodes(ctx, language))
+	case "hierarchy":
+		res, err = records(client.Hierarchy(ctx))
+	case "languages":
+		res, err = records(client.Languages(ctx))
+	case "shapes":
+		res, err = records(client.Shapes(ctx))
+	case "timeZones":
+		res, err = records(client.TimeZones(ctx))
+	case "userTags":
+		res, err = records(client.UserTags(ctx))
+	default:
+		return nil, fmt.Errorf("%w, unknown dump %q", errUsage, name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("download %s => %w", name, err)
+	}
+
+	return res, nil
+}
+
+// records returns the items as an iterator of any.
+func records[T any](items download.Iterator[T], err error) (download.Iterator[any], error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(any, error) bool) {
+		for item, err := range items {
+			if !yield(item, err) {
+				return
+			}
+		}
+	}, nil
+}
+
+// writeOutput runs write on stdout or the output file, which is only replaced once write succeeded.
+// The number of records written to a file is printed to stderr.
+func (a *app) writeOutput(path string, write func(w io.Writer) (int, error)) error {
+	if path == "" {
+		_, err := write(a.stdout)
+
+		return err
+	}
+
+	var count int
+
+	err := writeFile(path, func(w io.Writer) error {
+		var err error
+
+		count, err = write(w)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(a.stderr, "wrote %d records to %s\n", count, path)
+
+	return nil
+}
+
+// writeFile writes to a temporary file in the directory of path which is renamed to path on success.
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create file => %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	if err = write(file); err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("write file => %w", err)
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("rename file => %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+)
+
+const (
+	placeParis  = "2988507\tParis\tParis\tLutece,Paname\t48.85341\t2.3488\tP\tPPLC\tFR\t\t11\t75\t\t\t2138551\t\t42\tEurope/Paris\t2024-01-01"
+	placeLondon = "2643743\tLondon\tLondon\t\t51.50853\t-0.12574\tP\tPPLC\tGB\t\tENG\tGLA\t\t\t8961989\t\t25\tEurope/London\t2024-01-02"
+	placeBerlin = "2950159\tBerlin\tBerlin\t\t52.52437\t13.41053\tP\tPPLC\tDE\t\t16\t00\t11000\t11000000\t3426354\t74\t43\tEurope/Berlin\t2024-01-03"
+	placeLyon   = "2996944\tLyon\tLyon\t\t45.74846\t4.84671\tP\tPPLA\tFR\t\t84\t69\t\t\t522969\t\t171\tEurope/Paris\t2024-02-01"
+	placeLeeds  = "2644688\tLeeds\tLeeds\t\t53.79648\t-1.54785\tP\tPPL\tGB\t\tENG\tJ9\t\t\t455123\t\t44\tEurope/London\t2024-02-01"
+	// placeBerlinModified has a new population
+	placeBerlinModified = "2950159\tBerlin\tBerlin\t\t52.52437\t13.41053\tP\tPPLC\tDE\t\t16\t00\t11000\t11000000\t3600000\t74\t43\tEurope/Berlin\t2024-02-01"
+)
+
+var places = strings.Join([]string{placeParis, placeLondon, placeBerlin}, "\n") + "\n"
+
+// newDownloadServer serves cities15000 with Paris, London and Berlin, the modifications of yesterday updating
+// Berlin and adding Lyon and Leeds, the deletes of yesterday removing London, and the files loaded by serve.
+func newDownloadServer(t *testing.T) *testutil.FakeServer {
+	t.Helper()
+
+	date := time.Now().Add(-24 * time.Hour).Format(time.DateOnly)
+
+	fake := testutil.NewFakeServer(t)
+	fake.Handle("/cities15000.zip", testutil.FileResponse(zipFile(t, "cities15000.txt", places)))
+	fake.Handle("/FR.zip", testutil.FileResponse(zipFile(t, "FR.txt", placeParis+"\n"+placeLyon+"\n")))
+	fake.Handle("/modifications-"+date+".txt", testutil.FileResponse([]byte(
+		strings.Join([]string{placeBerlinModified, placeLyon, placeLeeds}, "\n")+"\n",
+	)))
+	fake.Handle("/deletes-"+date+".txt", testutil.FileResponse([]byte("2643743\tLondon\tduplicate\n")))
+	fake.Handle("/admin1CodesASCII.txt", testutil.FileResponse([]byte(
+		"FR.11\tÎle-de-France\tIle-de-France\t3012874\nGB.ENG\tEngland\tEngland\t6269131\n",
+	)))
+	fake.Handle("/admin2Codes.txt", testutil.FileResponse([]byte("FR.11.75\tParis\tParis\t2968815\n")))
+	fake.Handle("/countryInfo.txt", testutil.FileResponse([]byte(
+		"#ISO\tISO3\tISO-Numeric\tfips\tCountry\tCapital\tArea(in sq km)\tPopulation\tContinent\ttld\t"+
+			"CurrencyCode\tCurrencyName\tPhone\tPostal Code Format\tPostal Code Regex\tLanguages\tgeonameid\t"+
+			"neighbours\tEquivalentFipsCode\n"+
+			"FR\tFRA\t250\tFR\tFrance\tParis\t547030\t66987244\tEU\t.fr\tEUR\tEuro\t33\t#####\t^(\\d{5})$\t"+
+			"fr-FR\t3017382\tCH,DE,BE\t\n",
+	)))
+	fake.Handle("/featureCodes_en.txt", testutil.FileResponse([]byte(
+		"P.PPLC\tcapital of a political entity\t\nP.PPLA\tseat of a first-order administrative division\t\n",
+	)))
+
+	return fake
+}
+
+func zipFile(t *testing.T, name, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	archive := zip.NewWriter(&buf)
+
+	w, err := archive.Create(name)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
+
+func Test_downloadCommand(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "tsv",
+			args:     []string{"download", "cities15000"},
+			expected: places,
+		},
+		{
+			name:     "country",
+			args:     []string{"download", "FR"},
+			expected: placeParis + "\n" + placeLyon + "\n",
+		},
+		{
+			name:     "filters",
+			args:     []string{"download", "-country", "fr,de", "-min-population", "3000000", "cities15000"},
+			expected: placeBerlin + "\n",
+		},
+		{
+			name:     "geohash filter",
+			args:     []string{"download", "-geohash", "u09", "cities15000"},
+			expected: placeParis + "\n",
+		},
+		{
+			name: "csv",
+			args: []string{"download", "-format", "csv", "-feature-class", "P", "-country", "GB", "cities15000"},
+			expected: "ID,Name,NameASCII,AlternateNames,Position.Latitude,Position.Longitude,FeatureClass," +
+				"FeatureCode,CountryCode,AlternateCountryCodes,AdminCode.First,AdminCode.Second,AdminCode.Third," +
+				"AdminCode.Fourth,AdminCode.Fifth,Population,Elevation,DigitalElevationModel,Timezone," +
+				"ModificationDate\n" +
+				"2643743,London,London,,51.50853,-0.12574,P,PPLC,GB,,ENG,GLA,,,,8961989,0,25,Europe/London,2024-01-02\n",
+		},
+		{
+			name:     "records",
+			args:     []string{"download", "deletes"},
+			expected: "2643743\tLondon\tduplicate\n",
+		},
+		{
+			name:     "records csv",
+			args:     []string{"download", "-format", "csv", "admin2"},
+			expected: "ID,Code,Name,NameASCII\n2968815,FR.11.75,Paris,Paris\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCLI(t, map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()}, tt.args...)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout)
+		})
+	}
+}
+
+func Test_downloadCommand_json(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+	env := map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()}
+
+	t.Run("ndjson", func(t *testing.T) {
+		t.Parallel()
+
+		stdout, _, err := runCLI(t, env, "download", "-format", "ndjson", "cities15000")
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		require.Len(t, lines, 3)
+
+		var actual map[string]any
+
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &actual))
+		assert.Equal(t, "London", actual["Name"])
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		t.Parallel()
+
+		stdout, _, err := runCLI(t, env, "download", "-format", "geojson", "-country", "FR", "cities15000")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"FeatureCollection","features":[{"type":"Feature","id":2988507,`+
+			`"geometry":{"type":"Point","coordinates":[2.3488,48.85341]},"properties":{"name":"Paris",`+
+			`"asciiName":"Paris","alternateNames":["Lutece","Paname"],"featureClass":"P","featureCode":"PPLC",`+
+			`"countryCode":"FR","admin1Code":"11","admin2Code":"75","population":2138551,"dem":42,`+
+			`"timezone":"Europe/Paris","modificationDate":"2024-01-01"}}]}`, stdout)
+	})
+}
+
+func Test_downloadCommand_file(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+	path := filepath.Join(t.TempDir(), "cities.tsv")
+
+	_, stderr, err := runCLI(t, map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()},
+		"download", "-o", path, "cities15000")
+	require.NoError(t, err)
+	assert.Equal(t, "wrote 3 records to "+path+"\n", stderr)
+
+	actual, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, places, string(actual))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_downloadCommand_errors(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+	env := map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown dump", args: []string{"download", "foo"}},
+		{name: "unknown format", args: []string{"download", "-format", "xml", "cities15000"}},
+		{name: "filter of records", args: []string{"download", "-country", "FR", "deletes"}},
+		{name: "geojson of records", args: []string{"download", "-format", "geojson", "deletes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := runCLI(t, env, tt.args...)
+
+			require.ErrorIs(t, err, errUsage)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "out.tsv")
+
+		_, _, err := runCLI(t, env, "download", "-o", path, "cities500")
+
+		require.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/platx/geonames/download"
+)
+
+// geoJSONFeature is a place of a GeoJSON feature collection.
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	ID         uint64            `json:"id"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Name                  string   `json:"name"`
+	ASCIIName             string   `json:"asciiName"`
+	AlternateNames        []string `json:"alternateNames,omitempty"`
+	FeatureClass          string   `json:"featureClass"`
+	FeatureCode           string   `json:"featureCode"`
+	CountryCode           string   `json:"countryCode"`
+	AlternateCountryCodes []string `json:"alternateCountryCodes,omitempty"`
+	Admin1Code            string   `json:"admin1Code,omitempty"`
+	Admin2Code            string   `json:"admin2Code,omitempty"`
+	Admin3Code            string   `json:"admin3Code,omitempty"`
+	Admin4Code            string   `json:"admin4Code,omitempty"`
+	Population            int64    `json:"population"`
+	Elevation             int64    `json:"elevation,omitempty"`
+	DEM                   int64    `json:"dem"`
+	Timezone              string   `json:"timezone"`
+	ModificationDate      string   `json:"modificationDate"`
+}
+
+// placeRow returns the place as a row of the dump format.
+func placeRow(item download.GeoName) []string {
+	elevation := ""
+	if item.Elevation != 0 {
+		elevation = strconv.FormatInt(item.Elevation, 10)
+	}
+
+	countryCodes := make([]string, 0, len(item.AlternateCountryCodes))
+	for _, code := range item.AlternateCountryCodes {
+		countryCodes = append(countryCodes, string(code))
+	}
+
+	return []string{
+		strconv.FormatUint(item.ID, 10),
+		item.Name,
+		item.NameASCII,
+		strings.Join(item.AlternateNames, ","),
+		formatFloat(item.Position.Latitude),
+		formatFloat(item.Position.Longitude),
+		item.FeatureClass,
+		item.FeatureCode,
+		string(item.CountryCode),
+		strings.Join(countryCodes, ","),
+		item.AdminCode.First,
+		item.AdminCode.Second,
+		item.AdminCode.Third,
+		item.AdminCode.Fourth,
+		strconv.FormatInt(item.Population, 10),
+		elevation,
+		strconv.FormatInt(item.DigitalElevationModel, 10),
+		item.Timezone,
+		formatDate(item.ModificationDate),
+	}
+}
+
+// writePlaces writes the places in the format and returns their number, tsv is the format of the dump.
+func writePlaces(w io.Writer, format string, items download.Iterator[download.GeoName]) (int, error) {
+	if format == formatGeoJSON {
+		return writeGeoJSON(w, items)
+	}
+
+	return writeItems(w, format, items, placeRow)
+}
+
+// writeRecords writes the records in the format and returns their number.
+func writeRecords(w io.Writer, format string, items download.Iterator[any]) (int, error) {
+	return writeItems(w, format, items, func(item any) []string {
+		_, values := flatten(item)
+
+		return values
+	})
+}
+
+// writeItems writes the items as tsv rows, csv rows with a header or JSON lines.
+func writeItems[T any](w io.Writer, format string, items download.Iterator[T], row func(T) []string) (int, error) {
+	var (
+		count int
+		err   error
+	)
+
+	buf := bufio.NewWriter(w)
+	csvWriter := csv.NewWriter(buf)
+	encoder := json.NewEncoder(buf)
+
+	for item, itemErr := range items {
+		if itemErr != nil {
+			return count, fmt.Errorf("read record %d => %w", count+1, itemErr)
+		}
+
+		switch format {
+		case formatTSV:
+			_, err = buf.WriteString(strings.Join(row(item), "\t") + "\n")
+		case formatCSV:
+			names, values := flatten(item)
+			if count == 0 {
+				err = csvWriter.Write(names)
+			}
+
+			if err == nil {
+				err = csvWriter.Write(values)
+			}
+		default:
+			err = encoder.Encode(item)
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("write record => %w", err)
+		}
+
+		count++
+	}
+
+	csvWriter.Flush()
+
+	if err = csvWriter.Error(); err == nil {
+		err = buf.Flush()
+	}
+
+	if err != nil {
+		return count, fmt.Errorf("write records => %w", err)
+	}
+
+	return count, nil
+}
+
+// writeGeoJSON streams the places as a feature collection of points.
+func writeGeoJSON(w io.Writer, items download.Iterator[download.GeoName]) (int, error) {
+	var count int
+
+	buf := bufio.NewWriter(w)
+
+	if _, err := buf.WriteString(`{"type":"FeatureCollection","features":[`); err != nil {
+		return count, fmt.Errorf("write header => %w", err)
+	}
+
+	for item, err := range items {
+		if err != nil {
+			return count, fmt.Errorf("read record %d => %w", count+1, err)
+		}
+
+		if count > 0 {
+			_ = buf.WriteByte(',')
+		}
+
+		data, err := json.Marshal(newGeoJSONFeature(item))
+		if err != nil {
+			return count, fmt.Errorf("encode record => %w", err)
+		}
+
+		if _, err = buf.Write(data); err != nil {
+			return count, fmt.Errorf("write record => %w", err)
+		}
+
+		count++
+	}
+
+	if _, err := buf.WriteString("]}\n"); err != nil {
+		return count, fmt.Errorf("write footer => %w", err)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return count, fmt.Errorf("write records => %w", err)
+	}
+
+	return count, nil
+}
+
+func newGeoJSONFeature(item download.GeoName) geoJSONFeature {
+	countryCodes := make([]string, 0, len(item.AlternateCountryCodes))
+	for _, code := range item.AlternateCountryCodes {
+		countryCodes = append(countryCodes, string(code))
+	}
+
+	return geoJSONFeature{
+		Type: "Feature",
+		ID:   item.ID,
+		Geometry: geoJSONPoint{
+			Type:        "Point",
+			Coordinates: [2]float64{item.Position.Longitude, item.Position.Latitude},
+		},
+		Properties: geoJSONProperties{
+			Name:                  item.Name,
+			ASCIIName:             item.NameASCII,
+			AlternateNames:        item.AlternateNames,
+			FeatureClass:          item.FeatureClass,
+			FeatureCode:           item.FeatureCode,
+			CountryCode:           string(item.CountryCode),
+			AlternateCountryCodes: countryCodes,
+			Admin1Code:            item.AdminCode.First,
+			Admin2Code:            item.AdminCode.Second,
+			Admin3Code:            item.AdminCode.Third,
+			Admin4Code:            item.AdminCode.Fourth,
+			Population:            item.Population,
+			Elevation:             item.Elevation,
+			DEM:                   item.DigitalElevationModel,
+			Timezone:              item.Timezone,
+			ModificationDate:      formatDate(item.ModificationDate),
+		},
+	}
+}
+
+// flatten returns the exported fields of the struct as columns, nested structs are named by their path,
+// e.g. Position.Latitude, lists of scalars are comma separated and other lists are JSON encoded.
+func flatten(v any) ([]string, []string) {
+	var names, values []string
+
+	flattenValue("", reflect.ValueOf(v), &names, &values)
+
+	return names, values
+}
+
+func flattenValue(name string, v reflect.Value, names, values *[]string) {
+	if t, ok := v.Interface().(time.Time); ok {
+		*names = append(*names, name)
+		*values = append(*values, formatDate(t))
+
+		return
+	}
+
+	if v.Kind() == reflect.Struct {
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldName := field.Name
+			if name != "" {
+				fieldName = name + "." + fieldName
+			}
+
+			flattenValue(fieldName, v.Field(i), names, values)
+		}
+
+		return
+	}
+
+	*names = append(*names, name)
+	*values = append(*values, formatValue(v))
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+			items := make([]string, 0, v.Len())
+			for i := range v.Len() {
+				items = append(items, fmt.Sprint(v.Index(i).Interface()))
+			}
+
+			return strings.Join(items, ",")
+		default:
+			data, err := json.Marshal(v.Interface())
+			if err != nil {
+				return ""
+			}
+
+			return string(data)
+		}
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.DateOnly)
+}
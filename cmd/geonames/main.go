@@ -0,0 +1,135 @@
+// Command geonames queries the GeoNames webservice, downloads and converts the dump files, keeps downloaded
+// files up to date with the daily modifications and serves them as a local webservice.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: geonames [-config file] [-username name] [-json] [-verbose] <command> [flags] [args]
+
+Commands:
+  search     search places, e.g. geonames search -country FR paris
+  get        get a place by its geonameId, e.g. geonames get 2988507
+  nearby     find the nearest places of any kind, e.g. geonames nearby 48.86 2.35
+  reverse    find the nearest populated places, e.g. geonames reverse 48.86 2.35
+  timezone   get the timezone with sunrise and sunset, e.g. geonames timezone 48.86 2.35
+  elevation  get the elevation in meters, e.g. geonames elevation -model srtm1 45.83 6.86
+  download   download a dump file as tsv, csv, ndjson or geojson, e.g. geonames download -format csv cities15000
+  sync       apply the daily modifications and deletes to a downloaded tsv file
+  serve      serve the webservice JSON endpoints from the dump files
+
+The username is taken from -username, the GEONAMES_USERNAME variable or the config file, which defaults to
+geonames/config.json in the user config directory, e.g. {"username": "demo"}. Run geonames <command> -h
+for the flags of a command. -verbose logs every request to stderr.
+`
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+var (
+	errUsage           = errors.New("invalid usage")
+	errMissingUsername = errors.New("missing username, set -username, GEONAMES_USERNAME or the config file")
+)
+
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]command{
+	"search":    searchCommand,
+	"get":       getCommand,
+	"nearby":    nearbyCommand,
+	"reverse":   reverseCommand,
+	"timezone":  timezoneCommand,
+	"elevation": elevationCommand,
+	"download":  downloadCommand,
+	"sync":      syncCommand,
+	"serve":     serveCommand,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+
+	stop()
+
+	if code := exitCode(err); code != 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "geonames:", err)
+
+		os.Exit(code)
+	}
+}
+
+// run parses the global flags and runs the command, getenv is used for the environment variables.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) error {
+	flags := flag.NewFlagSet("geonames", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(stderr, usage)
+	}
+
+	configPath := flags.String("config", "", "config file, default is GEONAMES_CONFIG or geonames/config.json")
+	username := flags.String("username", "", "webservice username, default is GEONAMES_USERNAME or the config file")
+	asJSON := flags.Bool("json", false, "print JSON instead of tables")
+	verbose := flags.Bool("verbose", false, "log every request to stderr")
+
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+
+		return errUsage
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		return fmt.Errorf("%w, unknown command %q", errUsage, flags.Arg(0))
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		return err
+	}
+
+	if *username != "" {
+		cfg.Username = *username
+	}
+
+	return cmd(
+		ctx,
+		&app{stdout: stdout, stderr: stderr, config: cfg, json: *asJSON, verbose: *verbose},
+		flags.Args()[1:],
+	)
+}
+
+// exitCode returns 0 for no error and help requests, 2 for usage errors and 1 otherwise.
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return exitUsage
+	default:
+		return exitFailure
+	}
+}
+
+// usageError marks flag parsing errors as usage errors, help requests are passed as is.
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return fmt.Errorf("%w => %w", errUsage, err)
+}
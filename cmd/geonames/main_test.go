@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCLI runs the command line with the environment, an empty config file is used unless GEONAMES_CONFIG is set.
+func runCLI(t *testing.T, env map[string]string, args ...string) (string, string, error) {
+	t.Helper()
+
+	env = maps.Clone(env)
+	if _, ok := env["GEONAMES_CONFIG"]; !ok {
+		env["GEONAMES_CONFIG"] = writeTempFile(t, "config.json", "{}")
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), args, &stdout, &stderr, func(key string) string {
+		return env[key]
+	})
+
+	return stdout.String(), stderr.String(), err
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stderr   string
+	}{
+		{name: "no command", args: nil, exitCode: exitUsage, stderr: "Usage: geonames"},
+		{name: "unknown command", args: []string{"foo"}, exitCode: exitUsage},
+		{name: "unknown flag", args: []string{"-foo", "search"}, exitCode: exitUsage},
+		{name: "help", args: []string{"-h"}, exitCode: 0, stderr: "Commands:"},
+		{name: "command help", args: []string{"nearby", "-h"}, exitCode: 0, stderr: "-max-rows"},
+		{name: "missing arguments", args: []string{"nearby", "1.5"}, exitCode: exitUsage},
+		{name: "invalid position", args: []string{"nearby", "foo", "1.5"}, exitCode: exitUsage},
+		{name: "invalid flag value", args: []string{"search", "-max-rows", "-1", "foo"}, exitCode: exitUsage},
+		{name: "missing username", args: []string{"get", "1"}, exitCode: exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, stderr, err := runCLI(t, map[string]string{}, tt.args...)
+
+			assert.Equal(t, tt.exitCode, exitCode(err))
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+}
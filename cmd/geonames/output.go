@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/middleware"
+	"github.com/platx/geonames/webservice"
+)
+
+// app is the state shared by all commands.
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	config config
+	// json prints JSON instead of tables
+	json bool
+	// verbose logs every request to stderr
+	verbose bool
+}
+
+// webservice returns a client of the configured webservice, the username is required.
+func (a *app) webservice() (*webservice.Client, error) {
+	if a.config.Username == "" {
+		return nil, errMissingUsername
+	}
+
+	var opts []webservice.Option
+
+	if a.config.BaseURL != "" {
+		opts = append(opts, webservice.WithBaseURL(a.config.BaseURL))
+	}
+
+	if a.verbose {
+		opts = append(opts, webservice.WithMiddleware(a.logging()))
+	}
+
+	return webservice.NewClient(a.config.Username, opts...), nil
+}
+
+// download returns a client of the configured download server.
+func (a *app) download() *download.Client {
+	var opts []download.Option
+
+	if a.config.DownloadURL != "" {
+		opts = append(opts, download.WithBaseURL(a.config.DownloadURL))
+	}
+
+	if a.verbose {
+		opts = append(opts, download.WithMiddleware(a.logging()))
+	}
+
+	return download.NewClient(opts...)
+}
+
+// logging returns the middleware logging every request to stderr.
+func (a *app) logging() middleware.Middleware {
+	handler := slog.NewTextHandler(a.stderr, &slog.HandlerOptions{
+		AddSource:   false,
+		Level:       slog.LevelDebug,
+		ReplaceAttr: nil,
+	})
+
+	return middleware.Logging(slog.New(handler))
+}
+
+// table is a result printed as tab aligned columns.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// print writes the value as indented JSON or the table.
+func (a *app) print(v any, t table) error {
+	if a.json {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encode JSON => %w", err)
+		}
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return fmt.Errorf("write table => %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write table => %w", err)
+	}
+
+	return nil
+}
+
+// geoNamesTable returns the table of webservice places, with a distance column for nearby places.
+func geoNamesTable(items []webservice.GeoName, distances []float64) table {
+	res := table{
+		header: []string{"ID", "NAME", "COUNTRY", "FEATURE", "LATITUDE", "LONGITUDE", "POPULATION"},
+		rows:   make([][]string, 0, len(items)),
+	}
+
+	if distances != nil {
+		res.header = append(res.header, "DISTANCE")
+	}
+
+	for i, item := range items {
+		row := []string{
+			strconv.FormatUint(item.ID, 10),
+			item.Name,
+			string(item.Country.Code),
+			item.Feature.Class + "." + item.Feature.Code,
+			formatFloat(item.Position.Latitude),
+			formatFloat(item.Position.Longitude),
+			strconv.FormatUint(item.Population, 10),
+		}
+
+		if distances != nil {
+			row = append(row, formatFloat(distances[i]))
+		}
+
+		res.rows = append(res.rows, row)
+	}
+
+	return res
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/server"
+	"github.com/platx/geonames/value"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// serveOptions selects the dump files the server is built from.
+type serveOptions struct {
+	// places dump name, ignored when file is set
+	places string
+	// file of places written by geonames download
+	file           string
+	language       string
+	alternateNames bool
+	shapes         bool
+	hierarchy      bool
+}
+
+func serveCommand(ctx context.Context, a *app, args []string) error {
+	var opts serveOptions
+
+	flags := newFlagSet(a, `serve [flags]
+
+Serves searchJSON, getJSON, findNearbyPlaceNameJSON, findNearbyJSON, hierarchyJSON, childrenJSON, siblingsJSON,
+countryInfoJSON, countryCodeJSON and timezoneJSON, use GEONAMES_BASE_URL to query it with geonames.`)
+	addr := flags.String("addr", "localhost:8080", "listen address")
+	flags.StringVar(&opts.places, "places", value.Cities15000, "places dump, e.g. allCountries, cities500 or FR")
+	flags.StringVar(&opts.file, "file", "", "tsv file of places written by geonames download instead of a dump")
+	flags.StringVar(&opts.language, "lang", "en", "language of the feature code names")
+	flags.BoolVar(&opts.alternateNames, "alternate-names", false, "load alternateNamesV2 for localized names")
+	flags.BoolVar(&opts.shapes, "shapes", false, "load the country shapes for countryCodeJSON")
+	flags.BoolVar(&opts.hierarchy, "hierarchy", false, "load hierarchy instead of deriving it from admin codes")
+
+	if err := parseArgs(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	if opts.file == "" && !isPlaceDump(opts.places) {
+		return fmt.Errorf("%w, %s is not a places dump", errUsage, opts.places)
+	}
+
+	handler, err := buildServer(ctx, a.download(), opts)
+	if err != nil {
+		return err
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listen => %w", err)
+	}
+
+	_, _ = fmt.Fprintf(a.stderr, "listening on http://%s\n", listener.Addr())
+
+	return serve(ctx, listener, handler)
+}
+
+// buildServer downloads the dump files and builds the server.
+func buildServer(ctx context.Context, client *download.Client, opts serveOptions) (*server.Server, error) {
+	var (
+		src server.Source
+		err error
+	)
+
+	if opts.file != "" {
+		src.Places = readPlaces(opts.file)
+	} else if src.Places, err = placeDump(ctx, client, opts.places); err != nil {
+		return nil, err
+	}
+
+	if src.AdminDivisionFirst, err = client.AdminDivisionFirst(ctx); err != nil {
+		return nil, fmt.Errorf("download admin1 => %w", err)
+	}
+
+	if src.AdminDivisionSecond, err = client.AdminDivisionSecond(ctx); err != nil {
+		return nil, fmt.Errorf("download admin2 => %w", err)
+	}
+
+	if src.Countries, err = client.CountryInfo(ctx); err != nil {
+		return nil, fmt.Errorf("download countryInfo => %w", err)
+	}
+
+	if src.Features, err = client.FeatureCodes(ctx, opts.language); err != nil {
+		return nil, fmt.Errorf("download featureCodes => %w", err)
+	}
+
+	if opts.alternateNames {
+		if src.AlternateNames, err = client.AlternateNames(ctx); err != nil {
+			return nil, fmt.Errorf("download alternateNames => %w", err)
+		}
+	}
+
+	if opts.shapes {
+		if src.Shapes, err = client.Shapes(ctx); err != nil {
+			return nil, fmt.Errorf("download shapes => %w", err)
+		}
+	}
+
+	if opts.hierarchy {
+		if src.Hierarchy, err = client.Hierarchy(ctx); err != nil {
+			return nil, fmt.Errorf("download hierarchy => %w", err)
+		}
+	}
+
+	res, err := server.Build(src)
+	if err != nil {
+		return nil, fmt.Errorf("build server => %w", err)
+	}
+
+	return res, nil
+}
+
+// serve serves the handler until the context is done, then shuts the server down gracefully.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	srv := new(http.Server)
+	srv.Handler = handler
+	srv.ReadHeaderTimeout = readHeaderTimeout
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("serve => %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown => %w", err)
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve => %w", err)
+	}
+
+	return nil
+}
+
+// readPlaces returns the places of a tsv file in the format of the dump.
+func readPlaces(path string) download.Iterator[download.GeoName] {
+	return func(yield func(download.GeoName, error) bool) {
+		file, err := os.Open(path)
+		if err != nil {
+			yield(download.GeoName{}, fmt.Errorf("open file => %w", err))
+
+			return
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			var item download.GeoName
+
+			err = item.UnmarshalRow(strings.Split(line, "\t"))
+			if !yield(item, err) {
+				return
+			}
+		}
+
+		if err = scanner.Err(); err != nil {
+			yield(download.GeoName{}, fmt.Errorf("read file => %w", err))
+		}
+	}
+}
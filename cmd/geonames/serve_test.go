@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func Test_buildServer(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+	client := download.NewClient(download.WithBaseURL(fake.URL()))
+
+	tests := []struct {
+		name     string
+		opts     serveOptions
+		expected []string
+	}{
+		{
+			name:     "dump",
+			opts:     serveOptions{places: "cities15000", language: "en"},
+			expected: []string{"Paris"},
+		},
+		{
+			name:     "file",
+			opts:     serveOptions{file: writeTempFile(t, "places.tsv", placeLyon+"\n"), language: "en"},
+			expected: []string{"Lyon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := buildServer(context.Background(), client, tt.opts)
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			places, err := webservice.NewClient("demo", webservice.WithBaseURL(srv.URL)).Search(
+				context.Background(),
+				webservice.SearchRequest{Country: []value.CountryCode{value.CountryCodeFrance}},
+			)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(places))
+			for _, place := range places {
+				names = append(names, place.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := buildServer(context.Background(), client, serveOptions{file: "missing.tsv", language: "en"})
+
+		require.ErrorContains(t, err, "open file")
+	})
+
+	t.Run("missing dump", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := buildServer(context.Background(), client, serveOptions{places: "cities500", language: "en"})
+
+		require.ErrorContains(t, err, "download cities500")
+	})
+}
+
+func Test_serve(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- serve(ctx, listener, http.NotFoundHandler())
+	}()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	cancel()
+
+	select {
+	case err = <-errs:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("serve did not return after the context was done")
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/platx/geonames/download"
+)
+
+const maxLineLength = 16 << 20
+
+// syncResult counts the changes applied to the local file.
+type syncResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+	Total   int `json:"total"`
+}
+
+func syncCommand(ctx context.Context, a *app, args []string) error {
+	var filter placeFilter
+
+	flags := newFlagSet(a, `sync [flags] <file>
+
+Applies the modifications and deletes of yesterday to a tsv file of places written by geonames download,
+places added by the modifications are kept only when they pass the filters.`)
+	filter.register(flags)
+
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	client := a.download()
+
+	modifications, err := client.Modifications(ctx)
+	if err != nil {
+		return fmt.Errorf("download modifications => %w", err)
+	}
+
+	deletes, err := client.Deletes(ctx)
+	if err != nil {
+		return fmt.Errorf("download deletes => %w", err)
+	}
+
+	res, err := applyChanges(lines, modifications, deletes, filter.match)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(path, func(w io.Writer) error {
+		buf := bufio.NewWriter(w)
+
+		for _, line := range lines.rows {
+			if line == "" {
+				continue
+			}
+
+			if _, err := buf.WriteString(line + "\n"); err != nil {
+				return fmt.Errorf("write file => %w", err)
+			}
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("write file => %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(res, table{
+		header: []string{"ADDED", "UPDATED", "DELETED", "TOTAL"},
+		rows: [][]string{{
+			strconv.Itoa(res.Added),
+			strconv.Itoa(res.Updated),
+			strconv.Itoa(res.Deleted),
+			strconv.Itoa(res.Total),
+		}},
+	})
+}
+
+// placeLines are the rows of a local file, unchanged rows are written back as read.
+type placeLines struct {
+	rows []string
+	// index of the row by geonameId
+	index map[uint64]int
+}
+
+// readLines reads the rows of the tsv file of places.
+func readLines(path string) (*placeLines, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file => %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	res := &placeLines{rows: nil, index: make(map[uint64]int)}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rawID, _, _ := strings.Cut(line, "\t")
+
+		id, err := strconv.ParseUint(rawID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse ID of line %d => %w", len(res.rows)+1, err)
+		}
+
+		res.index[id] = len(res.rows)
+		res.rows = append(res.rows, line)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read file => %w", err)
+	}
+
+	return res, nil
+}
+
+// applyChanges replaces the modified rows, appends the added ones and clears the deleted ones. Modified rows
+// which no longer match are deleted.
+func applyChanges(
+	lines *placeLines,
+	modifications download.Iterator[download.GeoName],
+	deletes download.Iterator[download.GeoNameDeleted],
+	match func(item download.GeoName) bool,
+) (syncResult, error) {
+	res := syncResult{Added: 0, Updated: 0, Deleted: 0, Total: 0}
+
+	remove := func(id uint64) {
+		if idx, ok := lines.index[id]; ok {
+			lines.rows[idx] = ""
+			delete(lines.index, id)
+			res.Deleted++
+		}
+	}
+
+	for item, err := range modifications {
+		if err != nil {
+			return res, fmt.Errorf("read modifications => %w", err)
+		}
+
+		idx, ok := lines.index[item.ID]
+
+		switch {
+		case !match(item):
+			remove(item.ID)
+		case ok:
+			lines.rows[idx] = strings.Join(placeRow(item), "\t")
+			res.Updated++
+		default:
+			lines.index[item.ID] = len(lines.rows)
+			lines.rows = append(lines.rows, strings.Join(placeRow(item), "\t"))
+			res.Added++
+		}
+	}
+
+	for item, err := range deletes {
+		if err != nil {
+			return res, fmt.Errorf("read deletes => %w", err)
+		}
+
+		remove(item.ID)
+	}
+
+	res.Total = len(lines.index)
+
+	return res, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_syncCommand(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		result   syncResult
+	}{
+		{
+			name:     "all",
+			args:     nil,
+			expected: placeParis + "\n" + placeBerlinModified + "\n" + placeLyon + "\n" + placeLeeds + "\n",
+			result:   syncResult{Added: 2, Updated: 1, Deleted: 1, Total: 4},
+		},
+		{
+			name:     "filtered",
+			args:     []string{"-country", "FR,DE"},
+			expected: placeParis + "\n" + placeBerlinModified + "\n" + placeLyon + "\n",
+			result:   syncResult{Added: 1, Updated: 1, Deleted: 1, Total: 3},
+		},
+		{
+			name:     "modified place no longer matching",
+			args:     []string{"-min-population", "3500000"},
+			expected: placeParis + "\n" + placeBerlinModified + "\n",
+			result:   syncResult{Added: 0, Updated: 1, Deleted: 1, Total: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeTempFile(t, "places.tsv", places)
+			args := append(append([]string{"-json", "sync"}, tt.args...), path)
+
+			stdout, _, err := runCLI(t, map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()}, args...)
+			require.NoError(t, err)
+
+			var result syncResult
+
+			require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+			assert.Equal(t, tt.result, result)
+
+			actual, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func Test_syncCommand_errors(t *testing.T) {
+	t.Parallel()
+
+	fake := newDownloadServer(t)
+	env := map[string]string{"GEONAMES_DOWNLOAD_URL": fake.URL()}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := runCLI(t, env, "sync", "missing.tsv")
+
+		require.ErrorContains(t, err, "open file")
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeTempFile(t, "places.tsv", "foo\tbar\n")
+
+		_, _, err := runCLI(t, env, "sync", path)
+
+		require.ErrorContains(t, err, "parse ID of line 1")
+	})
+
+	t.Run("missing modifications", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeTempFile(t, "places.tsv", places)
+
+		_, _, err := runCLI(t, map[string]string{"GEONAMES_DOWNLOAD_URL": newWebserviceServer(t).URL()}, "sync", path)
+		require.ErrorContains(t, err, "download modifications")
+
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, places, string(actual))
+	})
+}
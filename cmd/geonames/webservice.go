@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+const (
+	elevationSRTM1     = "srtm1"
+	elevationSRTM3     = "srtm3"
+	elevationAstergdem = "astergdem"
+	elevationGTOPO30   = "gtopo30"
+)
+
+func searchCommand(ctx context.Context, a *app, args []string) error {
+	var req webservice.SearchRequest
+
+	flags := newFlagSet(a, "search [flags] <query>")
+	flags.Func("country", "comma separated country codes, e.g. FR,BE", func(s string) error {
+		req.Country = value.ParseMultipleValues[value.CountryCode](strings.ToUpper(s))
+
+		return nil
+	})
+	flags.Func("feature-class", "comma separated feature classes, e.g. P,A", func(s string) error {
+		req.FeatureClass = value.ParseMultipleValues[string](s)
+
+		return nil
+	})
+	flags.StringVar(&req.Language, "lang", "", "language of the names, e.g. fr or local")
+	uint32Var(flags, &req.MaxRows, "max-rows", "maximal number of results, default is 100")
+
+	if err := parseArgs(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	req.Query = strings.Join(flags.Args(), " ")
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Search(ctx, req)
+	if err != nil {
+		return fmt.Errorf("search => %w", err)
+	}
+
+	return a.print(res, geoNamesTable(res, nil))
+}
+
+func getCommand(ctx context.Context, a *app, args []string) error {
+	var req webservice.GetRequest
+
+	flags := newFlagSet(a, "get [flags] <geonameId>")
+	flags.StringVar(&req.Language, "lang", "", "language of the name, e.g. fr or local")
+
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w, invalid geonameId %q", errUsage, flags.Arg(0))
+	}
+
+	req.ID = id
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Get(ctx, req)
+	if err != nil {
+		return fmt.Errorf("get => %w", err)
+	}
+
+	t := geoNamesTable([]webservice.GeoName{res.GeoName}, nil)
+	t.header = append(t.header, "TIMEZONE", "ELEVATION")
+	t.rows[0] = append(t.rows[0], res.Timezone.Name, strconv.FormatInt(int64(res.Elevation), 10))
+
+	return a.print(res, t)
+}
+
+func nearbyCommand(ctx context.Context, a *app, args []string) error {
+	var req webservice.FindNearbyRequest
+
+	flags := newFlagSet(a, "nearby [flags] <latitude> <longitude>")
+	flags.Func("feature-class", "comma separated feature classes, e.g. P,A", func(s string) error {
+		req.FeatureClass = value.ParseMultipleValues[string](s)
+
+		return nil
+	})
+	flags.Func("feature-code", "comma separated feature codes, e.g. PPLC,ADM1", func(s string) error {
+		req.FeatureCode = value.ParseMultipleValues[string](s)
+
+		return nil
+	})
+	float64PtrVar(flags, &req.Radius, "radius", "maximal distance in km")
+	uint32Var(flags, &req.MaxRows, "max-rows", "maximal number of results, default is 10")
+	flags.BoolVar(&req.LocalCountry, "local-country", false, "restrict the results to the local country")
+
+	position, err := parsePositionArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	req.Position = position
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.FindNearby(ctx, req)
+	if err != nil {
+		return fmt.Errorf("find nearby => %w", err)
+	}
+
+	return a.print(res, nearbyTable(res))
+}
+
+func reverseCommand(ctx context.Context, a *app, args []string) error {
+	var req webservice.FindNearbyPlaceNameRequest
+
+	flags := newFlagSet(a, "reverse [flags] <latitude> <longitude>")
+	flags.StringVar(&req.Language, "lang", "", "language of the names, e.g. fr or local")
+	float64PtrVar(flags, &req.Radius, "radius", "maximal distance in km")
+	uint32Var(flags, &req.MaxRows, "max-rows", "maximal number of results, default is 1")
+	flags.BoolVar(&req.LocalCountry, "local-country", false, "restrict the results to the local country")
+	flags.Func("cities", "minimal size, cities1000, cities5000 or cities15000", func(s string) error {
+		req.Cities = value.Cities(s)
+
+		return nil
+	})
+
+	position, err := parsePositionArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	req.Position = position
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.FindNearbyPlaceName(ctx, req)
+	if err != nil {
+		return fmt.Errorf("find nearby place name => %w", err)
+	}
+
+	return a.print(res, nearbyTable(res))
+}
+
+func timezoneCommand(ctx context.Context, a *app, args []string) error {
+	var req webservice.TimezoneRequest
+
+	flags := newFlagSet(a, "timezone [flags] <latitude> <longitude>")
+	flags.StringVar(&req.Language, "lang", "", "language of the country name")
+	float64PtrVar(flags, &req.Radius, "radius", "buffer in km for the closest timezone in coastal areas")
+	flags.Func("date", "date of sunrise and sunset, e.g. 2024-06-21", func(s string) error {
+		date, err := time.Parse(time.DateOnly, s)
+		req.Date = date
+
+		return err
+	})
+
+	position, err := parsePositionArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	req.Position = position
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Timezone(ctx, req)
+	if err != nil {
+		return fmt.Errorf("timezone => %w", err)
+	}
+
+	const timeFormat = "2006-01-02 15:04"
+
+	return a.print(res, table{
+		header: []string{"TIMEZONE", "COUNTRY", "TIME", "SUNRISE", "SUNSET", "GMT", "DST"},
+		rows: [][]string{{
+			res.Name,
+			string(res.Country.Code),
+			res.Time.Format(timeFormat),
+			res.Sunrise.Format(timeFormat),
+			res.Sunset.Format(timeFormat),
+			strconv.Itoa(res.GMTOffset),
+			strconv.Itoa(res.DSTOffset),
+		}},
+	})
+}
+
+func elevationCommand(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "elevation [flags] <latitude> <longitude>")
+	model := flags.String("model", elevationSRTM3, "elevation model, srtm1, srtm3, astergdem or gtopo30")
+
+	position, err := parsePositionArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.webservice()
+	if err != nil {
+		return err
+	}
+
+	var lookup func(ctx context.Context, position value.Position) (int32, error)
+
+	switch *model {
+	case elevationSRTM1:
+		lookup = client.SRTM1
+	case elevationSRTM3:
+		lookup = client.SRTM3
+	case elevationAstergdem:
+		lookup = client.Astergdem
+	case elevationGTOPO30:
+		lookup = client.GTOPO30
+	default:
+		return fmt.Errorf("%w, unknown elevation model %q", errUsage, *model)
+	}
+
+	res, err := lookup(ctx, position)
+	if err != nil {
+		return fmt.Errorf("elevation => %w", err)
+	}
+
+	return a.print(
+		map[string]any{"model": *model, "position": position, "elevation": res},
+		table{header: []string{"MODEL", "ELEVATION"}, rows: [][]string{{*model, strconv.FormatInt(int64(res), 10)}}},
+	)
+}
+
+// newFlagSet returns the flag set of a command, its usage is printed to stderr.
+func newFlagSet(a *app, synopsis string) *flag.FlagSet {
+	name, _, _ := strings.Cut(synopsis, " ")
+
+	res := flag.NewFlagSet(name, flag.ContinueOnError)
+	res.SetOutput(a.stderr)
+	res.Usage = func() {
+		_, _ = fmt.Fprintf(a.stderr, "Usage: geonames %s\n\nFlags:\n", synopsis)
+		res.PrintDefaults()
+	}
+
+	return res
+}
+
+// parseArgs parses the flags and checks the number of remaining arguments, -1 is unlimited.
+func parseArgs(flags *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+
+	if flags.NArg() < minArgs || (maxArgs >= 0 && flags.NArg() > maxArgs) {
+		flags.Usage()
+
+		return fmt.Errorf("%w, unexpected number of arguments", errUsage)
+	}
+
+	return nil
+}
+
+// parsePositionArgs parses the flags followed by the latitude and longitude arguments.
+func parsePositionArgs(flags *flag.FlagSet, args []string) (value.Position, error) {
+	if err := parseArgs(flags, args, 2, 2); err != nil {
+		return value.Position{}, err
+	}
+
+	res, err := value.ParsePosition(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return res, fmt.Errorf("%w => %w", errUsage, err)
+	}
+
+	return res, nil
+}
+
+func uint32Var(flags *flag.FlagSet, p *uint32, name, usage string) {
+	flags.Func(name, usage, func(s string) error {
+		v, err := strconv.ParseUint(s, 10, 32)
+		*p = uint32(v)
+
+		return err
+	})
+}
+
+// float64PtrVar defines a flag of an optional parameter, which is only set when the flag is given.
+func float64PtrVar(flags *flag.FlagSet, p **float64, name, usage string) {
+	flags.Func(name, usage, func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		*p = &v
+
+		return err
+	})
+}
+
+func nearbyTable(items []webservice.GeoNameNearby) table {
+	places := make([]webservice.GeoName, 0, len(items))
+	distances := make([]float64, 0, len(items))
+
+	for _, item := range items {
+		places = append(places, item.GeoName)
+		distances = append(distances, item.Distance)
+	}
+
+	return geoNamesTable(places, distances)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+)
+
+func newWebserviceServer(t *testing.T) *testutil.FakeServer {
+	t.Helper()
+
+	return testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+}
+
+func Test_webserviceCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		path   string
+		query  map[string]string
+		stdout string
+	}{
+		{
+			name:   "search",
+			args:   []string{"search", "-country", "us,gb", "-feature-class", "P", "-max-rows", "5", "new", "york"},
+			path:   "/searchJSON",
+			query:  map[string]string{"q": "new york", "country": "US", "featureClass": "P", "maxRows": "5"},
+			stdout: "New York City",
+		},
+		{
+			name:   "get",
+			args:   []string{"get", "-lang", "fr", "1"},
+			path:   "/getJSON",
+			query:  map[string]string{"geonameId": "1", "lang": "fr"},
+			stdout: "TIMEZONE",
+		},
+		{
+			name:   "nearby",
+			args:   []string{"nearby", "-radius", "10", "-feature-code", "PPLC", "48.5", "2.25"},
+			path:   "/findNearbyJSON",
+			query:  map[string]string{"lat": "48.5", "lng": "2.25", "radius": "10", "featureCode": "PPLC"},
+			stdout: "DISTANCE",
+		},
+		{
+			name:   "reverse",
+			args:   []string{"reverse", "-max-rows", "3", "48.5", "2.25"},
+			path:   "/findNearbyPlaceNameJSON",
+			query:  map[string]string{"lat": "48.5", "lng": "2.25", "maxRows": "3"},
+			stdout: "DISTANCE",
+		},
+		{
+			name:   "timezone",
+			args:   []string{"timezone", "-date", "2024-06-21", "48.5", "2.25"},
+			path:   "/timezoneJSON",
+			query:  map[string]string{"lat": "48.5", "lng": "2.25", "date": "2024-06-21"},
+			stdout: "2021-01-01 12:00",
+		},
+		{
+			name:   "elevation",
+			args:   []string{"elevation", "-model", "srtm1", "48.5", "2.25"},
+			path:   "/srtm1JSON",
+			query:  map[string]string{"lat": "48.5", "lng": "2.25"},
+			stdout: "111",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := newWebserviceServer(t)
+
+			stdout, _, err := runCLI(t, map[string]string{
+				"GEONAMES_USERNAME": "demo",
+				"GEONAMES_BASE_URL": fake.URL(),
+			}, tt.args...)
+
+			require.NoError(t, err)
+			assert.Contains(t, stdout, tt.stdout)
+
+			requests := fake.Requests(tt.path)
+			require.Len(t, requests, 1)
+
+			for key, expected := range tt.query {
+				assert.Equal(t, expected, requests[0].URL.Query().Get(key), key)
+			}
+		})
+	}
+}
+
+func Test_webserviceCommands_json(t *testing.T) {
+	t.Parallel()
+
+	fake := newWebserviceServer(t)
+
+	stdout, _, err := runCLI(t, map[string]string{
+		"GEONAMES_USERNAME": "demo",
+		"GEONAMES_BASE_URL": fake.URL(),
+	}, "-json", "search", "york")
+
+	require.NoError(t, err)
+
+	var actual []map[string]any
+
+	require.NoError(t, json.Unmarshal([]byte(stdout), &actual))
+	require.Len(t, actual, 2)
+	assert.Equal(t, "New York City", actual[0]["Name"])
+}
+
+func Test_webserviceCommands_verbose(t *testing.T) {
+	t.Parallel()
+
+	fake := newWebserviceServer(t)
+
+	_, stderr, err := runCLI(t, map[string]string{
+		"GEONAMES_USERNAME": "demo",
+		"GEONAMES_BASE_URL": fake.URL(),
+	}, "-verbose", "get", "1")
+
+	require.NoError(t, err)
+	assert.Contains(t, stderr, `msg="geonames call" endpoint=get status=200`)
+	assert.NotContains(t, stderr, "demo")
+}
+
+func Test_elevationCommand_invalidModel(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := runCLI(t, map[string]string{"GEONAMES_USERNAME": "demo"}, "elevation", "-model", "foo", "1", "2")
+
+	require.ErrorIs(t, err, errUsage)
+}
@@ -11,12 +11,15 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/platx/geonames/middleware"
 )
 
 const (
 	defaultBaseURL        = "https://download.geonames.org/export/dump"
 	defaultRequestTimeout = 10 * time.Minute
 	columnSeparator       = "\t"
+	maxLineLength         = 64 << 20 // shape geometries are stored in a single line
 	commentPrefix         = "#"
 )
 
@@ -24,6 +27,7 @@ var (
 	ErrFileNotFoundInArchive = errors.New("file not found in archive")
 	ErrUnexpectedStatusCode  = errors.New("unexpected status code")
 	ErrInvalidType           = errors.New("invalid type")
+	ErrFileNotDownloaded     = errors.New("file not downloaded")
 )
 
 type httpDoer interface {
@@ -31,8 +35,9 @@ type httpDoer interface {
 }
 
 type Client struct {
-	httpClient httpDoer
-	baseURL    string
+	httpClient  httpDoer
+	baseURL     string
+	middlewares []middleware.Middleware
 }
 
 type Option func(*Client)
@@ -54,6 +59,14 @@ func WithBaseURL(baseURL string) Option {
 	}
 }
 
+// WithMiddleware adds middlewares intercepting every download, the first one is the outermost.
+// The endpoint of the Call is the file name like "cities500.zip", its duration covers the transfer of the file.
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(client *Client) {
+		client.middlewares = append(client.middlewares, middlewares...)
+	}
+}
+
 func NewClient(opts ...Option) *Client {
 	res := &Client{
 		httpClient: &http.Client{
@@ -62,7 +75,8 @@ func NewClient(opts ...Option) *Client {
 			Jar:           nil,
 			Timeout:       defaultRequestTimeout,
 		},
-		baseURL: defaultBaseURL,
+		baseURL:     defaultBaseURL,
+		middlewares: nil,
 	}
 
 	for _, opt := range opts {
@@ -111,8 +125,59 @@ func (c *Client) downloadAndParseZIPFile(ctx context.Context, fileName string) (
 	return c.parseZIPFile(ctx, file, zipFile)
 }
 
+// downloadFile downloads the file through the middlewares into a temporary file. The file of a middleware calling
+// next more than once is the last one, ErrFileNotDownloaded is returned when none calls it.
 func (c *Client) downloadFile(ctx context.Context, fileName string) (*os.File, error) {
-	req, err := c.createHTTPRequest(ctx, fileName)
+	var res *os.File
+
+	call := &middleware.Call{
+		Endpoint: fileName,
+		Request:  nil,
+		Status:   0,
+		ErrCode:  0,
+		Cached:   false,
+		Duration: 0,
+	}
+
+	handler := func(ctx context.Context, call *middleware.Call) error {
+		start := time.Now()
+
+		if res != nil {
+			removeFile(res)
+		}
+
+		var err error
+
+		res, err = c.fetchFile(ctx, call)
+
+		call.Duration = time.Since(start)
+
+		return err
+	}
+
+	if err := middleware.Chain(handler, c.middlewares...)(ctx, call); err != nil {
+		if res != nil {
+			removeFile(res)
+		}
+
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, fmt.Errorf("%s => %w", fileName, ErrFileNotDownloaded)
+	}
+
+	return res, nil
+}
+
+// removeFile closes and deletes a temporary file which is not returned.
+func removeFile(file *os.File) {
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+}
+
+func (c *Client) fetchFile(ctx context.Context, call *middleware.Call) (*os.File, error) {
+	req, err := c.createHTTPRequest(ctx, call.Endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("create http request => %w", err)
 	}
@@ -126,11 +191,13 @@ func (c *Client) downloadFile(ctx context.Context, fileName string) (*os.File, e
 		_ = res.Body.Close()
 	}()
 
+	call.Status = res.StatusCode
+
 	if res.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, res.StatusCode)
 	}
 
-	tmpFile, err := os.CreateTemp("", "*"+fileName)
+	tmpFile, err := os.CreateTemp("", "*"+call.Endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("create temp file => %w", err)
 	}
@@ -180,6 +247,7 @@ func (c *Client) parseTSV(ctx context.Context, file io.ReadCloser) Iterator[[]st
 		}()
 
 		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
 
 		for {
 			select {
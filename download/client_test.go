@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/platx/geonames/download/testdata"
+	"github.com/platx/geonames/middleware"
 	"github.com/platx/geonames/testutil"
 )
 
@@ -52,6 +54,110 @@ func Test_NewClient(t *testing.T) {
 	})
 }
 
+func Test_Client_middleware(t *testing.T) {
+	t.Parallel()
+
+	var calls []middleware.Call
+
+	record := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, call *middleware.Call) error {
+			err := next(ctx, call)
+
+			calls = append(calls, *call)
+
+			return err
+		}
+	}
+
+	fake := testutil.NewFakeServer(t, testutil.WithDownloadFixtures())
+	client := NewClient(WithBaseURL(fake.URL()), WithMiddleware(record))
+
+	_, err := client.TimeZones(context.Background())
+	require.NoError(t, err)
+
+	_, err = client.downloadFile(context.Background(), "unknown.zip")
+	require.ErrorIs(t, err, ErrUnexpectedStatusCode)
+
+	require.Len(t, calls, 2)
+
+	assert.Equal(t, "timeZones.txt", calls[0].Endpoint)
+	assert.Nil(t, calls[0].Request)
+	assert.Equal(t, http.StatusOK, calls[0].Status)
+	assert.Positive(t, calls[0].Duration)
+
+	assert.Equal(t, "unknown.zip", calls[1].Endpoint)
+	assert.Equal(t, http.StatusNotFound, calls[1].Status)
+}
+
+func Test_Client_downloadFile_middleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		middleware middleware.Middleware
+		err        error
+	}{
+		{
+			name: "next not called",
+			middleware: func(middleware.Handler) middleware.Handler {
+				return func(context.Context, *middleware.Call) error {
+					return nil
+				}
+			},
+			err: ErrFileNotDownloaded,
+		},
+		{
+			name: "next called twice",
+			middleware: func(next middleware.Handler) middleware.Handler {
+				return func(ctx context.Context, call *middleware.Call) error {
+					if err := next(ctx, call); err != nil {
+						return err
+					}
+
+					return next(ctx, call)
+				}
+			},
+			err: nil,
+		},
+		{
+			name: "failing after next",
+			middleware: func(next middleware.Handler) middleware.Handler {
+				return func(ctx context.Context, call *middleware.Call) error {
+					_ = next(ctx, call)
+
+					return assert.AnError
+				}
+			},
+			err: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := testutil.NewFakeServer(t, testutil.WithDownloadFixtures())
+			client := NewClient(WithBaseURL(fake.URL()), WithMiddleware(tt.middleware))
+
+			file, err := client.downloadFile(context.Background(), "timeZones.txt")
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.Nil(t, file)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = os.Remove(file.Name())
+			})
+
+			assert.FileExists(t, file.Name())
+		})
+	}
+}
+
 func Test_Client_downloadAndParseFile(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,43 @@
+package download
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/platx/geonames/value"
+)
+
+// Filter returns an iterator yielding only items matching the predicate, errors are passed through.
+func Filter[T any](items Iterator[T], predicate func(item T) bool) Iterator[T] {
+	return func(yield func(T, error) bool) {
+		for item, err := range items {
+			if err == nil && !predicate(item) {
+				continue
+			}
+
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// FilterByGeohash returns an iterator yielding only records located within the geohash cell of the prefix,
+// case-insensitive. An empty prefix yields every record, an invalid one yields a single error.
+func FilterByGeohash(items Iterator[GeoName], prefix value.Geohash) Iterator[GeoName] {
+	if prefix == "" {
+		return items
+	}
+
+	prefix = value.Geohash(strings.ToLower(string(prefix)))
+
+	if !prefix.Valid() {
+		return func(yield func(GeoName, error) bool) {
+			yield(GeoName{}, fmt.Errorf("%w => %q", value.ErrInvalidGeohash, prefix))
+		}
+	}
+
+	return Filter(items, func(item GeoName) bool {
+		return value.EncodeGeohash(item.Position, value.GeohashMaxPrecision).HasPrefix(prefix)
+	})
+}
@@ -0,0 +1,159 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/platx/geonames/download/testdata"
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Filter(t *testing.T) {
+	t.Parallel()
+
+	items := Iterator[int](func(yield func(int, error) bool) {
+		for i := range 6 {
+			var err error
+			if i == 3 {
+				err = assert.AnError
+			}
+
+			if !yield(i, err) {
+				return
+			}
+		}
+	})
+
+	res, errs := collect(Filter(items, func(item int) bool { return item%2 == 0 }), nil)
+
+	assert.Equal(t, []int{0, 2, 4}, res)
+	assert.Equal(t, []error{assert.AnError}, errs)
+
+	for item := range Filter(items, func(int) bool { return true }) {
+		if item == 1 {
+			break
+		}
+	}
+}
+
+func Test_FilterByGeohash(t *testing.T) {
+	t.Parallel()
+
+	caller := func(client *Client, ctx context.Context) ([]GeoName, []error) {
+		res, err := client.Cities500(ctx)
+
+		return collect(FilterByGeohash(res, value.EncodeGeohash(value.Position{Latitude: 2.2, Longitude: -2.2}, 3)), err)
+	}
+
+	testCase := testSuite[GeoName]{
+		name: "success",
+		args: args{
+			httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+				m.On("Do", mock.Anything).Once().Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       testutil.MustOpen(testdata.FS, "cities500.zip"),
+					},
+					nil,
+				)
+			}),
+			ctx: context.Background(),
+		},
+		exp: exp[GeoName]{
+			res: []GeoName{
+				{
+					ID:             2,
+					Name:           "London",
+					NameASCII:      "London",
+					AlternateNames: []string{"Landan", "Лондон"},
+					Position: value.Position{
+						Latitude:  2.222,
+						Longitude: -2.222,
+					},
+					FeatureClass:          "B",
+					FeatureCode:           "BBBB",
+					CountryCode:           value.CountryCodeUnitedKingdom,
+					AlternateCountryCodes: []value.CountryCode{},
+					AdminCode: value.AdminCode{
+						First:  "FOO",
+						Second: "BAR",
+						Third:  "BAZ",
+						Fourth: "FOOBAR",
+					},
+					Population:            222222,
+					Elevation:             222,
+					DigitalElevationModel: 22,
+					Timezone:              "Europe/London",
+					ModificationDate:      time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			err: []error{
+				errors.New("parse ID => strconv.ParseUint: parsing \"v\": invalid syntax"),
+				errors.New("parse Position => latitude => strconv.ParseFloat: parsing \"v\": invalid syntax"),
+				errors.New("parse Position => longitude => strconv.ParseFloat: parsing \"v\": invalid syntax"),
+				errors.New("parse Population => strconv.ParseInt: parsing \"v\": invalid syntax"),
+				errors.New("parse Elevation => strconv.ParseInt: parsing \"v\": invalid syntax"),
+				errors.New("parse DigitalElevationModel => strconv.ParseInt: parsing \"v\": invalid syntax"),
+				errors.New("parse ModificationDate => parsing time \"v\" as \"2006-01-02\": cannot parse \"v\" as \"2006\""),
+				errors.New("invalid row length, expected 19, got 3"),
+			},
+		},
+	}
+
+	testCase.run(t, caller)
+}
+
+func Test_FilterByGeohash_prefix(t *testing.T) {
+	t.Parallel()
+
+	items := Iterator[GeoName](func(yield func(GeoName, error) bool) {
+		for _, position := range []value.Position{
+			{Latitude: 51.5073, Longitude: -0.1277},
+			{Latitude: 48.8566, Longitude: 2.3522},
+		} {
+			if !yield(GeoName{Position: position}, nil) {
+				return
+			}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		prefix value.Geohash
+		exp    int
+		err    error
+	}{
+		{name: "empty", prefix: "", exp: 2},
+		{name: "cell", prefix: "gcp", exp: 1},
+		{name: "upper case", prefix: "GCPVJ", exp: 1},
+		{name: "max precision", prefix: value.EncodeGeohash(value.Position{Latitude: 48.8566, Longitude: 2.3522}, 12), exp: 1},
+		{name: "too long", prefix: "gcpvj0duq5331", err: value.ErrInvalidGeohash},
+		{name: "invalid character", prefix: "gca", err: value.ErrInvalidGeohash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, errs := collect(FilterByGeohash(items, tt.prefix), nil)
+
+			assert.Len(t, res, tt.exp)
+
+			if tt.err == nil {
+				assert.Empty(t, errs)
+
+				return
+			}
+
+			assert.Len(t, errs, 1)
+			assert.ErrorIs(t, errs[0], tt.err)
+		})
+	}
+}
@@ -13,6 +13,17 @@ var ErrInvalidRowLength = errors.New("invalid row length")
 
 type Iterator[T any] iter.Seq2[T, error]
 
+// FromSlice returns an iterator over the items, e.g. to build an index from records already in memory.
+func FromSlice[T any](items []T) Iterator[T] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
 type GeoName struct {
 	// ID of record in geonames database
 	ID uint64
@@ -470,6 +481,33 @@ func (v *AlternateNameDeleted) UnmarshalRow(row []string) error {
 	return nil
 }
 
+type Shape struct {
+	// GeoNameID of the country, referring to Country.ID
+	GeoNameID uint64
+	// Geometry outline of the country
+	Geometry value.MultiPolygon
+}
+
+func (v *Shape) UnmarshalRow(row []string) error {
+	const columns = 2
+
+	var err error
+
+	if err = checkColumns(row, columns); err != nil {
+		return err
+	}
+
+	if v.GeoNameID, err = value.ParseUint64(row[0]); err != nil {
+		return fmt.Errorf("parse GeoNameID => %w", err)
+	}
+
+	if v.Geometry, err = value.ParseGeoJSON([]byte(row[1])); err != nil {
+		return fmt.Errorf("parse Geometry => %w", err)
+	}
+
+	return nil
+}
+
 func checkColumns(row []string, expected int) error {
 	if len(row) != expected {
 		return fmt.Errorf("%w, expected %d, got %d", ErrInvalidRowLength, expected, len(row))
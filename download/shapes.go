@@ -0,0 +1,12 @@
+package download
+
+import (
+	"context"
+)
+
+// Shapes parses simplified country boundaries in GeoJSON from the shapes_all_low.zip file.
+func (c *Client) Shapes(ctx context.Context) (Iterator[Shape], error) {
+	res, err := c.downloadAndParseZIPFile(ctx, "shapes_all_low.zip")
+
+	return withUnmarshalRows[Shape](withSkipHeader(res)), err
+}
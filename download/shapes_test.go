@@ -0,0 +1,92 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/platx/geonames/download/testdata"
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Client_Shapes(t *testing.T) {
+	t.Parallel()
+
+	caller := func(client *Client, ctx context.Context) ([]Shape, []error) {
+		return collect(client.Shapes(ctx))
+	}
+
+	testCase := testSuite[Shape]{
+		args: args{
+			httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+				m.On(
+					"Do",
+					mock.MatchedBy(func(given *http.Request) bool {
+						return assertRequest(
+							t,
+							given,
+							"shapes_all_low.zip",
+						)
+					}),
+				).Once().Return(
+					&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       testutil.MustOpen(testdata.FS, "shapes_all_low.zip"),
+					},
+					nil,
+				)
+			}),
+			ctx: context.Background(),
+		},
+		exp: exp[Shape]{
+			res: []Shape{
+				{
+					GeoNameID: 1,
+					Geometry: value.MultiPolygon{
+						{
+							{
+								{Latitude: 0, Longitude: 0},
+								{Latitude: 0, Longitude: 1},
+								{Latitude: 1, Longitude: 1},
+								{Latitude: 1, Longitude: 0},
+								{Latitude: 0, Longitude: 0},
+							},
+						},
+					},
+				},
+				{
+					GeoNameID: 2,
+					Geometry: value.MultiPolygon{
+						{
+							{
+								{Latitude: 2, Longitude: 2},
+								{Latitude: 2, Longitude: 3},
+								{Latitude: 3, Longitude: 3},
+								{Latitude: 2, Longitude: 2},
+							},
+						},
+						{
+							{
+								{Latitude: 4, Longitude: 4},
+								{Latitude: 4, Longitude: 5},
+								{Latitude: 5, Longitude: 5},
+								{Latitude: 4, Longitude: 4},
+							},
+						},
+					},
+				},
+			},
+			err: []error{
+				errors.New("parse GeoNameID => strconv.ParseUint: parsing \"v\": invalid syntax"),
+				errors.New("parse Geometry => invalid geometry => unsupported type \"Point\""),
+				errors.New("invalid row length, expected 2, got 1"),
+			},
+		},
+	}
+
+	testCase.run(t, caller)
+}
@@ -0,0 +1,35 @@
+// Package elevation looks up elevations offline in SRTM .hgt tiles, falling back to a webservice model for the
+// positions without tile.
+package elevation
+
+import (
+	"context"
+
+	"github.com/platx/geonames/value"
+)
+
+// Provider returns the elevation of a position, value.NoElevation for "no data" areas like oceans.
+// It is implemented by Tiles and the webservice models adapted with Webservice.
+type Provider interface {
+	Elevation(ctx context.Context, position value.Position) (value.Elevation, error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(ctx context.Context, position value.Position) (value.Elevation, error)
+
+func (f ProviderFunc) Elevation(ctx context.Context, position value.Position) (value.Elevation, error) {
+	return f(ctx, position)
+}
+
+// Webservice adapts a webservice model returning meters to a Provider, the noData marker of the model is
+// value.NoElevation, e.g. Webservice(client.SRTM3, value.NoDataSRTM).
+func Webservice(lookup func(ctx context.Context, position value.Position) (int32, error), noData int32) Provider {
+	return ProviderFunc(func(ctx context.Context, position value.Position) (value.Elevation, error) {
+		meters, err := lookup(ctx, position)
+		if err != nil {
+			return value.NoElevation, err
+		}
+
+		return value.NewElevation(meters, noData), nil
+	})
+}
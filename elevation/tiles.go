@@ -0,0 +1,235 @@
+package elevation
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"sync"
+
+	"github.com/platx/geonames/value"
+)
+
+const (
+	// samplesSRTM1 is the number of rows and columns of a 1 arc-second tile.
+	samplesSRTM1 = 3601
+	// samplesSRTM3 is the number of rows and columns of a 3 arc-second tile.
+	samplesSRTM3 = 1201
+	// DefaultCacheSize is the number of tiles kept in memory, about 26 MB per 1 arc-second tile.
+	DefaultCacheSize = 16
+)
+
+var (
+	ErrTileNotFound = errors.New("tile not found")
+	ErrInvalidTile  = errors.New("invalid tile")
+)
+
+// Tiles reads the elevations of SRTM .hgt tiles of 1 or 3 arc-seconds named by their south-west corner,
+// e.g. N45E006.hgt. The elevation is interpolated bilinearly between the four surrounding samples.
+// It is safe for concurrent use.
+type Tiles struct {
+	fsys      fs.FS
+	cacheSize int
+	fallback  Provider
+	mu        sync.Mutex
+	tiles     map[string]*list.Element
+	// recent holds the entries of the tiles, the most recently used at the front
+	recent *list.List
+}
+
+// entry is a cached tile, it is read once outside the lock of the cache.
+type entry struct {
+	name string
+	once sync.Once
+	tile *tile
+	err  error
+}
+
+type tile struct {
+	samples int
+	heights []int16
+}
+
+type Option func(*Tiles)
+
+// WithCacheSize sets the number of tiles kept in memory, the least recently used one is evicted,
+// default is DefaultCacheSize.
+func WithCacheSize(size int) Option {
+	return func(t *Tiles) {
+		t.cacheSize = max(size, 1)
+	}
+}
+
+// WithFallback sets the provider used for positions without tile, e.g. Webservice(client.SRTM3, value.NoDataSRTM).
+func WithFallback(provider Provider) Option {
+	return func(t *Tiles) {
+		t.fallback = provider
+	}
+}
+
+// NewTiles returns the tiles of the file system, e.g. os.DirFS("srtm").
+func NewTiles(fsys fs.FS, opts ...Option) *Tiles {
+	res := &Tiles{
+		fsys:      fsys,
+		cacheSize: DefaultCacheSize,
+		fallback:  nil,
+		mu:        sync.Mutex{},
+		tiles:     map[string]*list.Element{},
+		recent:    list.New(),
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	return res
+}
+
+// Elevation returns the elevation of the position, value.NoElevation if the surrounding samples are voids.
+// Positions without tile are passed to the fallback, ErrTileNotFound is returned without one.
+func (t *Tiles) Elevation(ctx context.Context, position value.Position) (value.Elevation, error) {
+	lat, lng := math.Floor(position.Latitude), math.Floor(position.Longitude)
+
+	loaded, err := t.tile(tileName(lat, lng))
+	if errors.Is(err, ErrTileNotFound) && t.fallback != nil {
+		return t.fallback.Elevation(ctx, position)
+	}
+
+	if err != nil {
+		return value.NoElevation, err
+	}
+
+	return loaded.interpolate(lat+1-position.Latitude, position.Longitude-lng), nil
+}
+
+// tile returns the cached tile or loads it, concurrent lookups of a tile being loaded wait for it. Tiles
+// failing to load are not cached.
+func (t *Tiles) tile(name string) (*tile, error) {
+	elem := t.entry(name)
+	cached, _ := elem.Value.(*entry)
+
+	cached.once.Do(func() {
+		cached.tile, cached.err = readTile(t.fsys, name)
+	})
+
+	if cached.err != nil {
+		t.mu.Lock()
+		if t.tiles[name] == elem {
+			t.recent.Remove(elem)
+			delete(t.tiles, name)
+		}
+		t.mu.Unlock()
+
+		return nil, cached.err
+	}
+
+	return cached.tile, nil
+}
+
+// entry returns the cache entry of the tile, the least recently used one is evicted for a new one.
+func (t *Tiles) entry(name string) *list.Element {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.tiles[name]; ok {
+		t.recent.MoveToFront(elem)
+
+		return elem
+	}
+
+	res := t.recent.PushFront(&entry{name: name, once: sync.Once{}, tile: nil, err: nil})
+	t.tiles[name] = res
+
+	if t.recent.Len() > t.cacheSize {
+		evicted, _ := t.recent.Remove(t.recent.Back()).(*entry)
+
+		delete(t.tiles, evicted.name)
+	}
+
+	return res
+}
+
+// readTile reads the big-endian signed 16-bit samples of the tile, its resolution is told by the file size.
+func readTile(fsys fs.FS, name string) (*tile, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w => %s", ErrTileNotFound, name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read tile => %w", err)
+	}
+
+	var samples int
+
+	switch len(data) {
+	case samplesSRTM1 * samplesSRTM1 * 2:
+		samples = samplesSRTM1
+	case samplesSRTM3 * samplesSRTM3 * 2:
+		samples = samplesSRTM3
+	default:
+		return nil, fmt.Errorf("%w => %s has %d bytes", ErrInvalidTile, name, len(data))
+	}
+
+	heights := make([]int16, samples*samples)
+	for i := range heights {
+		heights[i] = int16(binary.BigEndian.Uint16(data[i*2:]))
+	}
+
+	return &tile{samples: samples, heights: heights}, nil
+}
+
+// tileName returns the name of the tile of the south-west corner, e.g. N45E006.hgt or S01W078.hgt.
+func tileName(lat, lng float64) string {
+	latHemisphere, lngHemisphere := 'N', 'E'
+
+	if lat < 0 {
+		latHemisphere = 'S'
+	}
+
+	if lng < 0 {
+		lngHemisphere = 'W'
+	}
+
+	return fmt.Sprintf("%c%02d%c%03d.hgt", latHemisphere, int(math.Abs(lat)), lngHemisphere, int(math.Abs(lng)))
+}
+
+// interpolate returns the elevation at the offsets in degrees from the north-west corner, the voids among the
+// four surrounding samples are left out.
+func (t *tile) interpolate(south, east float64) value.Elevation {
+	last := t.samples - 1
+	y, x := south*float64(last), east*float64(last)
+	row, col := min(int(y), last), min(int(x), last)
+	dy, dx := y-float64(row), x-float64(col)
+
+	corners := []struct {
+		row, col int
+		weight   float64
+	}{
+		{row, col, (1 - dy) * (1 - dx)},
+		{row, min(col+1, last), (1 - dy) * dx},
+		{min(row+1, last), col, dy * (1 - dx)},
+		{min(row+1, last), min(col+1, last), dy * dx},
+	}
+
+	var sum, weights float64
+
+	for _, corner := range corners {
+		height := t.heights[corner.row*t.samples+corner.col]
+		if height == value.NoDataSRTM || corner.weight == 0 {
+			continue
+		}
+
+		sum += float64(height) * corner.weight
+		weights += corner.weight
+	}
+
+	if weights == 0 {
+		return value.NoElevation
+	}
+
+	return value.Elevation{Meters: int32(math.Round(sum / weights)), Valid: true}
+}
@@ -0,0 +1,258 @@
+package elevation
+
+import (
+	"context"
+	"encoding/binary"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+// meters returns a valid elevation.
+func meters(meters int32) value.Elevation {
+	return value.Elevation{Meters: meters, Valid: true}
+}
+
+// newTile returns the file of a tile with the heights 2*row + 4*col, except the given voids.
+func newTile(samples int, voids ...[2]int) *fstest.MapFile {
+	data := make([]byte, samples*samples*2)
+
+	for row := range samples {
+		for col := range samples {
+			binary.BigEndian.PutUint16(data[(row*samples+col)*2:], uint16(2*row+4*col))
+		}
+	}
+
+	for _, void := range voids {
+		noData := int16(value.NoDataSRTM)
+		binary.BigEndian.PutUint16(data[(void[0]*samples+void[1])*2:], uint16(noData))
+	}
+
+	return &fstest.MapFile{Data: data}
+}
+
+func Test_Tiles_Elevation(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"N45E006.hgt": newTile(samplesSRTM3),
+		"S01W078.hgt": newTile(samplesSRTM3, [2]int{600, 600}, [2]int{600, 601}, [2]int{601, 600}, [2]int{601, 601}),
+		"N00E000.hgt": newTile(samplesSRTM3, [2]int{600, 601}),
+		"N10E010.hgt": &fstest.MapFile{Data: []byte("invalid")},
+	}
+
+	tests := []struct {
+		name     string
+		position value.Position
+		exp      value.Elevation
+	}{
+		{name: "north-west corner", position: value.Position{Latitude: 46 - 1e-9, Longitude: 6}, exp: meters(0)},
+		{name: "sample", position: value.Position{Latitude: 45.5, Longitude: 6.5}, exp: meters(3600)},
+		{name: "between rows", position: value.Position{Latitude: 46 - 600.5/1200, Longitude: 6.5}, exp: meters(3601)},
+		{
+			name:     "between rows and columns",
+			position: value.Position{Latitude: 46 - 600.25/1200, Longitude: 6 + 600.75/1200},
+			exp:      meters(3604),
+		},
+		{name: "south-east corner", position: value.Position{Latitude: 45, Longitude: 7 - 1e-9}, exp: meters(7200)},
+		{name: "southern and western hemisphere", position: value.Position{Latitude: -0.25, Longitude: -77.75}, exp: meters(1800)},
+		{name: "voids", position: value.Position{Latitude: -0.5 - 0.2/1200, Longitude: -77.5 + 0.2/1200}, exp: value.NoElevation},
+		{name: "void left out", position: value.Position{Latitude: 0.5 - 0.5/1200, Longitude: 0.5 + 0.5/1200}, exp: meters(3603)},
+	}
+
+	tiles := NewTiles(fsys)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tiles.Elevation(context.Background(), tt.position)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, actual)
+		})
+	}
+
+	t.Run("missing tile", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tiles.Elevation(context.Background(), value.Position{Latitude: 1, Longitude: 1})
+
+		require.ErrorIs(t, err, ErrTileNotFound)
+		require.ErrorContains(t, err, "N01E001.hgt")
+	})
+
+	t.Run("invalid tile", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := tiles.Elevation(context.Background(), value.Position{Latitude: 10.5, Longitude: 10.5})
+
+		require.ErrorIs(t, err, ErrInvalidTile)
+	})
+}
+
+func Test_Tiles_Elevation_SRTM1(t *testing.T) {
+	t.Parallel()
+
+	tiles := NewTiles(fstest.MapFS{"N45E006.hgt": newTile(samplesSRTM1)})
+
+	actual, err := tiles.Elevation(context.Background(), value.Position{Latitude: 45.5, Longitude: 6.5})
+
+	require.NoError(t, err)
+	assert.Equal(t, meters(10800), actual)
+}
+
+func Test_Tiles_fallback(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+	client := webservice.NewClient("demo", webservice.WithBaseURL(fake.URL()))
+
+	tiles := NewTiles(
+		fstest.MapFS{"N45E006.hgt": newTile(samplesSRTM3)},
+		WithFallback(Webservice(client.SRTM3, value.NoDataSRTM)),
+	)
+
+	local, err := tiles.Elevation(context.Background(), value.Position{Latitude: 45.5, Longitude: 6.5})
+	require.NoError(t, err)
+
+	remote, err := tiles.Elevation(context.Background(), value.Position{Latitude: 48.5, Longitude: 2.25})
+	require.NoError(t, err)
+
+	assert.Equal(t, meters(3600), local)
+	assert.Equal(t, meters(111), remote)
+
+	requests := fake.Requests("/srtm3JSON")
+	require.Len(t, requests, 1)
+	assert.Equal(t, "48.5", requests[0].URL.Query().Get("lat"))
+}
+
+func Test_Tiles_cache(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"N45E006.hgt": newTile(samplesSRTM3),
+		"N46E006.hgt": newTile(samplesSRTM3),
+	}
+
+	tiles := NewTiles(fsys, WithCacheSize(1))
+
+	_, err := tiles.Elevation(context.Background(), value.Position{Latitude: 45.5, Longitude: 6.5})
+	require.NoError(t, err)
+
+	first := tiles.tiles["N45E006.hgt"]
+	require.NotNil(t, first)
+
+	_, err = tiles.Elevation(context.Background(), value.Position{Latitude: 45.6, Longitude: 6.5})
+	require.NoError(t, err)
+	assert.Same(t, first, tiles.tiles["N45E006.hgt"])
+
+	_, err = tiles.Elevation(context.Background(), value.Position{Latitude: 46.5, Longitude: 6.5})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, tiles.recent.Len())
+	assert.NotContains(t, tiles.tiles, "N45E006.hgt")
+	assert.Contains(t, tiles.tiles, "N46E006.hgt")
+}
+
+// countingFS counts the opened files by name.
+type countingFS struct {
+	fs.FS
+	mu     sync.Mutex
+	opened map[string]int
+}
+
+func (f *countingFS) Open(name string) (fs.File, error) {
+	f.mu.Lock()
+	f.opened[name]++
+	f.mu.Unlock()
+
+	return f.FS.Open(name)
+}
+
+func Test_Tiles_concurrent(t *testing.T) {
+	t.Parallel()
+
+	fsys := &countingFS{FS: fstest.MapFS{"N45E006.hgt": newTile(samplesSRTM3)}, opened: map[string]int{}}
+	tiles := NewTiles(fsys)
+
+	var wg sync.WaitGroup
+
+	for range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			actual, err := tiles.Elevation(context.Background(), value.Position{Latitude: 45.5, Longitude: 6.5})
+			assert.NoError(t, err)
+			assert.Equal(t, meters(3600), actual)
+
+			_, err = tiles.Elevation(context.Background(), value.Position{Latitude: 1, Longitude: 1})
+			assert.ErrorIs(t, err, ErrTileNotFound)
+		}()
+	}
+
+	wg.Wait()
+
+	// the tile is read once, the missing one is not cached
+	assert.Equal(t, 1, fsys.opened["N45E006.hgt"])
+	assert.Positive(t, fsys.opened["N01E001.hgt"])
+	assert.Equal(t, 1, tiles.recent.Len())
+	assert.NotContains(t, tiles.tiles, "N01E001.hgt")
+}
+
+func Test_Webservice(t *testing.T) {
+	t.Parallel()
+
+	lookup := func(_ context.Context, position value.Position) (int32, error) {
+		if position.Latitude == 0 {
+			return 0, assert.AnError
+		}
+
+		return int32(position.Latitude), nil
+	}
+
+	provider := Webservice(lookup, value.NoDataGTOPO30)
+
+	actual, err := provider.Elevation(context.Background(), value.Position{Latitude: 45, Longitude: 6})
+	require.NoError(t, err)
+	assert.Equal(t, meters(45), actual)
+
+	actual, err = provider.Elevation(context.Background(), value.Position{Latitude: value.NoDataGTOPO30, Longitude: 6})
+	require.NoError(t, err)
+	assert.Equal(t, value.NoElevation, actual)
+
+	_, err = provider.Elevation(context.Background(), value.Position{Latitude: 0, Longitude: 6})
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func Test_tileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		lat, lng float64
+		exp      string
+	}{
+		{lat: 45, lng: 6, exp: "N45E006.hgt"},
+		{lat: -1, lng: -78, exp: "S01W078.hgt"},
+		{lat: 0, lng: -180, exp: "N00W180.hgt"},
+		{lat: -60, lng: 179, exp: "S60E179.hgt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exp, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, tileName(tt.lat, tt.lng))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package fuzzy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/platx/geonames/download"
+)
+
+// Kind is the kind of name a match was found by.
+type Kind uint8
+
+const (
+	KindName Kind = iota
+	KindASCIIName
+	KindAlternateName
+)
+
+// Reason explains how the matched name relates to the searched one.
+type Reason string
+
+const (
+	// ReasonExact the names are identical
+	ReasonExact Reason = "exact"
+	// ReasonNormalized the names only differ in case, diacritics or punctuation, e.g. `Dusseldorf` and `Düsseldorf`
+	ReasonNormalized Reason = "normalized"
+	// ReasonEditDistance the normalized names differ by a few edits, e.g. `Pittsburg` and `Pittsburgh`
+	ReasonEditDistance Reason = "edit distance"
+)
+
+// Match is a place found by a similar name.
+type Match struct {
+	download.GeoName
+
+	// Score similarity between 0 and 1, 1 - edit distance / length of the longer normalized name
+	Score float64
+	// Name the matched name of the place
+	Name string
+	// Kind of the matched name
+	Kind Kind
+	// Language of the matched alternate name, empty for names without language
+	Language string
+	// Reason how the matched name relates to the searched one
+	Reason Reason
+	// Distance number of edits between the normalized names
+	Distance int
+	// SharedTrigrams number of trigrams the names have in common, see Trigrams
+	SharedTrigrams int
+	// Trigrams number of trigrams of the longer name
+	Trigrams int
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindName:
+		return "name"
+	case KindASCIIName:
+		return "ascii name"
+	case KindAlternateName:
+		return "alternate name"
+	default:
+		return fmt.Sprintf("kind(%d)", uint8(k))
+	}
+}
+
+// Explain describes why the place matched,
+// e.g. `alternate name "Kiew" (de): edit distance 1, 2/4 trigrams, score 0.75`.
+func (m Match) Explain() string {
+	var res strings.Builder
+
+	fmt.Fprintf(&res, "%s %q", m.Kind, m.Name)
+
+	if m.Language != "" {
+		fmt.Fprintf(&res, " (%s)", m.Language)
+	}
+
+	fmt.Fprintf(&res, ": %s", m.Reason)
+
+	if m.Reason == ReasonEditDistance {
+		fmt.Fprintf(&res, " %d", m.Distance)
+	}
+
+	fmt.Fprintf(&res, ", %d/%d trigrams, score %.2f", m.SharedTrigrams, m.Trigrams, m.Score)
+
+	return res.String()
+}
@@ -0,0 +1,33 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Kind_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "name", KindName.String())
+	assert.Equal(t, "ascii name", KindASCIIName.String())
+	assert.Equal(t, "alternate name", KindAlternateName.String())
+	assert.Equal(t, "kind(7)", Kind(7).String())
+}
+
+func Test_Match_Explain(t *testing.T) {
+	t.Parallel()
+
+	match := Match{
+		Score:          0.75,
+		Name:           "Kiew",
+		Kind:           KindAlternateName,
+		Language:       "de",
+		Reason:         ReasonEditDistance,
+		Distance:       1,
+		SharedTrigrams: 2,
+		Trigrams:       4,
+	}
+
+	assert.Equal(t, `alternate name "Kiew" (de): edit distance 1, 2/4 trigrams, score 0.75`, match.Explain())
+}
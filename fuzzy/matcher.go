@@ -0,0 +1,231 @@
+// Package fuzzy provides typo-tolerant matching of place names over GeoNames names and alternate names.
+package fuzzy
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/internal/text"
+)
+
+const defaultThreshold = 0.75
+
+var ErrMissingPlaces = errors.New("places iterator is required")
+
+// Source provides the names to match, the alternate names catch historic and foreign spellings.
+type Source struct {
+	// Places records to match, required
+	Places download.Iterator[download.GeoName]
+	// AlternateNames names in other languages and historic names, e.g. `Kiev` for `Kyiv`, optional
+	AlternateNames download.Iterator[download.AlternateName]
+}
+
+// Matcher finds places by misspelled names through a trigram index. Match only reads the index and may be called
+// concurrently.
+type Matcher struct {
+	items     []download.GeoName
+	names     []entry
+	trigrams  map[string][]int
+	threshold float64
+}
+
+// entry is a single name of a place.
+type entry struct {
+	item       int
+	value      string
+	normalized []rune
+	kind       Kind
+	language   string
+	trigrams   int
+}
+
+type Option func(*Matcher)
+
+// WithThreshold sets the minimal similarity (0-1) of returned matches, default is 0.75.
+func WithThreshold(threshold float64) Option {
+	return func(m *Matcher) {
+		m.threshold = threshold
+	}
+}
+
+// Build reads all source iterators and builds the trigram index.
+func Build(src Source, opts ...Option) (*Matcher, error) {
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	res := &Matcher{
+		items:     make([]download.GeoName, 0),
+		names:     make([]entry, 0),
+		trigrams:  make(map[string][]int),
+		threshold: defaultThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	byID := make(map[uint64]int)
+
+	for item, err := range src.Places {
+		if err != nil {
+			return nil, fmt.Errorf("read places => %w", err)
+		}
+
+		byID[item.ID] = len(res.items)
+		res.items = append(res.items, item)
+
+		res.add(len(res.items)-1, item.Name, KindName, "")
+		res.add(len(res.items)-1, item.NameASCII, KindASCIIName, "")
+
+		for _, alternate := range item.AlternateNames {
+			res.add(len(res.items)-1, alternate, KindAlternateName, "")
+		}
+	}
+
+	if src.AlternateNames != nil {
+		for item, err := range src.AlternateNames {
+			if err != nil {
+				return nil, fmt.Errorf("read alternate names => %w", err)
+			}
+
+			if idx, ok := byID[item.GeoNameID]; ok && gazetteer.IsName(item) {
+				res.add(idx, item.Value, KindAlternateName, item.Language)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Len returns the number of indexed places.
+func (m *Matcher) Len() int {
+	return len(m.items)
+}
+
+// Match returns up to limit places whose names are similar to the given one, best matches first.
+// Each place is returned once with its most similar name.
+func (m *Matcher) Match(name string, limit int) []Match {
+	query := []rune(text.Normalize(name))
+	trigrams := text.Trigrams(name)
+
+	if limit <= 0 || len(query) == 0 {
+		return nil
+	}
+
+	shared := make(map[int]int)
+
+	for _, trigram := range trigrams {
+		for _, id := range m.trigrams[trigram] {
+			shared[id]++
+		}
+	}
+
+	best := make(map[int]Match)
+
+	for id, count := range shared {
+		e := m.names[id]
+
+		match, ok := m.compare(name, query, e)
+		if !ok {
+			continue
+		}
+
+		match.SharedTrigrams = count
+		match.Trigrams = max(len(trigrams), e.trigrams)
+
+		if current, ok := best[e.item]; !ok || compareMatches(match, current) < 0 {
+			best[e.item] = match
+		}
+	}
+
+	res := make([]Match, 0, len(best))
+
+	for _, match := range best {
+		res = append(res, match)
+	}
+
+	slices.SortFunc(res, compareMatches)
+
+	return res[:min(limit, len(res))]
+}
+
+// compare scores the name entry against the normalized query by its edit distance.
+func (m *Matcher) compare(name string, query []rune, e entry) (Match, bool) {
+	length := max(len(query), len(e.normalized))
+	limit := int(float64(length) * (1 - m.threshold))
+
+	distance := text.EditDistance(query, e.normalized, limit)
+	if distance > limit {
+		return Match{}, false
+	}
+
+	score := 1 - float64(distance)/float64(length)
+	if score < m.threshold {
+		return Match{}, false
+	}
+
+	reason := ReasonEditDistance
+
+	switch {
+	case distance == 0 && name == e.value:
+		reason = ReasonExact
+	case distance == 0:
+		reason = ReasonNormalized
+	}
+
+	return Match{
+		GeoName:        m.items[e.item],
+		Score:          score,
+		Name:           e.value,
+		Kind:           e.kind,
+		Language:       e.language,
+		Reason:         reason,
+		Distance:       distance,
+		SharedTrigrams: 0,
+		Trigrams:       0,
+	}, true
+}
+
+func (m *Matcher) add(item int, name string, kind Kind, language string) {
+	normalized := text.Normalize(name)
+	if normalized == "" {
+		return
+	}
+
+	// the same name is indexed once per place, e.g. when the ascii name equals the name
+	for i := len(m.names) - 1; i >= 0 && m.names[i].item == item; i-- {
+		if string(m.names[i].normalized) == normalized {
+			return
+		}
+	}
+
+	trigrams := text.Trigrams(name)
+
+	for _, trigram := range trigrams {
+		m.trigrams[trigram] = append(m.trigrams[trigram], len(m.names))
+	}
+
+	m.names = append(m.names, entry{
+		item:       item,
+		value:      name,
+		normalized: []rune(normalized),
+		kind:       kind,
+		language:   language,
+		trigrams:   len(trigrams),
+	})
+}
+
+// compareMatches orders by score, then main names before alternate names, then by population.
+func compareMatches(a, b Match) int {
+	return cmp.Or(
+		cmp.Compare(b.Score, a.Score),
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(b.Population, a.Population),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
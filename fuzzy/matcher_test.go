@@ -0,0 +1,190 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil/fixture"
+)
+
+func newTestMatcher(t *testing.T, opts ...Option) *Matcher {
+	t.Helper()
+
+	res, err := Build(Source{
+		Places:         download.FromSlice(fixture.Places()),
+		AlternateNames: download.FromSlice(fixture.AlternateNames()),
+	}, opts...)
+
+	require.NoError(t, err)
+
+	return res
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, len(fixture.Places()), newTestMatcher(t).Len())
+	})
+
+	t.Run("links are no names", func(t *testing.T) {
+		t.Parallel()
+
+		for _, match := range newTestMatcher(t).Match("https://en.wikipedia.org/wiki/Paris", 10) {
+			assert.NotEqual(t, "link", match.Language)
+		}
+	})
+
+	tests := []struct {
+		name string
+		src  Source
+		err  string
+	}{
+		{
+			name: "missing places",
+			src:  Source{},
+			err:  ErrMissingPlaces.Error(),
+		},
+		{
+			name: "places error",
+			src:  Source{Places: fixture.Failing(fixture.Places(), assert.AnError)},
+			err:  "read places => " + assert.AnError.Error(),
+		},
+		{
+			name: "alternate names error",
+			src:  Source{Places: download.FromSlice(fixture.Places()), AlternateNames: fixture.Failing(fixture.AlternateNames(), assert.AnError)},
+			err:  "read alternate names => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Build(tt.src)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func Test_Matcher_Match(t *testing.T) {
+	t.Parallel()
+
+	matcher := newTestMatcher(t)
+
+	tests := []struct {
+		name    string
+		given   string
+		limit   int
+		exp     []uint64
+		explain []string
+	}{
+		{
+			name:  "exact and misspelled",
+			given: "Pittsburg",
+			limit: 10,
+			exp:   []uint64{4285813, 5206379},
+			explain: []string{
+				`name "Pittsburg": exact, 9/9 trigrams, score 1.00`,
+				`name "Pittsburgh": edit distance 1, 8/10 trigrams, score 0.90`,
+			},
+		},
+		{
+			name:    "alternate name",
+			given:   "Kiev",
+			limit:   10,
+			exp:     []uint64{703448},
+			explain: []string{`alternate name "Kiev": exact, 4/4 trigrams, score 1.00`},
+		},
+		{
+			name:    "alternate name with language",
+			given:   "kiew",
+			limit:   10,
+			exp:     []uint64{703448},
+			explain: []string{`alternate name "Kiew" (de): normalized, 4/4 trigrams, score 1.00`},
+		},
+		{
+			name:    "diacritics folded",
+			given:   "Dusseldorf",
+			limit:   10,
+			exp:     []uint64{2934246},
+			explain: []string{`name "Düsseldorf": normalized, 10/10 trigrams, score 1.00`},
+		},
+		{
+			name:    "non latin script",
+			given:   "київ",
+			limit:   10,
+			exp:     []uint64{703448},
+			explain: []string{`alternate name "Київ" (uk): normalized, 5/5 trigrams, score 1.00`},
+		},
+		{
+			name:  "limit",
+			given: "Pittsburgh",
+			limit: 1,
+			exp:   []uint64{5206379},
+			explain: []string{
+				`name "Pittsburgh": exact, 10/10 trigrams, score 1.00`,
+			},
+		},
+		{
+			name:  "below threshold",
+			given: "Bern",
+			limit: 10,
+			exp:   []uint64{},
+		},
+		{
+			name:  "empty",
+			given: " ",
+			limit: 10,
+			exp:   []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := matcher.Match(tt.given, tt.limit)
+
+			assert.Equal(t, tt.exp, ids(actual))
+
+			if tt.explain != nil {
+				explained := make([]string, 0, len(actual))
+				for _, match := range actual {
+					explained = append(explained, match.Explain())
+				}
+
+				assert.Equal(t, tt.explain, explained)
+			}
+		})
+	}
+
+	t.Run("threshold", func(t *testing.T) {
+		t.Parallel()
+
+		actual := newTestMatcher(t, WithThreshold(0.6)).Match("Bern", 10)
+
+		require.Len(t, actual, 1)
+		assert.Equal(t, "Berlin", actual[0].Name)
+		assert.Equal(t, ReasonEditDistance, actual[0].Reason)
+		assert.Equal(t, 2, actual[0].Distance)
+		assert.InDelta(t, 0.667, actual[0].Score, 1e-3)
+	})
+}
+
+func ids(items []Match) []uint64 {
+	res := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.ID)
+	}
+
+	return res
+}
@@ -0,0 +1,170 @@
+// Package gazetteer resolves the codes of downloaded records into the names returned by the webservice and tells
+// names apart from codes among the alternate names, shared by the search, reverse, fuzzy, autocomplete, resolve and
+// server packages.
+package gazetteer
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+// minPopulation is the population threshold of the cities categories, see value.Cities.
+var minPopulation = map[value.Cities]int64{
+	value.Cities500:   500,
+	value.Cities1000:  1000,
+	value.Cities5000:  5000,
+	value.Cities15000: 15000,
+}
+
+// codeLanguages languages of alternate names which are links or codes rather than names, e.g. postal or airport codes.
+var codeLanguages = []string{"link", "wkdt", "unlc", "post", "iata", "icao", "faac"}
+
+// Gazetteer maps country and admin division codes to their records, it is only read after Build.
+type Gazetteer struct {
+	adminFirst  map[string]download.AdminDivision
+	adminSecond map[string]download.AdminDivision
+	countries   map[value.CountryCode]download.Country
+}
+
+// Build reads the given iterators, nil iterators are treated as empty.
+func Build(
+	adminFirst download.Iterator[download.AdminDivision],
+	adminSecond download.Iterator[download.AdminDivision],
+	countries download.Iterator[download.Country],
+) (*Gazetteer, error) {
+	var err error
+
+	res := &Gazetteer{
+		adminFirst:  nil,
+		adminSecond: nil,
+		countries:   nil,
+	}
+
+	if res.adminFirst, err = collect(adminFirst, adminDivisionCode); err != nil {
+		return nil, fmt.Errorf("read first admin divisions => %w", err)
+	}
+
+	if res.adminSecond, err = collect(adminSecond, adminDivisionCode); err != nil {
+		return nil, fmt.Errorf("read second admin divisions => %w", err)
+	}
+
+	if res.countries, err = collect(countries, countryCode); err != nil {
+		return nil, fmt.Errorf("read countries => %w", err)
+	}
+
+	return res, nil
+}
+
+// MinPopulation returns the population threshold of the cities category, 0 when not set.
+func MinPopulation(cities value.Cities) int64 {
+	return minPopulation[cities]
+}
+
+// IsName reports whether the alternate name is a name of the place, not a link or a code.
+func IsName(item download.AlternateName) bool {
+	return !slices.Contains(codeLanguages, item.Language)
+}
+
+// Country returns the country info of the given code.
+func (g *Gazetteer) Country(code value.CountryCode) (download.Country, bool) {
+	res, ok := g.countries[code]
+
+	return res, ok
+}
+
+// Countries returns all country infos in no particular order.
+func (g *Gazetteer) Countries() iter.Seq[download.Country] {
+	return maps.Values(g.countries)
+}
+
+// AdminDivisionsFirst returns all first level admin divisions in no particular order.
+func (g *Gazetteer) AdminDivisionsFirst() iter.Seq[download.AdminDivision] {
+	return maps.Values(g.adminFirst)
+}
+
+// AdminDivisionFirst returns the first level admin division of the record.
+func (g *Gazetteer) AdminDivisionFirst(item download.GeoName) download.AdminDivision {
+	return g.adminFirst[adminKey(item.CountryCode, item.AdminCode.First)]
+}
+
+// AdminDivisionSecond returns the second level admin division of the record.
+func (g *Gazetteer) AdminDivisionSecond(item download.GeoName) download.AdminDivision {
+	return g.adminSecond[adminKey(item.CountryCode, item.AdminCode.First, item.AdminCode.Second)]
+}
+
+// GeoName converts the record into the webservice representation, feature names are not available
+// in the dump files and left empty.
+func (g *Gazetteer) GeoName(item download.GeoName) webservice.GeoName {
+	country := g.countries[item.CountryCode]
+	adminFirst := g.AdminDivisionFirst(item)
+	adminSecond := g.AdminDivisionSecond(item)
+
+	return webservice.GeoName{
+		ID: item.ID,
+		Country: value.Country{
+			ID:   country.ID,
+			Code: item.CountryCode,
+			Name: country.Name,
+		},
+		AdminSubdivision: value.AdminDivisions{
+			First:  value.AdminDivision{ID: adminFirst.ID, Code: item.AdminCode.First, Name: adminFirst.Name},
+			Second: value.AdminDivision{ID: adminSecond.ID, Code: item.AdminCode.Second, Name: adminSecond.Name},
+			Third:  value.AdminDivision{ID: 0, Code: item.AdminCode.Third, Name: ""},
+			Fourth: value.AdminDivision{ID: 0, Code: item.AdminCode.Fourth, Name: ""},
+			Fifth:  value.AdminDivision{ID: 0, Code: item.AdminCode.Fifth, Name: ""},
+		},
+		Feature: value.Feature{
+			Class:     item.FeatureClass,
+			ClassName: "",
+			Code:      item.FeatureCode,
+			CodeName:  "",
+		},
+		Position:    item.Position,
+		Name:        item.Name,
+		ToponymName: item.Name,
+		Population:  uint64(max(item.Population, 0)),
+	}
+}
+
+// adminKey returns the admin division code as used in the admin*Codes.txt files, e.g. `US.CA.037`.
+func adminKey(country value.CountryCode, codes ...string) string {
+	res := string(country)
+
+	for _, code := range codes {
+		res += "." + code
+	}
+
+	return res
+}
+
+func adminDivisionCode(item download.AdminDivision) string {
+	return item.Code
+}
+
+func countryCode(item download.Country) value.CountryCode {
+	return item.Code
+}
+
+func collect[K comparable, V any](items download.Iterator[V], key func(item V) K) (map[K]V, error) {
+	res := make(map[K]V)
+
+	if items == nil {
+		return res, nil
+	}
+
+	for item, err := range items {
+		if err != nil {
+			return nil, err
+		}
+
+		res[key(item)] = item
+	}
+
+	return res, nil
+}
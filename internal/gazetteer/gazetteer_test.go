@@ -0,0 +1,137 @@
+package gazetteer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil iterators", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := Build(nil, nil, nil)
+
+		require.NoError(t, err)
+
+		_, ok := res.Country(value.CountryCodeFrance)
+		assert.False(t, ok)
+	})
+
+	tests := []struct {
+		name        string
+		adminFirst  download.Iterator[download.AdminDivision]
+		adminSecond download.Iterator[download.AdminDivision]
+		countries   download.Iterator[download.Country]
+		err         string
+	}{
+		{
+			name:       "first admin divisions error",
+			adminFirst: fixture.Failing[download.AdminDivision](nil, assert.AnError),
+			err:        "read first admin divisions => " + assert.AnError.Error(),
+		},
+		{
+			name:        "second admin divisions error",
+			adminSecond: fixture.Failing[download.AdminDivision](nil, assert.AnError),
+			err:         "read second admin divisions => " + assert.AnError.Error(),
+		},
+		{
+			name:      "countries error",
+			countries: fixture.Failing[download.Country](nil, assert.AnError),
+			err:       "read countries => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := Build(tt.adminFirst, tt.adminSecond, tt.countries)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, res)
+		})
+	}
+}
+
+func Test_Gazetteer_GeoName(t *testing.T) {
+	t.Parallel()
+
+	res, err := Build(
+		download.FromSlice(fixture.AdminDivisionsFirst()),
+		download.FromSlice(fixture.AdminDivisionsSecond()),
+		download.FromSlice(fixture.Countries()),
+	)
+
+	require.NoError(t, err)
+
+	actual := res.GeoName(fixture.Place(2988507))
+
+	assert.Equal(t, webservice.GeoName{
+		ID:      2988507,
+		Country: value.Country{ID: 3017382, Code: value.CountryCodeFrance, Name: "France"},
+		AdminSubdivision: value.AdminDivisions{
+			First:  value.AdminDivision{ID: 3012874, Code: "11", Name: "Île-de-France"},
+			Second: value.AdminDivision{ID: 2968815, Code: "75", Name: "Paris"},
+			Third:  value.AdminDivision{Code: "751"},
+			Fourth: value.AdminDivision{Code: "75056"},
+		},
+		Feature:     value.Feature{Class: "P", Code: "PPLC"},
+		Position:    value.Position{Latitude: 48.85341, Longitude: 2.3488},
+		Name:        "Paris",
+		ToponymName: "Paris",
+		Population:  2138551,
+	}, actual)
+}
+
+func Test_Gazetteer_Countries(t *testing.T) {
+	t.Parallel()
+
+	res, err := Build(download.FromSlice(fixture.AdminDivisionsFirst()), nil, download.FromSlice(fixture.Countries()))
+
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, fixture.Countries(), slices.Collect(res.Countries()))
+	assert.ElementsMatch(t, fixture.AdminDivisionsFirst(), slices.Collect(res.AdminDivisionsFirst()))
+}
+
+func Test_MinPopulation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(1000), MinPopulation(value.Cities1000))
+	assert.Zero(t, MinPopulation(""))
+}
+
+func Test_IsName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		language string
+		exp      bool
+	}{
+		{language: "", exp: true},
+		{language: "de", exp: true},
+		{language: "abbr", exp: true},
+		{language: "link", exp: false},
+		{language: "post", exp: false},
+		{language: "iata", exp: false},
+		{language: "icao", exp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, IsName(download.AlternateName{Language: tt.language}))
+		})
+	}
+}
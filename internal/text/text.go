@@ -0,0 +1,82 @@
+// Package text provides the name normalization shared by the offline name lookups.
+package text
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/platx/geonames/translit"
+)
+
+// Normalize romanizes and lower cases the text, so `Zürich` matches `zurich` and `Київ` matches `kiyiv`.
+// Punctuation is reduced to single spaces.
+func Normalize(text string) string {
+	return translit.Key(text)
+}
+
+// Tokenize splits normalized text into words.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// EditDistance returns the Levenshtein distance between a and b, or limit+1 once it exceeds the limit.
+func EditDistance(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+
+		if rowMin > limit {
+			return limit + 1
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return min(prev[len(b)], limit+1)
+}
+
+// Trigrams returns the distinct character trigrams of the normalized text padded with spaces,
+// e.g. ` ki`, `kie`, `iev`, `ev `.
+func Trigrams(text string) []string {
+	const size = 3
+
+	runes := []rune(" " + strings.Join(Tokenize(text), " ") + " ")
+	if len(runes) < size {
+		return nil
+	}
+
+	res := make([]string, 0, len(runes)-size+1)
+
+	for i := 0; i+size <= len(runes); i++ {
+		trigram := string(runes[i : i+size])
+		if !slices.Contains(res, trigram) {
+			res = append(res, trigram)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,61 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tokenize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"zurich", "zh"}, Tokenize("Zürich (ZH)"))
+	assert.Equal(t, []string{"saint", "etienne"}, Tokenize("Saint-Étienne"))
+	assert.Equal(t, []string{"strasse", "5"}, Tokenize("Straße 5"))
+	assert.Equal(t, []string{"parizh"}, Tokenize("Париж"))
+	assert.Empty(t, Tokenize(" ,; "))
+}
+
+func Test_Normalize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "strasse", Normalize("Straße"))
+	assert.Equal(t, "lodz", Normalize("Łódź"))
+	assert.Equal(t, "zurich", Normalize("Zu\u0308rich"))
+	assert.Equal(t, "kiyiv", Normalize("Київ"))
+	assert.Equal(t, "st malo", Normalize("St.-Malo"))
+}
+
+func Test_EditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b  string
+		limit int
+		exp   int
+	}{
+		{a: "london", b: "london", limit: 2, exp: 0},
+		{a: "londn", b: "london", limit: 2, exp: 1},
+		{a: "lodnon", b: "london", limit: 2, exp: 2},
+		{a: "paris", b: "london", limit: 2, exp: 3},
+		{a: "a", b: "abcd", limit: 2, exp: 3},
+		{a: "", b: "ab", limit: 2, exp: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, EditDistance([]rune(tt.a), []rune(tt.b), tt.limit))
+		})
+	}
+}
+
+func Test_Trigrams(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{" ki", "kie", "iev", "ev "}, Trigrams("Kiev"))
+	assert.Equal(t, []string{" st", "st ", "t m", " ma", "mal", "alo", "lo "}, Trigrams("St. Malo"))
+	assert.Equal(t, []string{" a "}, Trigrams("a"))
+	assert.Nil(t, Trigrams(""))
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+)
+
+// credentials matches the query parameters of the credentials in URLs of error messages.
+var credentials = regexp.MustCompile(`\b(username|token)=[^&\s"]*`)
+
+// Logging logs every call with its endpoint, request, status, error code and duration. Successful calls are
+// logged at debug level, failed ones at warn level with the error, credentials are redacted.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+
+			attrs := []slog.Attr{
+				slog.String("endpoint", call.Endpoint),
+				slog.Int("status", call.Status),
+				slog.Duration("duration", call.Duration),
+				slog.Bool("cached", call.Cached),
+			}
+
+			if call.Request != nil {
+				attrs = append(attrs, slog.Any("request", call.Request))
+			}
+
+			if call.ErrCode != 0 {
+				attrs = append(attrs, slog.Int("code", int(call.ErrCode)))
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", Redact(err.Error())))
+
+				logger.LogAttrs(ctx, slog.LevelWarn, "geonames call failed", attrs...)
+
+				return err
+			}
+
+			logger.LogAttrs(ctx, slog.LevelDebug, "geonames call", attrs...)
+
+			return nil
+		}
+	}
+}
+
+// Redact replaces the values of the username and token query parameters in the text.
+func Redact(text string) string {
+	return credentials.ReplaceAllString(text, "${1}=REDACTED")
+}
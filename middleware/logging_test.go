@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+)
+
+func Test_Logging(t *testing.T) {
+	t.Parallel()
+
+	newLogger := func(buf *bytes.Buffer) *slog.Logger {
+		return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+
+				return attr
+			},
+		}))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		handler := Chain(func(_ context.Context, call *Call) error {
+			call.Status = 200
+			call.Duration = 1500 * time.Millisecond
+
+			return nil
+		}, Logging(newLogger(&buf)))
+
+		type request struct{ Name string }
+
+		require.NoError(t, handler(context.Background(), &Call{Endpoint: "search", Request: request{Name: "paris"}}))
+		assert.Equal(
+			t,
+			"level=DEBUG msg=\"geonames call\" endpoint=search status=200 duration=1.5s cached=false request={Name:paris}\n",
+			buf.String(),
+		)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		exp := errors.New(`Get "https://secure.geonames.org/getJSON?geonameId=1&token=secret&username=demo": EOF`)
+
+		handler := Chain(func(_ context.Context, call *Call) error {
+			call.Status = 200
+			call.ErrCode = value.ErrCodeHourlyLimitExceeded
+
+			return exp
+		}, Logging(newLogger(&buf)))
+
+		require.ErrorIs(t, handler(context.Background(), &Call{Endpoint: "get"}), exp)
+		assert.Equal(
+			t,
+			"level=WARN msg=\"geonames call failed\" endpoint=get status=200 duration=0s cached=false code=19 "+
+				"error=\"Get \\\"https://secure.geonames.org/getJSON?geonameId=1&token=REDACTED&username=REDACTED\\\": EOF\"\n",
+			buf.String(),
+		)
+	})
+}
+
+func Test_Redact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		given string
+		exp   string
+	}{
+		{
+			name:  "query",
+			given: "https://secure.geonames.org/searchJSON?username=demo&q=paris&token=secret",
+			exp:   "https://secure.geonames.org/searchJSON?username=REDACTED&q=paris&token=REDACTED",
+		},
+		{
+			name:  "quoted",
+			given: `Get "https://secure.geonames.org/getJSON?username=demo": EOF`,
+			exp:   `Get "https://secure.geonames.org/getJSON?username=REDACTED": EOF`,
+		},
+		{
+			name:  "other parameters",
+			given: "https://secure.geonames.org/searchJSON?name=username",
+			exp:   "https://secure.geonames.org/searchJSON?name=username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, Redact(tt.given))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/platx/geonames/value"
+)
+
+// Result of a call counted by Metrics.
+type Result string
+
+const (
+	ResultOK     Result = "ok"
+	ResultCached Result = "cached"
+	ResultError  Result = "error"
+)
+
+// Metrics records the calls per endpoint, implemented by adapters of metric libraries or the Recorder.
+type Metrics interface {
+	// IncRequests increments the counter of calls of the endpoint, code is the error code of the webservice if any
+	IncRequests(endpoint string, result Result, code value.ErrCode)
+	// ObserveDuration records the duration of a call in the latency histogram of the endpoint
+	ObserveDuration(endpoint string, duration time.Duration)
+}
+
+// Instrument records every call in the metrics.
+func Instrument(metrics Metrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+
+			result := ResultOK
+
+			switch {
+			case err != nil:
+				result = ResultError
+			case call.Cached:
+				result = ResultCached
+			}
+
+			metrics.IncRequests(call.Endpoint, result, call.ErrCode)
+			metrics.ObserveDuration(call.Endpoint, call.Duration)
+
+			return err
+		}
+	}
+}
+
+// DefaultBuckets are the upper bounds of the latency histograms of the Recorder.
+func DefaultBuckets() []time.Duration {
+	return []time.Duration{
+		10 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2500 * time.Millisecond,
+		5 * time.Second,
+		10 * time.Second,
+	}
+}
+
+// EndpointStats are the counters and the latency histogram of an endpoint.
+type EndpointStats struct {
+	// Requests by result
+	Requests map[Result]int
+	// Errors by error code of the webservice
+	Errors map[value.ErrCode]int
+	// Buckets holds the number of calls up to the bound of the same index in the buckets of the Recorder,
+	// the last one counts the slower calls
+	Buckets []int
+	// Total duration of all calls
+	Total time.Duration
+}
+
+// Recorder is an in-memory Metrics, for tests or to be exported by the application.
+type Recorder struct {
+	buckets   []time.Duration
+	mu        sync.Mutex
+	endpoints map[string]*EndpointStats
+}
+
+// NewRecorder returns a recorder with the given histogram buckets in ascending order, DefaultBuckets without.
+func NewRecorder(buckets ...time.Duration) *Recorder {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets()
+	}
+
+	return &Recorder{
+		buckets:   slices.Sorted(slices.Values(buckets)),
+		mu:        sync.Mutex{},
+		endpoints: map[string]*EndpointStats{},
+	}
+}
+
+func (r *Recorder) IncRequests(endpoint string, result Result, code value.ErrCode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.endpoint(endpoint)
+	stats.Requests[result]++
+
+	if code != 0 {
+		stats.Errors[code]++
+	}
+}
+
+func (r *Recorder) ObserveDuration(endpoint string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.endpoint(endpoint)
+	idx, _ := slices.BinarySearch(r.buckets, duration)
+
+	stats.Buckets[idx]++
+	stats.Total += duration
+}
+
+// Buckets returns the upper bounds of the latency histograms.
+func (r *Recorder) Buckets() []time.Duration {
+	return slices.Clone(r.buckets)
+}
+
+// Snapshot returns a copy of the stats by endpoint.
+func (r *Recorder) Snapshot() map[string]EndpointStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make(map[string]EndpointStats, len(r.endpoints))
+
+	for endpoint, stats := range r.endpoints {
+		res[endpoint] = EndpointStats{
+			Requests: maps.Clone(stats.Requests),
+			Errors:   maps.Clone(stats.Errors),
+			Buckets:  slices.Clone(stats.Buckets),
+			Total:    stats.Total,
+		}
+	}
+
+	return res
+}
+
+func (r *Recorder) endpoint(endpoint string) *EndpointStats {
+	stats, ok := r.endpoints[endpoint]
+	if !ok {
+		stats = &EndpointStats{
+			Requests: map[Result]int{},
+			Errors:   map[value.ErrCode]int{},
+			Buckets:  make([]int, len(r.buckets)+1),
+			Total:    0,
+		}
+		r.endpoints[endpoint] = stats
+	}
+
+	return stats
+}
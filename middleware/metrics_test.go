@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+)
+
+func Test_Instrument(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder(100*time.Millisecond, time.Second)
+
+	calls := []Call{
+		{Endpoint: "search", Duration: 50 * time.Millisecond},
+		{Endpoint: "search", Cached: true, Duration: 100 * time.Millisecond},
+		{Endpoint: "search", ErrCode: value.ErrCodeServerOverloaded, Duration: 2 * time.Second},
+		{Endpoint: "get", Duration: 500 * time.Millisecond},
+	}
+
+	for _, given := range calls {
+		handler := Instrument(recorder)(func(_ context.Context, call *Call) error {
+			*call = given
+
+			if given.ErrCode != 0 {
+				return errors.New("server overloaded")
+			}
+
+			return nil
+		})
+
+		_ = handler(context.Background(), &Call{})
+	}
+
+	assert.Equal(t, map[string]EndpointStats{
+		"search": {
+			Requests: map[Result]int{ResultOK: 1, ResultCached: 1, ResultError: 1},
+			Errors:   map[value.ErrCode]int{value.ErrCodeServerOverloaded: 1},
+			Buckets:  []int{2, 0, 1},
+			Total:    2150 * time.Millisecond,
+		},
+		"get": {
+			Requests: map[Result]int{ResultOK: 1},
+			Errors:   map[value.ErrCode]int{},
+			Buckets:  []int{0, 1, 0},
+			Total:    500 * time.Millisecond,
+		},
+	}, recorder.Snapshot())
+}
+
+func Test_NewRecorder(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultBuckets(), NewRecorder().Buckets())
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Second}, NewRecorder(time.Second, time.Millisecond).Buckets())
+
+	recorder := NewRecorder()
+	recorder.IncRequests("search", ResultOK, 0)
+
+	snapshot := recorder.Snapshot()
+	snapshot["search"].Requests[ResultOK] = 10
+
+	require.Contains(t, recorder.Snapshot(), "search")
+	assert.Equal(t, 1, recorder.Snapshot()["search"].Requests[ResultOK])
+}
@@ -0,0 +1,40 @@
+// Package middleware intercepts the calls of the webservice and download clients, to log, time or count them.
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/platx/geonames/value"
+)
+
+// Call is one call of a client, the outcome fields are set once the next Handler returned.
+type Call struct {
+	// Endpoint name like "search" for the webservice or the file name like "cities500.zip" for downloads
+	Endpoint string
+	// Request struct of the webservice method, nil for downloads
+	Request any
+	// Status code of the last HTTP response, 0 if none was received
+	Status int
+	// ErrCode of the error envelope of the webservice, 0 without one
+	ErrCode value.ErrCode
+	// Cached reports whether the response was served from the cache or shared with a concurrent identical call
+	Cached bool
+	// Duration of the call, retries and waits for credits included
+	Duration time.Duration
+}
+
+// Handler handles the call and sets its outcome.
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps the next Handler, it can act before and after the call.
+type Middleware func(next Handler) Handler
+
+// Chain wraps the handler in the middlewares, the first one is the outermost.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Chain(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				order = append(order, name+" before")
+				err := next(ctx, call)
+				order = append(order, name+" after")
+
+				return err
+			}
+		}
+	}
+
+	handler := Chain(func(_ context.Context, call *Call) error {
+		order = append(order, call.Endpoint)
+
+		return nil
+	}, trace("first"), trace("second"))
+
+	require.NoError(t, handler(context.Background(), &Call{Endpoint: "search"}))
+	assert.Equal(t, []string{"first before", "second before", "search", "second after", "first after"}, order)
+}
@@ -0,0 +1,265 @@
+package resolve
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/platx/geonames/internal/text"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+const (
+	defaultLimit = 10
+	// alternateNameScore factor of places matched by an alternate name rather than their name
+	alternateNameScore = 0.9
+	// unrecognizedScore factor of each part which is neither a known admin division nor a country
+	unrecognizedScore = 0.5
+	// fallbackConfidence total confidence of the fallback search results
+	fallbackConfidence = 0.5
+	// populationOffset keeps places without population from having no prior
+	populationOffset = 10
+)
+
+// Candidate is a place the location string may refer to.
+type Candidate struct {
+	webservice.GeoName
+
+	// Confidence between 0 and 1, the confidences of all candidates add up to at most 1
+	Confidence float64
+	// Fallback the candidate was found by the fallback searcher
+	Fallback bool
+}
+
+// interpretation is a split of the location string into a place name and admin or country parts.
+type interpretation struct {
+	place      string
+	qualifiers []string
+}
+
+// Resolve returns up to limit candidates for the location string, the most probable first. Parts are separated
+// by commas, e.g. `Paris, Texas`, trailing admin or country parts may also be separated by spaces only,
+// e.g. `Springfield IL`. The fallback searcher is queried when no place matches locally.
+func (r *Resolver) Resolve(ctx context.Context, location string, limit int) ([]Candidate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	interpretations := r.interpretations(location)
+	if len(interpretations) == 0 {
+		return nil, nil
+	}
+
+	scores := make(map[int]float64)
+
+	for _, in := range interpretations {
+		for _, n := range r.names[in.place] {
+			if score := r.score(n, in.qualifiers); score > scores[n.place] {
+				scores[n.place] = score
+			}
+		}
+	}
+
+	if len(scores) == 0 {
+		return r.search(ctx, interpretations, limit)
+	}
+
+	return r.candidates(scores, limit), nil
+}
+
+// interpretations splits the location into the place name and the admin or country parts. The whole first part
+// is always taken as place name, trailing words of it are split off when they are known admin or country names.
+func (r *Resolver) interpretations(location string) []interpretation {
+	parts := make([]string, 0)
+
+	for _, part := range strings.FieldsFunc(location, func(r rune) bool { return r == ',' || r == ';' }) {
+		if key := text.Normalize(part); key != "" {
+			parts = append(parts, key)
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil
+	}
+
+	res := []interpretation{{place: parts[0], qualifiers: parts[1:]}}
+	words := strings.Fields(parts[0])
+
+	for i := len(words) - 1; i > 0; i-- {
+		if qualifiers := r.segment(words[i:]); qualifiers != nil {
+			res = append(res, interpretation{
+				place:      strings.Join(words[:i], " "),
+				qualifiers: append(qualifiers, parts[1:]...),
+			})
+		}
+	}
+
+	return res
+}
+
+// segment splits the words into known admin or country names, longest first, nil when that is not possible.
+func (r *Resolver) segment(words []string) []string {
+	if len(words) == 0 {
+		return []string{}
+	}
+
+	for end := len(words); end > 0; end-- {
+		part := strings.Join(words[:end], " ")
+		if !r.recognized(part) {
+			continue
+		}
+
+		if rest := r.segment(words[end:]); rest != nil {
+			return append([]string{part}, rest...)
+		}
+	}
+
+	return nil
+}
+
+// recognized reports whether the part is a known admin division or country.
+func (r *Resolver) recognized(part string) bool {
+	return len(r.countries[part]) > 0 || len(r.admins[part]) > 0
+}
+
+// score returns how well the place matches the admin and country parts, 0 when a part contradicts it,
+// e.g. `Texas` for a place in France.
+func (r *Resolver) score(n name, qualifiers []string) float64 {
+	res := 1.0
+	if n.alternate {
+		res = alternateNameScore
+	}
+
+	item := r.places[n.place]
+	admin := string(item.CountryCode) + "." + item.AdminCode.First
+
+	for _, qualifier := range qualifiers {
+		countries, admins := r.countries[qualifier], r.admins[qualifier]
+
+		switch {
+		case slices.Contains(countries, item.CountryCode) || slices.Contains(admins, admin):
+		case len(countries) > 0 || len(admins) > 0:
+			return 0
+		default:
+			res *= unrecognizedScore
+		}
+	}
+
+	return res
+}
+
+// candidates weights the scores by a population prior, the confidence is the score times the share of the weight.
+func (r *Resolver) candidates(scores map[int]float64, limit int) []Candidate {
+	weights := make(map[int]float64, len(scores))
+	total := 0.0
+
+	for place, score := range scores {
+		weights[place] = score * math.Log10(float64(max(r.places[place].Population, 0)+populationOffset))
+		total += weights[place]
+	}
+
+	res := make([]Candidate, 0, len(scores))
+
+	for place, score := range scores {
+		res = append(res, Candidate{
+			GeoName:    r.gazetteer.GeoName(r.places[place]),
+			Confidence: score * weights[place] / total,
+			Fallback:   false,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b Candidate) int {
+		return cmp.Or(
+			cmp.Compare(b.Confidence, a.Confidence),
+			cmp.Compare(b.Population, a.Population),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	return res[:min(limit, len(res))]
+}
+
+// search queries the fallback searcher with the interpretation having the most known admin or country parts,
+// the results are restricted to the countries of these parts and cut to the limit.
+func (r *Resolver) search(ctx context.Context, interpretations []interpretation, limit int) ([]Candidate, error) {
+	if r.fallback == nil {
+		return nil, nil
+	}
+
+	var req webservice.SearchRequest
+
+	best, known := interpretations[0], -1
+
+	for _, in := range interpretations {
+		count := 0
+
+		for _, qualifier := range in.qualifiers {
+			count += boolInt(r.recognized(qualifier))
+		}
+
+		if count > known {
+			best, known = in, count
+		}
+	}
+
+	req.Name = best.place
+	req.Country = r.qualifierCountries(best.qualifiers)
+	req.NameRequired = true
+
+	items, err := r.fallback.Search(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("search fallback => %w", err)
+	}
+
+	// the results are weighted by their reciprocal rank
+	total := 0.0
+	for i := range items {
+		total += 1 / float64(i+1)
+	}
+
+	res := make([]Candidate, 0, len(items))
+
+	for i, item := range items[:min(limit, len(items))] {
+		res = append(res, Candidate{
+			GeoName:    item,
+			Confidence: fallbackConfidence / float64(i+1) / total,
+			Fallback:   true,
+		})
+	}
+
+	return res, nil
+}
+
+// qualifierCountries returns the countries of the known admin and country parts.
+func (r *Resolver) qualifierCountries(qualifiers []string) []value.CountryCode {
+	res := make([]value.CountryCode, 0)
+
+	for _, qualifier := range qualifiers {
+		for _, country := range r.countries[qualifier] {
+			res = appendOnce(res, country)
+		}
+
+		for _, admin := range r.admins[qualifier] {
+			country, _, _ := strings.Cut(admin, ".")
+			res = appendOnce(res, value.CountryCode(country))
+		}
+	}
+
+	return res
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,222 @@
+package resolve
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+type searcherMock struct {
+	req   webservice.SearchRequest
+	items []webservice.GeoName
+	err   error
+}
+
+func (m *searcherMock) Search(_ context.Context, req webservice.SearchRequest) ([]webservice.GeoName, error) {
+	m.req = req
+
+	return m.items, m.err
+}
+
+func Test_Resolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	resolver := newTestResolver(t)
+
+	prior := func(population float64) float64 {
+		return math.Log10(population + populationOffset)
+	}
+
+	// the city of Paris, Paris in Texas and the department of Paris
+	paris := prior(2138551) + prior(24171) + prior(0)
+
+	tests := []struct {
+		name        string
+		given       string
+		limit       int
+		exp         []uint64
+		confidences []float64
+	}{
+		{
+			name:        "admin code",
+			given:       "Springfield, IL",
+			exp:         []uint64{4250542, 4250544},
+			confidences: []float64{prior(114394) / (prior(114394) + prior(1500)), prior(1500) / (prior(114394) + prior(1500))},
+		},
+		{
+			name:        "admin code without comma",
+			given:       "Springfield IL",
+			exp:         []uint64{4250542, 4250544},
+			confidences: []float64{prior(114394) / (prior(114394) + prior(1500)), prior(1500) / (prior(114394) + prior(1500))},
+		},
+		{
+			name:        "admin name",
+			given:       "Paris, Texas",
+			exp:         []uint64{4717560},
+			confidences: []float64{1},
+		},
+		{
+			name:        "admin name and country code",
+			given:       "paris texas usa",
+			exp:         []uint64{4717560},
+			confidences: []float64{1},
+		},
+		{
+			name:        "ambiguous",
+			given:       "Paris",
+			exp:         []uint64{2988507, 4717560, 2968815},
+			confidences: []float64{prior(2138551) / paris, prior(24171) / paris, prior(0) / paris},
+		},
+		{
+			name:        "limit",
+			given:       "Paris",
+			limit:       1,
+			exp:         []uint64{2988507},
+			confidences: []float64{prior(2138551) / paris},
+		},
+		{
+			name:        "country alternate name",
+			given:       "São Paulo, Brasil",
+			exp:         []uint64{3448439},
+			confidences: []float64{1},
+		},
+		{
+			name:        "admin abbreviation",
+			given:       "London, ON",
+			exp:         []uint64{6058560},
+			confidences: []float64{1},
+		},
+		{
+			name:        "country abbreviation",
+			given:       "London; UK",
+			exp:         []uint64{2643743},
+			confidences: []float64{1},
+		},
+		{
+			name:        "place abbreviation",
+			given:       "NYC",
+			exp:         []uint64{5128581},
+			confidences: []float64{alternateNameScore},
+		},
+		{
+			name:        "place named like an admin division",
+			given:       "New York, NY",
+			exp:         []uint64{5128581},
+			confidences: []float64{alternateNameScore},
+		},
+		{
+			name:  "unrecognized part",
+			given: "London, Narnia",
+			exp:   []uint64{2643743, 6058560},
+			confidences: []float64{
+				unrecognizedScore * prior(8961989) / (prior(8961989) + prior(383822)),
+				unrecognizedScore * prior(383822) / (prior(8961989) + prior(383822)),
+			},
+		},
+		{
+			name:  "contradicting part",
+			given: "Paris, Germany",
+			exp:   []uint64{},
+		},
+		{
+			name:  "empty",
+			given: " , ",
+			exp:   []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := resolver.Resolve(context.Background(), tt.given, tt.limit)
+
+			require.NoError(t, err)
+			require.Len(t, actual, len(tt.exp))
+
+			for i, item := range actual {
+				assert.Equal(t, tt.exp[i], item.ID)
+				assert.InDelta(t, tt.confidences[i], item.Confidence, 1e-9)
+				assert.False(t, item.Fallback)
+			}
+		})
+	}
+
+	t.Run("geoname", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := resolver.Resolve(context.Background(), "Paris, Texas", 0)
+
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "Texas", actual[0].AdminSubdivision.First.Name)
+		assert.Equal(t, "United States", actual[0].Country.Name)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := resolver.Resolve(ctx, "Paris", 0)
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func Test_Resolver_Resolve_Fallback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not used when found", func(t *testing.T) {
+		t.Parallel()
+
+		searcher := &searcherMock{}
+
+		actual, err := newTestResolver(t, WithFallback(searcher)).Resolve(context.Background(), "Paris", 0)
+
+		require.NoError(t, err)
+		assert.Len(t, actual, 3)
+		assert.Empty(t, searcher.req.Name)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		searcher := &searcherMock{
+			items: []webservice.GeoName{{ID: 1, Name: "Atlanta"}, {ID: 2, Name: "Atlantic City"}, {ID: 3, Name: "Atlantis"}},
+		}
+
+		actual, err := newTestResolver(t, WithFallback(searcher)).Resolve(context.Background(), "Atlantis TX, USA", 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, "atlantis", searcher.req.Name)
+		assert.Equal(t, []value.CountryCode{value.CountryCodeUnitedStates}, searcher.req.Country)
+		assert.True(t, searcher.req.NameRequired)
+
+		require.Len(t, actual, 2)
+		assert.Equal(t, uint64(1), actual[0].ID)
+		assert.InDelta(t, fallbackConfidence/(1+1.0/2+1.0/3), actual[0].Confidence, 1e-9)
+		assert.True(t, actual[0].Fallback)
+		assert.Equal(t, uint64(2), actual[1].ID)
+		assert.InDelta(t, fallbackConfidence/2/(1+1.0/2+1.0/3), actual[1].Confidence, 1e-9)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		searcher := &searcherMock{err: assert.AnError}
+
+		actual, err := newTestResolver(t, WithFallback(searcher)).Resolve(context.Background(), "Atlantis", 0)
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.EqualError(t, err, "search fallback => "+assert.AnError.Error())
+		assert.Nil(t, actual)
+	})
+}
@@ -0,0 +1,213 @@
+// Package resolve resolves free-text location strings such as `Springfield, IL` or `São Paulo, Brasil`
+// to ranked GeoNames candidates using the dump files, optionally falling back to a search.
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/internal/text"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+var ErrMissingPlaces = errors.New("places iterator is required")
+
+// Searcher finds places by name, implemented by webservice.Client and search.Index.
+type Searcher interface {
+	Search(ctx context.Context, req webservice.SearchRequest) ([]webservice.GeoName, error)
+}
+
+// Source provides the places to resolve to and the names that recognize the admin division and country parts.
+type Source struct {
+	// Places records to resolve to, usually cities500 or cities1000, required
+	Places download.Iterator[download.GeoName]
+	// AlternateNames names and abbreviations (language `abbr`) of places, admin divisions and countries, optional
+	AlternateNames download.Iterator[download.AlternateName]
+	// AdminDivisionFirst admin1CodesASCII.txt entries, used to match admin parts, optional
+	AdminDivisionFirst download.Iterator[download.AdminDivision]
+	// Countries countryInfo.txt entries, used to match country parts, optional
+	Countries download.Iterator[download.Country]
+}
+
+// Resolver resolves location strings. Its lookup maps are filled by Build and only read by Resolve, which may run
+// on several goroutines at once.
+type Resolver struct {
+	places    []download.GeoName
+	gazetteer *gazetteer.Gazetteer
+	// names maps normalized place names to the places having them
+	names map[string][]name
+	// countries maps normalized country names and codes to country codes
+	countries map[string][]value.CountryCode
+	// admins maps normalized admin division names, codes and abbreviations to admin division codes, e.g. `US.IL`
+	admins   map[string][]string
+	fallback Searcher
+}
+
+type name struct {
+	place     int
+	alternate bool
+}
+
+type Option func(*Resolver)
+
+// WithFallback sets the searcher used when no place matches locally.
+func WithFallback(searcher Searcher) Option {
+	return func(r *Resolver) {
+		r.fallback = searcher
+	}
+}
+
+// Build reads all source iterators and builds the resolver.
+func Build(src Source, opts ...Option) (*Resolver, error) {
+	var err error
+
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	res := &Resolver{
+		places:    make([]download.GeoName, 0),
+		gazetteer: nil,
+		names:     make(map[string][]name),
+		countries: make(map[string][]value.CountryCode),
+		admins:    make(map[string][]string),
+		fallback:  nil,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	placeIDs := make(map[uint64]int)
+
+	for item, err := range src.Places {
+		if err != nil {
+			return nil, fmt.Errorf("read places => %w", err)
+		}
+
+		placeIDs[item.ID] = len(res.places)
+		res.places = append(res.places, item)
+
+		res.addName(item.Name, len(res.places)-1, false)
+		res.addName(item.NameASCII, len(res.places)-1, false)
+
+		for _, alternate := range item.AlternateNames {
+			res.addName(alternate, len(res.places)-1, true)
+		}
+	}
+
+	if res.gazetteer, err = gazetteer.Build(src.AdminDivisionFirst, nil, src.Countries); err != nil {
+		return nil, err
+	}
+
+	countryIDs, adminIDs := res.addQualifiers()
+
+	if src.AlternateNames == nil {
+		return res, nil
+	}
+
+	for item, err := range src.AlternateNames {
+		if err != nil {
+			return nil, fmt.Errorf("read alternate names => %w", err)
+		}
+
+		if !gazetteer.IsName(item) {
+			continue
+		}
+
+		if id, ok := placeIDs[item.GeoNameID]; ok {
+			res.addName(item.Value, id, true)
+		}
+
+		key := text.Normalize(item.Value)
+
+		if code, ok := countryIDs[item.GeoNameID]; ok && key != "" {
+			res.countries[key] = appendOnce(res.countries[key], code)
+		}
+
+		if code, ok := adminIDs[item.GeoNameID]; ok && key != "" {
+			res.admins[key] = appendOnce(res.admins[key], code)
+		}
+	}
+
+	return res, nil
+}
+
+// Len returns the number of indexed places.
+func (r *Resolver) Len() int {
+	return len(r.places)
+}
+
+func (r *Resolver) addName(given string, place int, alternate bool) {
+	key := text.Normalize(given)
+	if key == "" {
+		return
+	}
+
+	for i, n := range r.names[key] {
+		if n.place == place {
+			r.names[key][i].alternate = n.alternate && alternate
+
+			return
+		}
+	}
+
+	r.names[key] = append(r.names[key], name{place: place, alternate: alternate})
+}
+
+// addQualifiers indexes the names and codes of countries and admin divisions, it returns their codes by geonameId
+// to add their alternate names.
+func (r *Resolver) addQualifiers() (map[uint64]value.CountryCode, map[uint64]string) {
+	countryIDs := make(map[uint64]value.CountryCode)
+	adminIDs := make(map[uint64]string)
+
+	for country := range r.gazetteer.Countries() {
+		countryIDs[country.ID] = country.Code
+
+		for _, key := range []string{country.Name, string(country.Code), country.IsoAlpha3} {
+			if key = text.Normalize(key); key != "" {
+				r.countries[key] = appendOnce(r.countries[key], country.Code)
+			}
+		}
+	}
+
+	for admin := range r.gazetteer.AdminDivisionsFirst() {
+		adminIDs[admin.ID] = admin.Code
+
+		keys := []string{admin.Name, admin.NameASCII}
+
+		// letter codes are commonly used abbreviations, e.g. `US.IL`, numeric ones are not
+		if _, code, ok := strings.Cut(admin.Code, "."); ok && isLetters(code) {
+			keys = append(keys, code)
+		}
+
+		for _, key := range keys {
+			if key = text.Normalize(key); key != "" {
+				r.admins[key] = appendOnce(r.admins[key], admin.Code)
+			}
+		}
+	}
+
+	return countryIDs, adminIDs
+}
+
+func isLetters(code string) bool {
+	const minLength = 2
+
+	return len(code) >= minLength && strings.IndexFunc(code, func(r rune) bool { return !unicode.IsLetter(r) }) < 0
+}
+
+func appendOnce[T comparable](values []T, v T) []T {
+	if slices.Contains(values, v) {
+		return values
+	}
+
+	return append(values, v)
+}
@@ -0,0 +1,192 @@
+// Package reverse looks up the populated places closest to a position in a spatial index of the cities dump files.
+// Geocoder.FindNearbyPlaceName takes the request and returns the results of webservice.Client.FindNearbyPlaceName.
+package reverse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/spatial"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+const featureClassPopulatedPlace = "P"
+
+var (
+	ErrNotFound      = errors.New("no place found")
+	ErrMissingPlaces = errors.New("places iterator is required")
+)
+
+// Source holds the dump files a Geocoder indexes, only Places is required.
+type Source struct {
+	// Places populated places, usually cities500 or cities1000, required
+	Places download.Iterator[download.GeoName]
+	// AdminDivisionFirst admin1CodesASCII.txt entries, optional
+	AdminDivisionFirst download.Iterator[download.AdminDivision]
+	// AdminDivisionSecond admin2Codes.txt entries, optional
+	AdminDivisionSecond download.Iterator[download.AdminDivision]
+	// Countries countryInfo.txt entries, optional
+	Countries download.Iterator[download.Country]
+}
+
+// Geocoder resolves positions to the nearest populated place. Lookups only read the index, so one Geocoder can be
+// shared by many goroutines.
+type Geocoder struct {
+	index       *spatial.Index
+	gazetteer   *gazetteer.Gazetteer
+	maxDistance float64
+}
+
+type Option func(*Geocoder)
+
+// WithMaxDistance sets the distance in km beyond which no place is returned, 0 means unlimited.
+func WithMaxDistance(maxDistance float64) Option {
+	return func(g *Geocoder) {
+		g.maxDistance = maxDistance
+	}
+}
+
+// Build reads all source iterators and builds the geocoder, only populated places (feature class P) are indexed.
+func Build(src Source, opts ...Option) (*Geocoder, error) {
+	var err error
+
+	res := &Geocoder{
+		index:       nil,
+		gazetteer:   nil,
+		maxDistance: 0,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	places := download.Filter(src.Places, func(item download.GeoName) bool {
+		return item.FeatureClass == featureClassPopulatedPlace
+	})
+
+	if res.index, err = spatial.Build(places); err != nil {
+		return nil, fmt.Errorf("build places index => %w", err)
+	}
+
+	if res.gazetteer, err = gazetteer.Build(src.AdminDivisionFirst, src.AdminDivisionSecond, src.Countries); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Download fetches the given cities file, admin divisions and country info with the client and builds the geocoder.
+func Download(ctx context.Context, client *download.Client, cities value.Cities, opts ...Option) (*Geocoder, error) {
+	var (
+		src Source
+		err error
+	)
+
+	if src.Places, err = client.Cities(ctx, cities); err != nil {
+		return nil, fmt.Errorf("download %s => %w", cities, err)
+	}
+
+	if src.AdminDivisionFirst, err = client.AdminDivisionFirst(ctx); err != nil {
+		return nil, fmt.Errorf("download first admin divisions => %w", err)
+	}
+
+	if src.AdminDivisionSecond, err = client.AdminDivisionSecond(ctx); err != nil {
+		return nil, fmt.Errorf("download second admin divisions => %w", err)
+	}
+
+	if src.Countries, err = client.CountryInfo(ctx); err != nil {
+		return nil, fmt.Errorf("download country info => %w", err)
+	}
+
+	return Build(src, opts...)
+}
+
+// Len returns the number of indexed places.
+func (g *Geocoder) Len() int {
+	return g.index.Len()
+}
+
+// Lookup returns the nearest populated place, ErrNotFound is returned when there is none within the max distance.
+func (g *Geocoder) Lookup(position value.Position) (webservice.GeoNameNearby, error) {
+	res := g.nearest(position, 1, 0, spatial.Filter{
+		FeatureClass:  nil,
+		FeatureCode:   nil,
+		Country:       nil,
+		MinPopulation: 0,
+	})
+	if len(res) == 0 {
+		return webservice.GeoNameNearby{}, ErrNotFound
+	}
+
+	return res[0], nil
+}
+
+// FindNearbyPlaceName mirrors webservice.Client.FindNearbyPlaceName. Radius, MaxRows, LocalCountry and Cities
+// are honoured, Language is ignored and the names are always the ones from the dump files.
+// Without MaxRows only the closest place is returned.
+func (g *Geocoder) FindNearbyPlaceName(
+	ctx context.Context,
+	req webservice.FindNearbyPlaceNameRequest,
+) ([]webservice.GeoNameNearby, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filter := spatial.Filter{
+		FeatureClass:  nil,
+		FeatureCode:   nil,
+		Country:       nil,
+		MinPopulation: gazetteer.MinPopulation(req.Cities),
+	}
+
+	if req.LocalCountry {
+		closest := g.nearest(req.Position, 1, value.Deref(req.Radius), filter)
+		if len(closest) == 0 {
+			return nil, nil
+		}
+
+		filter.Country = []value.CountryCode{closest[0].Country.Code}
+	}
+
+	return g.nearest(req.Position, int(max(req.MaxRows, 1)), value.Deref(req.Radius), filter), nil
+}
+
+// nearest returns up to k places within the radius (or the max distance when smaller), 0 means unlimited.
+func (g *Geocoder) nearest(
+	position value.Position,
+	k int,
+	radius float64,
+	filter spatial.Filter,
+) []webservice.GeoNameNearby {
+	if g.maxDistance > 0 && (radius <= 0 || g.maxDistance < radius) {
+		radius = g.maxDistance
+	}
+
+	found := g.index.Nearest(position, k, filter)
+	res := make([]webservice.GeoNameNearby, 0, len(found))
+
+	for _, item := range found {
+		if radius > 0 && item.Distance > radius {
+			break
+		}
+
+		res = append(res, g.result(item))
+	}
+
+	return res
+}
+
+func (g *Geocoder) result(item spatial.Result) webservice.GeoNameNearby {
+	return webservice.GeoNameNearby{
+		GeoName:  g.gazetteer.GeoName(item.GeoName),
+		Distance: item.Distance,
+	}
+}
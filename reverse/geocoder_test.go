@@ -0,0 +1,284 @@
+package reverse
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func newTestGeocoder(t *testing.T, opts ...Option) *Geocoder {
+	t.Helper()
+
+	res, err := Build(Source{
+		Places:              download.FromSlice(fixture.Places()),
+		AdminDivisionFirst:  download.FromSlice(fixture.AdminDivisionsFirst()),
+		AdminDivisionSecond: download.FromSlice(fixture.AdminDivisionsSecond()),
+		Countries:           download.FromSlice(fixture.Countries()),
+	}, opts...)
+
+	require.NoError(t, err)
+
+	return res
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("only populated places", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 28, newTestGeocoder(t).Len())
+	})
+
+	t.Run("optional sources", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := Build(Source{Places: download.FromSlice(fixture.Places())})
+
+		require.NoError(t, err)
+
+		actual, err := res.Lookup(value.Position{Latitude: 51.5, Longitude: -0.12})
+
+		require.NoError(t, err)
+		assert.Equal(t, "London", actual.Name)
+		assert.Empty(t, actual.Country.Name)
+		assert.Empty(t, actual.AdminSubdivision.First.Name)
+	})
+
+	tests := []struct {
+		name string
+		src  Source
+		err  string
+	}{
+		{
+			name: "missing places",
+			src:  Source{},
+			err:  ErrMissingPlaces.Error(),
+		},
+		{
+			name: "places error",
+			src:  Source{Places: fixture.Failing(fixture.Places(), assert.AnError)},
+			err:  "build places index => read item => " + assert.AnError.Error(),
+		},
+		{
+			name: "first admin divisions error",
+			src: Source{
+				Places:             download.FromSlice(fixture.Places()),
+				AdminDivisionFirst: fixture.Failing(fixture.AdminDivisionsFirst(), assert.AnError),
+			},
+			err: "read first admin divisions => " + assert.AnError.Error(),
+		},
+		{
+			name: "second admin divisions error",
+			src: Source{
+				Places:              download.FromSlice(fixture.Places()),
+				AdminDivisionSecond: fixture.Failing(fixture.AdminDivisionsSecond(), assert.AnError),
+			},
+			err: "read second admin divisions => " + assert.AnError.Error(),
+		},
+		{
+			name: "countries error",
+			src: Source{
+				Places:    download.FromSlice(fixture.Places()),
+				Countries: fixture.Failing(fixture.Countries(), assert.AnError),
+			},
+			err: "read countries => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Build(tt.src)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func Test_Download(t *testing.T) {
+	t.Parallel()
+
+	client := download.NewClient(download.WithHTTPClient(testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+		m.On("Do", mock.AnythingOfType("*http.Request")).Once().Return(
+			&http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody},
+			nil,
+		)
+	})))
+
+	actual, err := Download(context.Background(), client, value.Cities1000)
+
+	require.ErrorIs(t, err, download.ErrUnexpectedStatusCode)
+	require.ErrorContains(t, err, "download cities1000 => ")
+	assert.Nil(t, actual)
+}
+
+func Test_Geocoder_Lookup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nearest place", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := newTestGeocoder(t).Lookup(value.Position{Latitude: 51.45, Longitude: -0.11})
+
+		require.NoError(t, err)
+		assert.InDelta(t, 6.599, actual.Distance, 1e-3)
+
+		actual.Distance = 0
+
+		assert.Equal(t, webservice.GeoNameNearby{
+			GeoName: webservice.GeoName{
+				ID:      2643743,
+				Country: value.Country{ID: 2635167, Code: value.CountryCodeUnitedKingdom, Name: "United Kingdom"},
+				AdminSubdivision: value.AdminDivisions{
+					First:  value.AdminDivision{ID: 6269131, Code: "ENG", Name: "England"},
+					Second: value.AdminDivision{ID: 2648110, Code: "GLA", Name: "Greater London"},
+				},
+				Feature:     value.Feature{Class: "P", Code: "PPLC"},
+				Position:    value.Position{Latitude: 51.50853, Longitude: -0.12574},
+				Name:        "London",
+				ToponymName: "London",
+				Population:  8961989,
+			},
+		}, actual)
+	})
+
+	t.Run("max distance", func(t *testing.T) {
+		t.Parallel()
+
+		geocoder := newTestGeocoder(t, WithMaxDistance(50))
+
+		actual, err := geocoder.Lookup(value.Position{Latitude: 51, Longitude: 1.5})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Calais", actual.Name)
+
+		_, err = geocoder.Lookup(value.Position{Latitude: 40, Longitude: 0})
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		geocoder := newTestGeocoder(t)
+
+		var wg sync.WaitGroup
+
+		for range 8 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				actual, err := geocoder.Lookup(value.Position{Latitude: 48.8, Longitude: 2.3})
+
+				assert.NoError(t, err)
+				assert.Equal(t, "Paris", actual.Name)
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func Test_Geocoder_FindNearbyPlaceName(t *testing.T) {
+	t.Parallel()
+
+	dover := value.Position{Latitude: 51.1, Longitude: 1.3}
+
+	tests := []struct {
+		name string
+		opts []Option
+		req  webservice.FindNearbyPlaceNameRequest
+		exp  []string
+	}{
+		{
+			name: "closest by default",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover},
+			exp:  []string{"Deal"},
+		},
+		{
+			name: "max rows",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover, MaxRows: 5},
+			exp:  []string{"Deal", "Calais", "Croydon", "London", "Paris"},
+		},
+		{
+			name: "radius",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover, MaxRows: 10, Radius: value.Ptr(150.0)},
+			exp:  []string{"Deal", "Calais", "Croydon", "London"},
+		},
+		{
+			name: "max distance limits radius",
+			opts: []Option{WithMaxDistance(100)},
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover, MaxRows: 10, Radius: value.Ptr(150.0)},
+			exp:  []string{"Deal", "Calais"},
+		},
+		{
+			name: "local country",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: value.Position{Latitude: 50.9, Longitude: 1.6}, MaxRows: 10, LocalCountry: true},
+			exp:  []string{"Calais", "Paris", "Versailles"},
+		},
+		{
+			name: "cities",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover, MaxRows: 4, Cities: value.Cities15000},
+			exp:  []string{"Calais", "Croydon", "London", "Paris"},
+		},
+		{
+			name: "cities above population",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: dover, MaxRows: 2, Cities: value.Cities15000, LocalCountry: true, Radius: value.Ptr(50.0)},
+			exp:  []string{"Calais"},
+		},
+		{
+			name: "nothing in radius",
+			req:  webservice.FindNearbyPlaceNameRequest{Position: value.Position{}, LocalCountry: true, Radius: value.Ptr(10.0)},
+			exp:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := newTestGeocoder(t, tt.opts...).FindNearbyPlaceName(context.Background(), tt.req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, names(actual))
+		})
+	}
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		actual, err := newTestGeocoder(t).FindNearbyPlaceName(ctx, webservice.FindNearbyPlaceNameRequest{})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, actual)
+	})
+}
+
+func names(items []webservice.GeoNameNearby) []string {
+	res := make([]string, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.Name)
+	}
+
+	return res
+}
@@ -0,0 +1,186 @@
+// Package search runs webservice.SearchRequest queries against an inverted index of GeoNames records, for when
+// the search webservice is too slow, rate limited or out of reach.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/internal/text"
+	"github.com/platx/geonames/value"
+)
+
+var ErrMissingPlaces = errors.New("places iterator is required")
+
+// Source lists the records to search and the optional files behind the language and location filters.
+type Source struct {
+	// Places records to search, e.g. allCountries or cities500, required
+	Places download.Iterator[download.GeoName]
+	// AlternateNames names in other languages, used by SearchLanguage and Language, optional
+	AlternateNames download.Iterator[download.AlternateName]
+	// AdminDivisionFirst admin1CodesASCII.txt entries, optional
+	AdminDivisionFirst download.Iterator[download.AdminDivision]
+	// AdminDivisionSecond admin2Codes.txt entries, optional
+	AdminDivisionSecond download.Iterator[download.AdminDivision]
+	// Countries countryInfo.txt entries, used by ContinentCode and Query, optional
+	Countries download.Iterator[download.Country]
+}
+
+// Index is an inverted index over place names and attributes. Searching does not modify it, so one Index can serve
+// concurrent requests.
+type Index struct {
+	docs      []document
+	gazetteer *gazetteer.Gazetteer
+	// names maps name words to the ids of the documents having them
+	names map[string][]int
+	// attributes maps words of all other attributes (country, continent, admin names and codes) to document ids
+	attributes map[string][]int
+	// terms all name words in sorted order, used for prefix and fuzzy lookups
+	terms []string
+}
+
+type document struct {
+	item       download.GeoName
+	names      []name
+	attributes []string
+	continent  value.ContinentCode
+}
+
+type name struct {
+	value      string
+	normalized string
+	tokens     []string
+	language   string
+	preferred  bool
+}
+
+// Build reads all source iterators and builds the index.
+func Build(src Source) (*Index, error) {
+	var err error
+
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	res := &Index{
+		docs:       make([]document, 0),
+		gazetteer:  nil,
+		names:      make(map[string][]int),
+		attributes: make(map[string][]int),
+		terms:      nil,
+	}
+
+	if res.gazetteer, err = gazetteer.Build(src.AdminDivisionFirst, src.AdminDivisionSecond, src.Countries); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint64]int)
+
+	for item, err := range src.Places {
+		if err != nil {
+			return nil, fmt.Errorf("read places => %w", err)
+		}
+
+		byID[item.ID] = len(res.docs)
+		res.docs = append(res.docs, res.document(item))
+	}
+
+	if src.AlternateNames != nil {
+		for item, err := range src.AlternateNames {
+			if err != nil {
+				return nil, fmt.Errorf("read alternate names => %w", err)
+			}
+
+			doc, ok := byID[item.GeoNameID]
+			if !ok || !gazetteer.IsName(item) {
+				continue
+			}
+
+			res.docs[doc].names = append(res.docs[doc].names, newName(item.Value, item.Language, item.Preferred))
+		}
+	}
+
+	for i, doc := range res.docs {
+		for _, n := range doc.names {
+			for _, token := range n.tokens {
+				res.names[token] = appendID(res.names[token], i)
+			}
+		}
+
+		for _, token := range doc.attributes {
+			res.attributes[token] = appendID(res.attributes[token], i)
+		}
+	}
+
+	res.terms = make([]string, 0, len(res.names))
+
+	for term := range res.names {
+		res.terms = append(res.terms, term)
+	}
+
+	slices.Sort(res.terms)
+
+	return res, nil
+}
+
+// Len returns the number of indexed records.
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+func (idx *Index) document(item download.GeoName) document {
+	res := document{
+		item:       item,
+		names:      []name{newName(item.Name, "", true)},
+		attributes: nil,
+		continent:  "",
+	}
+
+	if item.NameASCII != "" && item.NameASCII != item.Name {
+		res.names = append(res.names, newName(item.NameASCII, "", false))
+	}
+
+	for _, alternate := range item.AlternateNames {
+		res.names = append(res.names, newName(alternate, "", false))
+	}
+
+	attributes := []string{string(item.CountryCode), item.AdminCode.First, item.AdminCode.Second}
+
+	if country, ok := idx.gazetteer.Country(item.CountryCode); ok {
+		res.continent = country.ContinentCode
+		attributes = append(attributes, country.Name, string(country.ContinentCode))
+	}
+
+	attributes = append(
+		attributes,
+		idx.gazetteer.AdminDivisionFirst(item).Name,
+		idx.gazetteer.AdminDivisionSecond(item).Name,
+	)
+
+	res.attributes = text.Tokenize(strings.Join(attributes, " "))
+
+	return res
+}
+
+func newName(given, language string, preferred bool) name {
+	return name{
+		value:      given,
+		normalized: text.Normalize(given),
+		tokens:     text.Tokenize(given),
+		language:   language,
+		preferred:  preferred,
+	}
+}
+
+// appendID appends the document id once, ids are added in ascending order.
+func appendID(ids []int, id int) []int {
+	if len(ids) > 0 && ids[len(ids)-1] == id {
+		return ids
+	}
+
+	return append(ids, id)
+}
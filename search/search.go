@@ -0,0 +1,478 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/internal/text"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+const (
+	defaultMaxRows = 100
+	maxMaxRows     = 1000
+
+	scoreName          = 2
+	scoreAttribute     = 1
+	scoreExactName     = 3
+	scorePopulationLog = 0.1
+	fuzzyEpsilon       = 1e-6
+)
+
+// Search mirrors webservice.Client.Search. Tag is ignored since tags are not part of the dump files,
+// Language selects the preferred alternate name in the given language for the Name field.
+func (idx *Index) Search(ctx context.Context, req webservice.SearchRequest) ([]webservice.GeoName, error) {
+	res, _, err := idx.SearchTotal(ctx, req)
+
+	return res, err
+}
+
+// SearchTotal is Search also returning the number of all matching records, the totalResultsCount of the webservice.
+func (idx *Index) SearchTotal(ctx context.Context, req webservice.SearchRequest) ([]webservice.GeoName, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	q := newQuery(req)
+	found := make([]match, 0)
+
+	for _, id := range idx.candidates(q) {
+		doc := &idx.docs[id]
+
+		if !q.filter(doc) {
+			continue
+		}
+
+		score, ok := q.score(doc)
+		if !ok {
+			continue
+		}
+
+		found = append(found, match{doc: doc, score: score})
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	slices.SortStableFunc(found, q.compare)
+
+	start := min(int(req.StartRow), len(found))
+	end := min(start+q.maxRows, len(found))
+	res := make([]webservice.GeoName, 0, end-start)
+
+	for _, m := range found[start:end] {
+		item := idx.gazetteer.GeoName(m.doc.item)
+
+		if localized, ok := m.doc.localizedName(req.Language); ok {
+			item.Name = localized
+		}
+
+		res = append(res, item)
+	}
+
+	return res, len(found), nil
+}
+
+type match struct {
+	doc   *document
+	score float64
+}
+
+// query is a SearchRequest with normalized search terms.
+type query struct {
+	req           webservice.SearchRequest
+	terms         []string
+	names         []string
+	nameEquals    string
+	startsWith    string
+	fuzzyLimit    func(term string) int
+	maxRows       int
+	minPopulation int64
+}
+
+func newQuery(req webservice.SearchRequest) *query {
+	res := &query{
+		req:           req,
+		terms:         text.Tokenize(req.Query),
+		names:         text.Tokenize(req.Name),
+		nameEquals:    text.Normalize(strings.TrimSpace(req.NameEquals)),
+		startsWith:    text.Normalize(strings.TrimSpace(req.NameStartsWith)),
+		fuzzyLimit:    fuzzyLimit(req.Fuzzy),
+		maxRows:       defaultMaxRows,
+		minPopulation: 0,
+	}
+
+	if req.MaxRows > 0 {
+		res.maxRows = int(min(req.MaxRows, maxMaxRows))
+	}
+
+	if req.Cities != "" {
+		res.minPopulation = max(gazetteer.MinPopulation(req.Cities), 1)
+	}
+
+	return res
+}
+
+// fuzzyLimit returns the allowed number of edits for a term, the fuzziness is the minimal
+// similarity 1 - edits/length, unset and 1 mean exact matching.
+func fuzzyLimit(fuzzy *float32) func(term string) int {
+	return func(term string) int {
+		if fuzzy == nil || *fuzzy >= 1 {
+			return 0
+		}
+
+		// the epsilon compensates float32 rounding, e.g. 0.8 is stored as 0.800000011920929
+		return int(math.Floor((1-float64(max(*fuzzy, 0)))*float64(len([]rune(term))) + fuzzyEpsilon))
+	}
+}
+
+// candidates returns ids of the documents which may match the name parameters, all documents without them.
+func (idx *Index) candidates(q *query) []int {
+	var sets [][]int
+
+	if len(q.terms) > 0 {
+		set := make([]int, 0)
+
+		for _, term := range q.terms {
+			set = union(set, idx.names[term])
+			set = union(set, idx.attributes[term])
+		}
+
+		sets = append(sets, set)
+	}
+
+	if len(q.names) > 0 {
+		set := make([]int, 0)
+
+		for _, term := range q.names {
+			for _, match := range idx.fuzzyTerms(term, q.fuzzyLimit(term)) {
+				set = union(set, idx.names[match])
+			}
+		}
+
+		sets = append(sets, set)
+	}
+
+	if tokens := text.Tokenize(q.nameEquals); len(tokens) > 0 {
+		sets = append(sets, idx.names[tokens[0]])
+	}
+
+	if tokens := text.Tokenize(q.startsWith); len(tokens) > 0 {
+		set := make([]int, 0)
+
+		for _, term := range idx.prefixTerms(tokens[0]) {
+			set = union(set, idx.names[term])
+		}
+
+		sets = append(sets, set)
+	}
+
+	if len(sets) == 0 {
+		res := make([]int, len(idx.docs))
+		for i := range res {
+			res[i] = i
+		}
+
+		return res
+	}
+
+	res := sets[0]
+	for _, set := range sets[1:] {
+		res = intersect(res, set)
+	}
+
+	return res
+}
+
+// prefixTerms returns the indexed name words starting with the prefix.
+func (idx *Index) prefixTerms(prefix string) []string {
+	start, _ := slices.BinarySearch(idx.terms, prefix)
+	end := start
+
+	for end < len(idx.terms) && strings.HasPrefix(idx.terms[end], prefix) {
+		end++
+	}
+
+	return idx.terms[start:end]
+}
+
+// fuzzyTerms returns the indexed name words within the given edit distance of the term. Words whose length differs
+// by more than the limit are skipped before computing the distance.
+func (idx *Index) fuzzyTerms(term string, limit int) []string {
+	if limit == 0 {
+		return []string{term}
+	}
+
+	res := make([]string, 0)
+	runes := []rune(term)
+
+	for _, candidate := range idx.terms {
+		if diff := utf8.RuneCountInString(candidate) - len(runes); diff > limit || -diff > limit {
+			continue
+		}
+
+		if text.EditDistance(runes, []rune(candidate), limit) <= limit {
+			res = append(res, candidate)
+		}
+	}
+
+	return res
+}
+
+func (q *query) filter(doc *document) bool {
+	item := doc.item
+	req := q.req
+
+	switch {
+	case len(req.Country) > 0 && !slices.Contains(req.Country, item.CountryCode),
+		req.ContinentCode != "" && doc.continent != req.ContinentCode,
+		len(req.FeatureClass) > 0 && !slices.Contains(req.FeatureClass, item.FeatureClass),
+		len(req.FeatureCode) > 0 && !slices.Contains(req.FeatureCode, item.FeatureCode),
+		!matchAdminCode(req.AdminCode, item.AdminCode),
+		q.minPopulation > 0 && (item.FeatureClass != "P" || item.Population < q.minPopulation),
+		req.BoundingBox != (value.BoundingBox{}) && !req.BoundingBox.Contains(item.Position):
+		return false
+	default:
+		return true
+	}
+}
+
+// score returns the relevance of the document, false when it does not match the search terms.
+func (q *query) score(doc *document) (float64, bool) {
+	names := doc.searchableNames(q.req.SearchLanguage)
+	res := 0.0
+
+	if q.nameEquals != "" {
+		if !slices.ContainsFunc(names, func(n name) bool { return n.normalized == q.nameEquals }) {
+			return 0, false
+		}
+
+		res += scoreExactName
+	}
+
+	if q.startsWith != "" {
+		if !slices.ContainsFunc(names, func(n name) bool { return strings.HasPrefix(n.normalized, q.startsWith) }) {
+			return 0, false
+		}
+
+		res += scoreName
+	}
+
+	if len(q.names) > 0 {
+		score, ok := q.match(q.names, func(term string) float64 { return q.nameScore(names, term) })
+		if !ok {
+			return 0, false
+		}
+
+		res += score
+	}
+
+	if len(q.terms) > 0 {
+		nameMatched := false
+
+		score, ok := q.match(q.terms, func(term string) float64 {
+			if nameScore := q.nameScore(names, term); nameScore > 0 {
+				nameMatched = true
+
+				return nameScore
+			}
+
+			if slices.Contains(doc.attributes, term) {
+				return scoreAttribute
+			}
+
+			return 0
+		})
+		if !ok || (q.req.NameRequired && !nameMatched) {
+			return 0, false
+		}
+
+		res += score
+	}
+
+	if phrase := strings.Join(q.names, " "); phrase != "" &&
+		slices.ContainsFunc(names, func(n name) bool { return n.normalized == phrase }) {
+		res += scoreExactName
+	}
+
+	return res + scorePopulationLog*math.Log1p(float64(max(doc.item.Population, 0))), true
+}
+
+// match combines the term scores with the requested operator.
+func (q *query) match(terms []string, score func(term string) float64) (float64, bool) {
+	res, matched := 0.0, 0
+
+	for _, term := range terms {
+		if s := score(term); s > 0 {
+			res += s
+			matched++
+		}
+	}
+
+	if q.req.Operator == value.OperatorOr {
+		return res, matched > 0
+	}
+
+	return res, matched == len(terms)
+}
+
+// nameScore returns the score of the best matching name word, fuzzy matches score by their similarity.
+func (q *query) nameScore(names []name, term string) float64 {
+	limit := q.fuzzyLimit(term)
+	runes := []rune(term)
+	best := 0.0
+
+	for _, n := range names {
+		for _, token := range n.tokens {
+			if token == term {
+				return scoreName
+			}
+
+			if limit == 0 {
+				continue
+			}
+
+			if distance := text.EditDistance(runes, []rune(token), limit); distance <= limit {
+				best = math.Max(best, scoreName*(1-float64(distance)/float64(len(runes))))
+			}
+		}
+	}
+
+	return best
+}
+
+func (q *query) compare(a, b match) int {
+	if bias := q.req.CountryBias; bias != "" {
+		res := cmp.Compare(boolInt(b.doc.item.CountryCode == bias), boolInt(a.doc.item.CountryCode == bias))
+		if res != 0 {
+			return res
+		}
+	}
+
+	switch q.req.OrderBy {
+	case value.OrderByPopulation:
+		if res := cmp.Compare(b.doc.item.Population, a.doc.item.Population); res != 0 {
+			return res
+		}
+	case value.OrderByElevation:
+		if res := cmp.Compare(b.doc.item.Elevation, a.doc.item.Elevation); res != 0 {
+			return res
+		}
+	case value.OrderByRelevance:
+	}
+
+	return cmp.Or(
+		cmp.Compare(b.score, a.score),
+		cmp.Compare(b.doc.item.Population, a.doc.item.Population),
+		cmp.Compare(a.doc.item.ID, b.doc.item.ID),
+	)
+}
+
+// searchableNames returns the names of the given language, all names when no language is given.
+func (d *document) searchableNames(language string) []name {
+	if language == "" {
+		return d.names
+	}
+
+	res := make([]name, 0)
+
+	for _, n := range d.names {
+		if n.language == language {
+			res = append(res, n)
+		}
+	}
+
+	return res
+}
+
+// localizedName returns the preferred alternate name of the given language.
+func (d *document) localizedName(language string) (string, bool) {
+	if language == "" {
+		return "", false
+	}
+
+	res, found := "", false
+
+	for _, n := range d.names {
+		if n.language != language {
+			continue
+		}
+
+		if n.preferred {
+			return n.value, true
+		}
+
+		if !found {
+			res, found = n.value, true
+		}
+	}
+
+	return res, found
+}
+
+func matchAdminCode(expected, actual value.AdminCode) bool {
+	return (expected.First == "" || expected.First == actual.First) &&
+		(expected.Second == "" || expected.Second == actual.Second) &&
+		(expected.Third == "" || expected.Third == actual.Third) &&
+		(expected.Fourth == "" || expected.Fourth == actual.Fourth) &&
+		(expected.Fifth == "" || expected.Fifth == actual.Fifth)
+}
+
+// union merges two ascending id lists.
+func union(a, b []int) []int {
+	res := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			res = append(res, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+
+	return res
+}
+
+// intersect returns the ids present in both ascending id lists.
+func intersect(a, b []int) []int {
+	res := make([]int, 0, min(len(a), len(b)))
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+
+	return res
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,253 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func Test_Index_Search(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex(t)
+
+	tests := []struct {
+		name string
+		req  webservice.SearchRequest
+		exp  []uint64
+	}{
+		{
+			name: "name",
+			req:  webservice.SearchRequest{Name: "london"},
+			exp:  []uint64{2643743, 6058560, 4839416},
+		},
+		{
+			name: "name with alternate name",
+			req:  webservice.SearchRequest{Name: "Londres"},
+			exp:  []uint64{2643743},
+		},
+		{
+			name: "name with diacritics folded",
+			req:  webservice.SearchRequest{Name: "zurich"},
+			exp:  []uint64{2657896},
+		},
+		{
+			name: "name and operator",
+			req:  webservice.SearchRequest{Name: "new london"},
+			exp:  []uint64{4839416},
+		},
+		{
+			name: "name or operator",
+			req:  webservice.SearchRequest{Name: "new london", Operator: value.OperatorOr},
+			exp:  []uint64{4839416, 2643743, 5128581, 6058560},
+		},
+		{
+			name: "name equals",
+			req:  webservice.SearchRequest{NameEquals: "London"},
+			exp:  []uint64{2643743, 6058560},
+		},
+		{
+			name: "name starts with",
+			req:  webservice.SearchRequest{NameStartsWith: "lond"},
+			exp:  []uint64{2643743, 6058560, 2643736},
+		},
+		{
+			name: "query over attributes",
+			req:  webservice.SearchRequest{Query: "london england"},
+			exp:  []uint64{2643743},
+		},
+		{
+			name: "query or operator",
+			req:  webservice.SearchRequest{Query: "london ontario", Operator: value.OperatorOr},
+			exp:  []uint64{6058560, 2643743, 4839416},
+		},
+		{
+			name: "query by country name",
+			req:  webservice.SearchRequest{Query: "paris france"},
+			exp:  []uint64{2988507, 2968815},
+		},
+		{
+			name: "query without name",
+			req:  webservice.SearchRequest{Query: "england"},
+			exp:  []uint64{2643743, 2652221, 2651500, 2636063},
+		},
+		{
+			name: "query name required",
+			req:  webservice.SearchRequest{Query: "england", NameRequired: true},
+			exp:  []uint64{},
+		},
+		{
+			name: "country",
+			req:  webservice.SearchRequest{Name: "london", Country: []value.CountryCode{value.CountryCodeCanada}},
+			exp:  []uint64{6058560},
+		},
+		{
+			name: "country bias",
+			req:  webservice.SearchRequest{Name: "london", CountryBias: value.CountryCodeUnitedStates},
+			exp:  []uint64{4839416, 2643743, 6058560},
+		},
+		{
+			name: "continent",
+			req:  webservice.SearchRequest{Name: "london", ContinentCode: value.ContinentCodeNorthAmerica},
+			exp:  []uint64{6058560, 4839416},
+		},
+		{
+			name: "admin code",
+			req:  webservice.SearchRequest{Query: "london", AdminCode: value.AdminCode{First: "ENG"}},
+			exp:  []uint64{2643743},
+		},
+		{
+			name: "feature class",
+			req:  webservice.SearchRequest{Query: "england", FeatureClass: []string{"H"}},
+			exp:  []uint64{2636063},
+		},
+		{
+			name: "feature code",
+			req:  webservice.SearchRequest{FeatureCode: []string{"PPLC"}, Country: []value.CountryCode{value.CountryCodeUnitedKingdom, value.CountryCodeFrance}},
+			exp:  []uint64{2643743, 2988507},
+		},
+		{
+			name: "cities",
+			req:  webservice.SearchRequest{Name: "london", Cities: value.Cities15000},
+			exp:  []uint64{2643743, 6058560},
+		},
+		{
+			name: "bounding box",
+			req: webservice.SearchRequest{
+				Name:        "london",
+				BoundingBox: value.BoundingBox{East: 30, West: -10, North: 60, South: 35},
+			},
+			exp: []uint64{2643743},
+		},
+		{
+			name: "fuzzy",
+			req:  webservice.SearchRequest{Name: "londn", Fuzzy: value.Ptr[float32](0.8)},
+			exp:  []uint64{2643743, 6058560, 4839416},
+		},
+		{
+			name: "fuzzy default is exact",
+			req:  webservice.SearchRequest{Name: "londn"},
+			exp:  []uint64{},
+		},
+		{
+			name: "search language",
+			req:  webservice.SearchRequest{Name: "париж", SearchLanguage: "ru"},
+			exp:  []uint64{2988507},
+		},
+		{
+			name: "search language without names",
+			req:  webservice.SearchRequest{Name: "париж", SearchLanguage: "de"},
+			exp:  []uint64{},
+		},
+		{
+			name: "order by population",
+			req:  webservice.SearchRequest{FeatureCode: []string{"PPLC", "MT"}, Country: []value.CountryCode{value.CountryCodeFrance}, OrderBy: value.OrderByPopulation},
+			exp:  []uint64{2988507, 2994154},
+		},
+		{
+			name: "order by elevation",
+			req:  webservice.SearchRequest{FeatureCode: []string{"PPLC", "MT"}, Country: []value.CountryCode{value.CountryCodeFrance}, OrderBy: value.OrderByElevation},
+			exp:  []uint64{2994154, 2988507},
+		},
+		{
+			name: "max rows and start row",
+			req:  webservice.SearchRequest{Name: "london", MaxRows: 1, StartRow: 1},
+			exp:  []uint64{6058560},
+		},
+		{
+			name: "start row beyond results",
+			req:  webservice.SearchRequest{Name: "london", StartRow: 10},
+			exp:  []uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := idx.Search(context.Background(), tt.req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, ids(actual))
+		})
+	}
+
+	t.Run("result", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := idx.Search(context.Background(), webservice.SearchRequest{
+			Name:         "paris",
+			Country:      []value.CountryCode{value.CountryCodeFrance},
+			FeatureClass: []string{"P"},
+			Language:     "ru",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []webservice.GeoName{
+			{
+				ID:      2988507,
+				Country: value.Country{ID: 3017382, Code: value.CountryCodeFrance, Name: "France"},
+				AdminSubdivision: value.AdminDivisions{
+					First:  value.AdminDivision{ID: 3012874, Code: "11", Name: "Île-de-France"},
+					Second: value.AdminDivision{Code: "75"},
+					Third:  value.AdminDivision{Code: "751"},
+					Fourth: value.AdminDivision{Code: "75056"},
+				},
+				Feature:     value.Feature{Class: "P", Code: "PPLC"},
+				Position:    value.Position{Latitude: 48.85341, Longitude: 2.3488},
+				Name:        "Париж",
+				ToponymName: "Paris",
+				Population:  2138551,
+			},
+		}, actual)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		actual, err := idx.Search(ctx, webservice.SearchRequest{Name: "london"})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, actual)
+	})
+}
+
+func Test_Index_SearchTotal(t *testing.T) {
+	t.Parallel()
+
+	actual, total, err := newTestIndex(t).SearchTotal(
+		context.Background(),
+		webservice.SearchRequest{Name: "london", MaxRows: 1, StartRow: 1},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, uint64(6058560), actual[0].ID)
+	assert.Equal(t, 3, total)
+}
+
+func Test_union(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []int{1, 2, 3, 5, 8}, union([]int{1, 3, 5}, []int{2, 3, 8}))
+	assert.Equal(t, []int{1}, union(nil, []int{1}))
+	assert.Equal(t, []int{3, 5}, intersect([]int{1, 3, 5}, []int{3, 4, 5}))
+}
+
+func ids(items []webservice.GeoName) []uint64 {
+	res := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.ID)
+	}
+
+	return res
+}
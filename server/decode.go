@@ -0,0 +1,147 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// decodeQuery decodes the query values into the request pointed to by v, the reverse of webservice.URLEncoder:
+// fields are matched by their url tags, `,dive` structs are decoded in place and missing values are left zero.
+func decodeQuery(values url.Values, v any) error {
+	return decodeStruct(values, reflect.ValueOf(v).Elem())
+}
+
+func decodeStruct(values url.Values, val reflect.Value) error {
+	for i := range val.NumField() {
+		field := val.Type().Field(i)
+
+		key := field.Tag.Get("url")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && key == ",dive" {
+			if err := decodeStruct(values, val.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		raw := values[key]
+		if len(raw) == 0 || raw[0] == "" {
+			continue
+		}
+
+		if err := decodeValue(val.Field(i), raw); err != nil {
+			return fmt.Errorf("%w %s => %w", ErrInvalidParameter, key, err)
+		}
+	}
+
+	return nil
+}
+
+// decodeValue decodes the raw values into the field using reflection type switch, only slices use more than
+// the first value.
+func decodeValue(field reflect.Value, raw []string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw[0])
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw[0], 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw[0], 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw[0], field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw[0])
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(v)
+	case reflect.Slice:
+		return decodeSlice(field, raw)
+	case reflect.Struct:
+		return decodeTime(field, raw[0])
+	case reflect.Pointer:
+		return decodePointer(field, raw)
+	case
+		reflect.Invalid,
+		reflect.Uintptr,
+		reflect.Complex64,
+		reflect.Complex128,
+		reflect.Array,
+		reflect.Chan,
+		reflect.Func,
+		reflect.Interface,
+		reflect.Map,
+		reflect.UnsafePointer:
+	}
+
+	return nil
+}
+
+// decodePointer decodes into a newly allocated value, so optional parameters are set even to their zero value.
+func decodePointer(field reflect.Value, raw []string) error {
+	res := reflect.New(field.Type().Elem())
+
+	if err := decodeValue(res.Elem(), raw); err != nil {
+		return err
+	}
+
+	field.Set(res)
+
+	return nil
+}
+
+// decodeSlice decodes repeated values into a slice of strings, e.g. `country=FR&country=DE`.
+func decodeSlice(field reflect.Value, raw []string) error {
+	if field.Type().Elem().Kind() != reflect.String {
+		return nil
+	}
+
+	res := reflect.MakeSlice(field.Type(), len(raw), len(raw))
+
+	for i, v := range raw {
+		res.Index(i).SetString(v)
+	}
+
+	field.Set(res)
+
+	return nil
+}
+
+// decodeTime decodes a date in the format written by webservice.URLEncoder, other structs are ignored.
+func decodeTime(field reflect.Value, raw string) error {
+	if field.Type() != reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	v, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return err
+	}
+
+	field.Set(reflect.ValueOf(v))
+
+	return nil
+}
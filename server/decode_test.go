@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func Test_decodeQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		exp := webservice.SearchRequest{
+			Name:         "paris",
+			MaxRows:      10,
+			Country:      []value.CountryCode{value.CountryCodeFrance, value.CountryCodeBelgium},
+			AdminCode:    value.AdminCode{First: "11"},
+			FeatureClass: []string{"P"},
+			NameRequired: true,
+			Fuzzy:        value.Ptr[float32](0.8),
+			BoundingBox:  value.BoundingBox{East: 3, West: 2, North: 49, South: 48},
+		}
+
+		values := url.Values{}
+		webservice.NewURLEncoder(values).Encode(exp)
+
+		var actual webservice.SearchRequest
+
+		require.NoError(t, decodeQuery(values, &actual))
+		assert.Equal(t, exp, actual)
+	})
+
+	t.Run("date", func(t *testing.T) {
+		t.Parallel()
+
+		var actual webservice.TimezoneRequest
+
+		require.NoError(t, decodeQuery(url.Values{"lat": {"48.8"}, "lng": {"2.3"}, "date": {"2024-06-21"}}, &actual))
+		assert.Equal(t, webservice.TimezoneRequest{
+			Position: value.Position{Latitude: 48.8, Longitude: 2.3},
+			Date:     time.Date(2024, time.June, 21, 0, 0, 0, 0, time.UTC),
+		}, actual)
+	})
+
+	t.Run("explicit zero", func(t *testing.T) {
+		t.Parallel()
+
+		var actual webservice.OceanRequest
+
+		require.NoError(t, decodeQuery(url.Values{"lat": {"0"}, "lng": {"0"}, "radius": {"0"}}, &actual))
+		assert.Equal(t, webservice.OceanRequest{Position: value.Position{}, Radius: value.Ptr(0.0)}, actual)
+	})
+
+	tests := []struct {
+		name  string
+		given url.Values
+		err   string
+	}{
+		{
+			name:  "invalid uint",
+			given: url.Values{"geonameId": {"-1"}},
+			err:   `invalid parameter geonameId => strconv.ParseUint: parsing "-1": invalid syntax`,
+		},
+		{
+			name:  "invalid bool",
+			given: url.Values{"localCountry": {"yes"}},
+			err:   `invalid parameter localCountry => strconv.ParseBool: parsing "yes": invalid syntax`,
+		},
+		{
+			name:  "invalid float",
+			given: url.Values{"lat": {"north"}},
+			err:   `invalid parameter lat => strconv.ParseFloat: parsing "north": invalid syntax`,
+		},
+		{
+			name:  "invalid pointer",
+			given: url.Values{"radius": {"far"}},
+			err:   `invalid parameter radius => strconv.ParseFloat: parsing "far": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var req struct {
+				ID           uint64         `url:"geonameId"`
+				LocalCountry bool           `url:"localCountry"`
+				Position     value.Position `url:",dive"`
+				Radius       *float64       `url:"radius"`
+			}
+
+			require.EqualError(t, decodeQuery(tt.given, &req), tt.err)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+// featureClassNames are the names of the feature classes as returned by the webservice.
+var featureClassNames = map[string]string{
+	"A": "country, state, region,...",
+	"H": "stream, lake, ...",
+	"L": "parks,area, ...",
+	"P": "city, village,...",
+	"R": "road, railroad ",
+	"S": "spot, building, farm",
+	"T": "mountain,hill,rock,... ",
+	"U": "undersea",
+	"V": "forest,heath,...",
+}
+
+// continentNames are the names of the continents as returned by countryInfoJSON.
+var continentNames = map[value.ContinentCode]string{
+	value.ContinentCodeAfrica:       "Africa",
+	value.ContinentCodeAsia:         "Asia",
+	value.ContinentCodeEurope:       "Europe",
+	value.ContinentCodeNorthAmerica: "North America",
+	value.ContinentCodeSouthAmerica: "South America",
+	value.ContinentCodeOceania:      "Oceania",
+	value.ContinentCodeAntarctica:   "Antarctica",
+}
+
+// The types below are the wire format decoded by the types in webservice/result.go.
+
+type geoNamesResponse[T any] struct {
+	Items []T `json:"geonames"`
+}
+
+type searchResponse struct {
+	TotalResultsCount int       `json:"totalResultsCount"`
+	Items             []geoName `json:"geonames"`
+}
+
+type errorResponse struct {
+	Status errorStatus `json:"status"`
+}
+
+type errorStatus struct {
+	Message string        `json:"message"`
+	Value   value.ErrCode `json:"value"`
+}
+
+type geoName struct {
+	GeoNameID        uint64            `json:"geonameId"`
+	Name             string            `json:"name"`
+	ToponymName      string            `json:"toponymName"`
+	FeatureClass     string            `json:"fcl"`
+	FeatureClassName string            `json:"fclName"`
+	FeatureCode      string            `json:"fcode"`
+	FeatureCodeName  string            `json:"fcodeName"`
+	Latitude         float64           `json:"lat,string"`
+	Longitude        float64           `json:"lng,string"`
+	Population       uint64            `json:"population"`
+	CountryID        uint64            `json:"countryId,string"`
+	CountryCode      value.CountryCode `json:"countryCode"`
+	CountryName      string            `json:"countryName"`
+	AdminCode1       string            `json:"adminCode1"`
+	AdminName1       string            `json:"adminName1"`
+	AdminCode2       string            `json:"adminCode2"`
+	AdminName2       string            `json:"adminName2"`
+	AdminCode3       string            `json:"adminCode3"`
+	AdminName3       string            `json:"adminName3"`
+	AdminCode4       string            `json:"adminCode4"`
+	AdminName4       string            `json:"adminName4"`
+	AdminCode5       string            `json:"adminCode5"`
+	AdminName5       string            `json:"adminName5"`
+}
+
+type geoNameNearby struct {
+	geoName
+
+	Distance float64 `json:"distance,string"`
+}
+
+type geoNameDetailed struct {
+	geoName
+
+	ContinentCode  value.ContinentCode `json:"continentCode"`
+	ASCIIName      string              `json:"asciiName"`
+	AlternateNames []alternateName     `json:"alternateNames"`
+	Timezone       timezoneInfo        `json:"timezone"`
+	Elevation      int64               `json:"elevation"`
+	SRTM3          int64               `json:"srtm3"`
+	Astergdem      int64               `json:"astergdem"`
+	BoundingBox    boundingBox         `json:"bbox"`
+}
+
+type alternateName struct {
+	Language string `json:"lang"`
+	Value    string `json:"name"`
+}
+
+type timezoneInfo struct {
+	Name      string  `json:"timeZoneId"`
+	GMTOffset float64 `json:"gmtOffset"`
+	DSTOffset float64 `json:"dstOffset"`
+}
+
+type boundingBox struct {
+	West  float64 `json:"west"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+	South float64 `json:"south"`
+}
+
+type countryDetailed struct {
+	GeoNameID        uint64              `json:"geonameId"`
+	Continent        value.ContinentCode `json:"continent"`
+	CountryCode      value.CountryCode   `json:"countryCode"`
+	ContinentName    string              `json:"continentName"`
+	CountryName      string              `json:"countryName"`
+	IsoAlpha3        string              `json:"isoAlpha3"`
+	IsoNumeric       uint64              `json:"isoNumeric,string"`
+	FipsCode         string              `json:"fipsCode"`
+	Capital          string              `json:"capital"`
+	Languages        string              `json:"languages"`
+	PostalCodeFormat string              `json:"postalCodeFormat"`
+	CurrencyCode     string              `json:"currencyCode"`
+	Population       int64               `json:"population,string"`
+	AreaInSqKm       float64             `json:"areaInSqKm,string"`
+	South            float64             `json:"south"`
+	North            float64             `json:"north"`
+	East             float64             `json:"east"`
+	West             float64             `json:"west"`
+}
+
+type countryNearby struct {
+	Languages   string            `json:"languages"`
+	Distance    float64           `json:"distance,string"`
+	CountryCode value.CountryCode `json:"countryCode"`
+	CountryName string            `json:"countryName"`
+}
+
+type timezone struct {
+	TimezoneID  string            `json:"timezoneId"`
+	CountryCode value.CountryCode `json:"countryCode"`
+	CountryName string            `json:"countryName"`
+	Latitude    float64           `json:"lat"`
+	Longitude   float64           `json:"lng"`
+	Time        string            `json:"time"`
+	Sunset      string            `json:"sunset"`
+	Sunrise     string            `json:"sunrise"`
+	GMTOffset   int               `json:"gmtOffset"`
+	DSTOffset   int               `json:"dstOffset"`
+	RawOffset   int               `json:"rawOffset"`
+}
+
+// encodeGeoName converts the record into the wire format, the name is localized when the language is set.
+// Feature names are taken from the features when available.
+func (s *Server) encodeGeoName(item webservice.GeoName, language string) geoName {
+	if name, ok := s.localizedName(item.ID, language); ok {
+		item.Name = name
+	}
+
+	if name, ok := s.localizedName(item.Country.ID, language); ok {
+		item.Country.Name = name
+	}
+
+	codeName := item.Feature.CodeName
+	if codeName == "" {
+		codeName = s.featureNames[item.Feature.Class+"."+item.Feature.Code]
+	}
+
+	className := item.Feature.ClassName
+	if className == "" {
+		className = featureClassNames[item.Feature.Class]
+	}
+
+	return geoName{
+		GeoNameID:        item.ID,
+		Name:             item.Name,
+		ToponymName:      item.ToponymName,
+		FeatureClass:     item.Feature.Class,
+		FeatureClassName: className,
+		FeatureCode:      item.Feature.Code,
+		FeatureCodeName:  codeName,
+		Latitude:         item.Position.Latitude,
+		Longitude:        item.Position.Longitude,
+		Population:       item.Population,
+		CountryID:        item.Country.ID,
+		CountryCode:      item.Country.Code,
+		CountryName:      item.Country.Name,
+		AdminCode1:       item.AdminSubdivision.First.Code,
+		AdminName1:       item.AdminSubdivision.First.Name,
+		AdminCode2:       item.AdminSubdivision.Second.Code,
+		AdminName2:       item.AdminSubdivision.Second.Name,
+		AdminCode3:       item.AdminSubdivision.Third.Code,
+		AdminName3:       item.AdminSubdivision.Third.Name,
+		AdminCode4:       item.AdminSubdivision.Fourth.Code,
+		AdminName4:       item.AdminSubdivision.Fourth.Name,
+		AdminCode5:       item.AdminSubdivision.Fifth.Code,
+		AdminName5:       item.AdminSubdivision.Fifth.Name,
+	}
+}
+
+func (s *Server) encodeGeoNames(items []webservice.GeoName, language string) []geoName {
+	res := make([]geoName, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, s.encodeGeoName(item, language))
+	}
+
+	return res
+}
+
+func (s *Server) encodeGeoNamesNearby(items []webservice.GeoNameNearby, language string) []geoNameNearby {
+	res := make([]geoNameNearby, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, geoNameNearby{
+			geoName:  s.encodeGeoName(item.GeoName, language),
+			Distance: item.Distance,
+		})
+	}
+
+	return res
+}
+
+func encodeBoundingBox(box value.BoundingBox) boundingBox {
+	return boundingBox{
+		West:  box.West,
+		East:  box.East,
+		North: box.North,
+		South: box.South,
+	}
+}
+
+func encodeLanguages(languages []string) string {
+	return strings.Join(languages, ",")
+}
+
+// localizedName returns the name of the record in the language, preferred names first, historic and colloquial
+// names are skipped.
+func (s *Server) localizedName(id uint64, language string) (string, bool) {
+	if language == "" {
+		return "", false
+	}
+
+	res, found := "", false
+
+	for _, item := range s.alternateNames[id] {
+		if item.Language != language || item.Historic || item.Colloquial {
+			continue
+		}
+
+		if item.Preferred {
+			return item.Value, true
+		}
+
+		if !found {
+			res, found = item.Value, true
+		}
+	}
+
+	return res, found
+}
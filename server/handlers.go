@@ -0,0 +1,470 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/platx/geonames/boundary"
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/spatial"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+const (
+	pathSearch              = "/searchJSON"
+	pathGet                 = "/getJSON"
+	pathFindNearbyPlaceName = "/findNearbyPlaceNameJSON"
+	pathFindNearby          = "/findNearbyJSON"
+	pathHierarchy           = "/hierarchyJSON"
+	pathChildren            = "/childrenJSON"
+	pathSiblings            = "/siblingsJSON"
+	pathCountryInfo         = "/countryInfoJSON"
+	pathCountryCode         = "/countryCodeJSON"
+	pathTimezone            = "/timezoneJSON"
+)
+
+const (
+	defaultChildrenRows = 200
+	// timezoneCandidates number of nearest places searched for one with a timezone
+	timezoneCandidates = 16
+	timeFormat         = "2006-01-02 15:04"
+)
+
+// handler returns the response to encode as JSON or an error to encode as status response.
+type handler func(r *http.Request) (any, error)
+
+func (s *Server) routes() {
+	s.handle(pathSearch, s.searchJSON)
+	s.handle(pathGet, s.getJSON)
+	s.handle(pathFindNearbyPlaceName, s.findNearbyPlaceNameJSON)
+	s.handle(pathFindNearby, s.findNearbyJSON)
+	s.handle(pathHierarchy, s.hierarchyJSON)
+	s.handle(pathChildren, s.childrenJSON)
+	s.handle(pathSiblings, s.siblingsJSON)
+	s.handle(pathCountryInfo, s.countryInfoJSON)
+	s.handle(pathCountryCode, s.countryCodeJSON)
+	s.handle(pathTimezone, s.timezoneJSON)
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusNotFound, errorResponse{
+			Status: errorStatus{Message: "service not implemented", Value: value.ErrCodeServiceNotImplemented},
+		})
+	})
+}
+
+func (s *Server) handle(path string, h handler) {
+	s.mux.HandleFunc(http.MethodGet+" "+path, func(w http.ResponseWriter, r *http.Request) {
+		res, err := h(r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	})
+}
+
+// writeError writes the error in the status envelope of the webservice. Like GeoNames, the status code is 200.
+func writeError(w http.ResponseWriter, err error) {
+	code := value.ErrCode(value.ErrCodeOther)
+
+	switch {
+	case errors.Is(err, ErrInvalidParameter):
+		code = value.ErrCodeInvalidParameter
+	case errors.Is(err, ErrNotFound):
+		code = value.ErrCodeRecordNotExist
+	case errors.Is(err, ErrNoResult):
+		code = value.ErrCodeNoResultFound
+	}
+
+	writeJSON(w, http.StatusOK, errorResponse{Status: errorStatus{Message: err.Error(), Value: code}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// requirePosition rejects requests without lat or lng, decoding would leave them at 0 otherwise.
+func requirePosition(values url.Values) error {
+	for _, key := range []string{"lat", "lng"} {
+		if values.Get(key) == "" {
+			return fmt.Errorf("%w, missing %s", ErrInvalidParameter, key)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) searchJSON(r *http.Request) (any, error) {
+	var req webservice.SearchRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	items, total, err := s.search.SearchTotal(r.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	// the search index already localizes the names
+	return searchResponse{TotalResultsCount: total, Items: s.encodeGeoNames(items, "")}, nil
+}
+
+func (s *Server) getJSON(r *http.Request) (any, error) {
+	var req webservice.GetRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	item, err := s.place(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	country, _ := s.gazetteer.Country(item.CountryCode)
+
+	alternateNames := make([]alternateName, 0, len(s.alternateNames[item.ID]))
+	for _, name := range s.alternateNames[item.ID] {
+		alternateNames = append(alternateNames, alternateName{Language: name.Language, Value: name.Value})
+	}
+
+	var tz timezoneInfo
+
+	if location, err := time.LoadLocation(item.Timezone); err == nil && item.Timezone != "" {
+		tz.Name = item.Timezone
+		tz.GMTOffset, tz.DSTOffset = offsets(location, s.now().Year())
+	}
+
+	return geoNameDetailed{
+		geoName:        s.encodeGeoName(s.gazetteer.GeoName(item), req.Language),
+		ContinentCode:  country.ContinentCode,
+		ASCIIName:      item.NameASCII,
+		AlternateNames: alternateNames,
+		Timezone:       tz,
+		Elevation:      item.Elevation,
+		SRTM3:          item.DigitalElevationModel,
+		// the dump files have a single elevation model, it is returned for both
+		Astergdem:   item.DigitalElevationModel,
+		BoundingBox: encodeBoundingBox(s.countryBoxes[item.ID]),
+	}, nil
+}
+
+func (s *Server) findNearbyPlaceNameJSON(r *http.Request) (any, error) {
+	var req webservice.FindNearbyPlaceNameRequest
+	if err := decodePositionQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	items, err := s.geocoder.FindNearbyPlaceName(r.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return geoNamesResponse[geoNameNearby]{Items: s.encodeGeoNamesNearby(items, req.Language)}, nil
+}
+
+func (s *Server) findNearbyJSON(r *http.Request) (any, error) {
+	var req webservice.FindNearbyRequest
+	if err := decodePositionQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	filter := spatial.Filter{
+		FeatureClass:  req.FeatureClass,
+		FeatureCode:   req.FeatureCode,
+		Country:       nil,
+		MinPopulation: 0,
+	}
+
+	if req.LocalCountry {
+		closest := s.nearest(req.Position, 1, value.Deref(req.Radius), filter)
+		if len(closest) == 0 {
+			return geoNamesResponse[geoNameNearby]{Items: []geoNameNearby{}}, nil
+		}
+
+		filter.Country = []value.CountryCode{closest[0].CountryCode}
+	}
+
+	found := s.nearest(req.Position, int(max(req.MaxRows, 1)), value.Deref(req.Radius), filter)
+	items := make([]webservice.GeoNameNearby, 0, len(found))
+
+	for _, item := range found {
+		items = append(items, webservice.GeoNameNearby{
+			GeoName:  s.gazetteer.GeoName(item.GeoName),
+			Distance: item.Distance,
+		})
+	}
+
+	return geoNamesResponse[geoNameNearby]{Items: s.encodeGeoNamesNearby(items, "")}, nil
+}
+
+func (s *Server) hierarchyJSON(r *http.Request) (any, error) {
+	var req webservice.HierarchyRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.place(req.ID); err != nil {
+		return nil, err
+	}
+
+	return geoNamesResponse[geoName]{Items: s.encodePlaces(s.hierarchy.chain(req.ID), "")}, nil
+}
+
+func (s *Server) childrenJSON(r *http.Request) (any, error) {
+	var req webservice.ChildrenRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.place(req.ID); err != nil {
+		return nil, err
+	}
+
+	if req.MaxRows == 0 {
+		req.MaxRows = defaultChildrenRows
+	}
+
+	ids := s.hierarchy.childrenOf(req.ID, req.Hierarchy)
+
+	return geoNamesResponse[geoName]{Items: s.encodePlaces(ids[:min(int(req.MaxRows), len(ids))], "")}, nil
+}
+
+func (s *Server) siblingsJSON(r *http.Request) (any, error) {
+	var req webservice.SiblingsRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.place(req.ID); err != nil {
+		return nil, err
+	}
+
+	return geoNamesResponse[geoName]{Items: s.encodePlaces(s.hierarchy.siblings(req.ID), "")}, nil
+}
+
+func (s *Server) countryInfoJSON(r *http.Request) (any, error) {
+	var req webservice.CountryInfoRequest
+	if err := decodeQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	items := make([]countryDetailed, 0)
+
+	for country := range s.gazetteer.Countries() {
+		if len(req.Country) > 0 && !slices.Contains(req.Country, country.Code) {
+			continue
+		}
+
+		items = append(items, s.encodeCountry(country, req.Language))
+	}
+
+	slices.SortFunc(items, func(a, b countryDetailed) int {
+		return cmp.Or(cmp.Compare(a.CountryName, b.CountryName), cmp.Compare(a.CountryCode, b.CountryCode))
+	})
+
+	return geoNamesResponse[countryDetailed]{Items: items}, nil
+}
+
+// countryCodeJSON uses the country shapes when available, the country of the nearest place within the radius
+// otherwise.
+func (s *Server) countryCodeJSON(r *http.Request) (any, error) {
+	var req webservice.CountryCodeRequest
+	if err := decodePositionQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	if s.boundary != nil {
+		res, err := s.boundary.CountryCode(r.Context(), req)
+		if errors.Is(err, boundary.ErrNotFound) {
+			return nil, fmt.Errorf("%w => %w", ErrNoResult, err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return s.encodeCountryNearby(res, req.Language), nil
+	}
+
+	found := s.nearest(req.Position, 1, value.Deref(req.Radius), spatial.Filter{
+		FeatureClass:  nil,
+		FeatureCode:   nil,
+		Country:       nil,
+		MinPopulation: 0,
+	})
+	if len(found) == 0 || found[0].CountryCode == "" {
+		return nil, ErrNoResult
+	}
+
+	country, _ := s.gazetteer.Country(found[0].CountryCode)
+
+	return s.encodeCountryNearby(webservice.CountryNearby{
+		Country:   value.Country{ID: country.ID, Code: found[0].CountryCode, Name: country.Name},
+		Languages: country.Languages,
+		Distance:  0,
+	}, req.Language), nil
+}
+
+// timezoneJSON returns the timezone of the nearest place having one. The offsets are whole hours since
+// webservice.Timezone decodes them as integers.
+func (s *Server) timezoneJSON(r *http.Request) (any, error) {
+	var req webservice.TimezoneRequest
+	if err := decodePositionQuery(r.URL.Query(), &req); err != nil {
+		return nil, err
+	}
+
+	item, ok := s.timezonePlace(req.Position, value.Deref(req.Radius))
+	if !ok {
+		return nil, ErrNoResult
+	}
+
+	location, err := time.LoadLocation(item.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone location => %w", err)
+	}
+
+	now := s.now().In(location)
+
+	date := now
+	if !req.Date.IsZero() {
+		date = req.Date
+	}
+
+	sunrise, sunset := sunTimes(req.Position, date.Year(), date.Month(), date.Day())
+	gmtOffset, dstOffset := offsets(location, now.Year())
+
+	country, _ := s.gazetteer.Country(item.CountryCode)
+
+	countryName := country.Name
+	if name, ok := s.localizedName(country.ID, req.Language); ok {
+		countryName = name
+	}
+
+	return timezone{
+		TimezoneID:  item.Timezone,
+		CountryCode: item.CountryCode,
+		CountryName: countryName,
+		Latitude:    req.Position.Latitude,
+		Longitude:   req.Position.Longitude,
+		Time:        now.Format(timeFormat),
+		Sunset:      sunset.In(location).Format(timeFormat),
+		Sunrise:     sunrise.In(location).Format(timeFormat),
+		GMTOffset:   int(gmtOffset),
+		DSTOffset:   int(dstOffset),
+		RawOffset:   int(math.Min(gmtOffset, dstOffset)),
+	}, nil
+}
+
+// decodePositionQuery is decodeQuery for requests requiring a position.
+func decodePositionQuery(values url.Values, v any) error {
+	if err := requirePosition(values); err != nil {
+		return err
+	}
+
+	return decodeQuery(values, v)
+}
+
+func (s *Server) place(id uint64) (download.GeoName, error) {
+	item, ok := s.places[id]
+	if !ok {
+		return download.GeoName{}, fmt.Errorf("%w, geonameId %d", ErrNotFound, id)
+	}
+
+	return item, nil
+}
+
+// nearest returns up to k places within the radius, 0 means unlimited.
+func (s *Server) nearest(position value.Position, k int, radius float64, filter spatial.Filter) []spatial.Result {
+	res := s.spatial.Nearest(position, k, filter)
+
+	if radius > 0 {
+		if i := slices.IndexFunc(res, func(item spatial.Result) bool { return item.Distance > radius }); i >= 0 {
+			res = res[:i]
+		}
+	}
+
+	return res
+}
+
+// timezonePlace returns the nearest place having a timezone within the radius, 0 means unlimited.
+func (s *Server) timezonePlace(position value.Position, radius float64) (download.GeoName, bool) {
+	for _, item := range s.nearest(position, timezoneCandidates, radius, spatial.Filter{
+		FeatureClass:  nil,
+		FeatureCode:   nil,
+		Country:       nil,
+		MinPopulation: 0,
+	}) {
+		if item.Timezone != "" {
+			return item.GeoName, true
+		}
+	}
+
+	return download.GeoName{}, false
+}
+
+func (s *Server) encodePlaces(ids []uint64, language string) []geoName {
+	res := make([]geoName, 0, len(ids))
+
+	for _, id := range ids {
+		res = append(res, s.encodeGeoName(s.gazetteer.GeoName(s.places[id]), language))
+	}
+
+	return res
+}
+
+func (s *Server) encodeCountry(country download.Country, language string) countryDetailed {
+	name := country.Name
+	if localized, ok := s.localizedName(country.ID, language); ok {
+		name = localized
+	}
+
+	box := s.countryBoxes[country.ID]
+
+	return countryDetailed{
+		GeoNameID:        country.ID,
+		Continent:        country.ContinentCode,
+		CountryCode:      country.Code,
+		ContinentName:    continentNames[country.ContinentCode],
+		CountryName:      name,
+		IsoAlpha3:        country.IsoAlpha3,
+		IsoNumeric:       country.IsoNumeric,
+		FipsCode:         country.FipsCode,
+		Capital:          country.Capital,
+		Languages:        encodeLanguages(country.Languages),
+		PostalCodeFormat: country.PostalCodeFormat,
+		CurrencyCode:     country.CurrencyCode,
+		Population:       country.Population,
+		AreaInSqKm:       country.AreaInSqKm,
+		South:            box.South,
+		North:            box.North,
+		East:             box.East,
+		West:             box.West,
+	}
+}
+
+func (s *Server) encodeCountryNearby(country webservice.CountryNearby, language string) countryNearby {
+	name := country.Name
+	if localized, ok := s.localizedName(country.ID, language); ok {
+		name = localized
+	}
+
+	return countryNearby{
+		Languages:   encodeLanguages(country.Languages),
+		Distance:    country.Distance,
+		CountryCode: country.Code,
+		CountryName: name,
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+)
+
+func Test_Server_errors(t *testing.T) {
+	t.Parallel()
+
+	srv, err := Build(testSource())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		code   value.ErrCode
+	}{
+		{
+			name:   "invalid parameter",
+			target: "/getJSON?geonameId=abc",
+			status: http.StatusOK,
+			code:   value.ErrCodeInvalidParameter,
+		},
+		{
+			name:   "missing position",
+			target: "/timezoneJSON?lat=48.8",
+			status: http.StatusOK,
+			code:   value.ErrCodeInvalidParameter,
+		},
+		{
+			name:   "record does not exist",
+			target: "/hierarchyJSON?geonameId=1",
+			status: http.StatusOK,
+			code:   value.ErrCodeRecordNotExist,
+		},
+		{
+			name:   "no result",
+			target: "/countryCodeJSON?lat=0&lng=0",
+			status: http.StatusOK,
+			code:   value.ErrCodeNoResultFound,
+		},
+		{
+			name:   "not implemented",
+			target: "/postalCodeSearchJSON?postalcode=75001",
+			status: http.StatusNotFound,
+			code:   value.ErrCodeServiceNotImplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			var actual errorResponse
+
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&actual))
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.code, actual.Status.Value)
+			assert.NotEmpty(t, actual.Status.Message)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/value"
+)
+
+// earthID is the root of the GeoNames hierarchy.
+const earthID = 6295630
+
+// hierarchyAdmin is the hierarchy.txt type of the administrative hierarchy, entries without type belong to it too.
+const hierarchyAdmin = "ADM"
+
+// continentIDs are the records of the continents, the parents of the countries.
+var continentIDs = map[value.ContinentCode]uint64{
+	value.ContinentCodeAfrica:       6255146,
+	value.ContinentCodeAsia:         6255147,
+	value.ContinentCodeEurope:       6255148,
+	value.ContinentCodeNorthAmerica: 6255149,
+	value.ContinentCodeSouthAmerica: 6255150,
+	value.ContinentCodeOceania:      6255151,
+	value.ContinentCodeAntarctica:   6255152,
+}
+
+// adminFeatureCodes are the feature codes of the admin division levels, indexed by level - 1.
+var adminFeatureCodes = []string{"ADM1", "ADM2", "ADM3", "ADM4"}
+
+// hierarchy holds the parent and children of every record, filled once while the server is built.
+type hierarchy struct {
+	places  map[uint64]download.GeoName
+	parents map[uint64]uint64
+	// children of the administrative hierarchy, populated places and admin divisions only
+	children map[uint64][]uint64
+	// other maps the hierarchy.txt types other than ADM to their children, e.g. `tourism`
+	other map[string]map[uint64][]uint64
+}
+
+// newHierarchy derives the parents from the admin codes of the records, countries belong to their continent and
+// continents to the Earth. Administrative entries of hierarchy.txt take precedence over the derived parents.
+func newHierarchy(
+	places map[uint64]download.GeoName,
+	gaz *gazetteer.Gazetteer,
+	items []download.HierarchyItem,
+) *hierarchy {
+	res := &hierarchy{
+		places:   places,
+		parents:  make(map[uint64]uint64, len(places)),
+		children: make(map[uint64][]uint64),
+		other:    make(map[string]map[uint64][]uint64),
+	}
+
+	admins, countries := adminRecords(places, gaz)
+
+	for id, item := range places {
+		if parent, ok := derivedParent(item, gaz, admins, countries); ok && parent != id {
+			if _, exists := places[parent]; exists {
+				res.parents[id] = parent
+			}
+		}
+	}
+
+	for _, item := range items {
+		_, parentExists := places[item.ParentID]
+		_, childExists := places[item.ChildID]
+
+		if !parentExists || !childExists || item.ParentID == item.ChildID {
+			continue
+		}
+
+		if item.Type == "" || item.Type == hierarchyAdmin {
+			res.parents[item.ChildID] = item.ParentID
+
+			continue
+		}
+
+		if res.other[item.Type] == nil {
+			res.other[item.Type] = make(map[uint64][]uint64)
+		}
+
+		res.other[item.Type][item.ParentID] = append(res.other[item.Type][item.ParentID], item.ChildID)
+	}
+
+	for id, parent := range res.parents {
+		if isHierarchyMember(places[id]) {
+			res.children[parent] = append(res.children[parent], id)
+		}
+	}
+
+	for _, children := range res.children {
+		res.sort(children)
+	}
+
+	for _, other := range res.other {
+		for _, children := range other {
+			res.sort(children)
+		}
+	}
+
+	return res
+}
+
+// chain returns the records from the root down to the given one.
+func (h *hierarchy) chain(id uint64) []uint64 {
+	res := []uint64{id}
+	seen := map[uint64]bool{id: true}
+
+	for parent, ok := h.parents[id]; ok && !seen[parent]; parent, ok = h.parents[parent] {
+		seen[parent] = true
+		res = append(res, parent)
+	}
+
+	slices.Reverse(res)
+
+	return res
+}
+
+// childrenOf returns the children of the record in the given hierarchy type, the administrative one when empty.
+func (h *hierarchy) childrenOf(id uint64, kind value.Hierarchy) []uint64 {
+	if kind == "" || kind == hierarchyAdmin {
+		return h.children[id]
+	}
+
+	return h.other[string(kind)][id]
+}
+
+// siblings returns the children of the parent of the record including the record itself, only the record itself
+// when it has no parent.
+func (h *hierarchy) siblings(id uint64) []uint64 {
+	parent, ok := h.parents[id]
+	if !ok {
+		return []uint64{id}
+	}
+
+	res := h.children[parent]
+	if !slices.Contains(res, id) {
+		res = append(slices.Clone(res), id)
+		h.sort(res)
+	}
+
+	return res
+}
+
+func (h *hierarchy) sort(ids []uint64) {
+	slices.SortFunc(ids, func(a, b uint64) int {
+		return cmp.Or(cmp.Compare(h.places[a].Name, h.places[b].Name), cmp.Compare(a, b))
+	})
+}
+
+// adminRecords returns the admin division records by their code, e.g. `US.CA.037`, and the countries by their code.
+func adminRecords(
+	places map[uint64]download.GeoName,
+	gaz *gazetteer.Gazetteer,
+) (map[string]uint64, map[value.CountryCode]uint64) {
+	admins := make(map[string]uint64)
+	countries := make(map[value.CountryCode]uint64)
+
+	for country := range gaz.Countries() {
+		if _, ok := places[country.ID]; ok {
+			countries[country.Code] = country.ID
+		}
+	}
+
+	for id, item := range places {
+		if level := adminLevel(item); level > 0 {
+			admins[adminKey(item, level)] = id
+		}
+
+		if _, ok := countries[item.CountryCode]; !ok && strings.HasPrefix(item.FeatureCode, "PCL") {
+			countries[item.CountryCode] = id
+		}
+	}
+
+	return admins, countries
+}
+
+// derivedParent returns the parent by feature and admin codes: the deepest admin division above the record,
+// otherwise its country.
+func derivedParent(
+	item download.GeoName,
+	gaz *gazetteer.Gazetteer,
+	admins map[string]uint64,
+	countries map[value.CountryCode]uint64,
+) (uint64, bool) {
+	switch {
+	case item.ID == earthID:
+		return 0, false
+	case item.FeatureCode == "CONT":
+		return earthID, true
+	case strings.HasPrefix(item.FeatureCode, "PCL"):
+		country, _ := gaz.Country(item.CountryCode)
+		id, ok := continentIDs[country.ContinentCode]
+
+		return id, ok
+	}
+
+	level := adminLevel(item)
+	if level == 0 {
+		level = len(adminFeatureCodes) + 1
+	}
+
+	for l := level - 1; l > 0; l-- {
+		if id, ok := admins[adminKey(item, l)]; ok && adminCode(item, l) != "" {
+			return id, true
+		}
+	}
+
+	id, ok := countries[item.CountryCode]
+
+	return id, ok
+}
+
+// adminLevel returns the admin division level of the record, 0 when it is none.
+func adminLevel(item download.GeoName) int {
+	if item.FeatureClass != "A" {
+		return 0
+	}
+
+	return slices.Index(adminFeatureCodes, item.FeatureCode) + 1
+}
+
+// adminKey returns the admin code of the record up to the level as used in the admin*Codes.txt files.
+func adminKey(item download.GeoName, level int) string {
+	res := string(item.CountryCode)
+
+	for l := 1; l <= level; l++ {
+		res += "." + adminCode(item, l)
+	}
+
+	return res
+}
+
+func adminCode(item download.GeoName, level int) string {
+	return []string{item.AdminCode.First, item.AdminCode.Second, item.AdminCode.Third, item.AdminCode.Fourth}[level-1]
+}
+
+// isHierarchyMember reports whether the record is listed as child, admin divisions, populated places and continents.
+func isHierarchyMember(item download.GeoName) bool {
+	return item.FeatureClass == "A" || item.FeatureClass == "P" || item.FeatureCode == "CONT"
+}
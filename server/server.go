@@ -0,0 +1,254 @@
+// Package server serves GeoNames webservice JSON endpoints from locally loaded dump files, so that
+// webservice.Client can use a private, quota-free instance via webservice.WithBaseURL.
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/platx/geonames/boundary"
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/internal/gazetteer"
+	"github.com/platx/geonames/reverse"
+	"github.com/platx/geonames/search"
+	"github.com/platx/geonames/spatial"
+	"github.com/platx/geonames/value"
+)
+
+var (
+	ErrMissingPlaces    = errors.New("places iterator is required")
+	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrNotFound         = errors.New("record does not exist")
+	ErrNoResult         = errors.New("no result found")
+)
+
+// Source is the dump data to serve. Places is required, each other file enables or enriches some endpoints.
+type Source struct {
+	// Places records to serve, e.g. allCountries or cities500, required
+	Places download.Iterator[download.GeoName]
+	// AlternateNames used by searchJSON languages and getJSON alternate names, optional
+	AlternateNames download.Iterator[download.AlternateName]
+	// AdminDivisionFirst admin1CodesASCII.txt entries, optional
+	AdminDivisionFirst download.Iterator[download.AdminDivision]
+	// AdminDivisionSecond admin2Codes.txt entries, optional
+	AdminDivisionSecond download.Iterator[download.AdminDivision]
+	// Countries countryInfo.txt entries, required by countryInfoJSON, optional
+	Countries download.Iterator[download.Country]
+	// Hierarchy hierarchy.txt entries, optional, the hierarchy is derived from admin codes otherwise
+	Hierarchy download.Iterator[download.HierarchyItem]
+	// Shapes country shapes used by countryCodeJSON, optional, the country of the nearest place is used otherwise
+	Shapes download.Iterator[download.Shape]
+	// Features featureCodes_xx.txt entries used for feature code names, optional
+	Features download.Iterator[download.Feature]
+}
+
+// Server is an http.Handler serving searchJSON, getJSON, findNearbyPlaceNameJSON, findNearbyJSON, hierarchyJSON,
+// childrenJSON, siblingsJSON, countryInfoJSON, countryCodeJSON and timezoneJSON. The username parameter is ignored.
+// Build loads all data up front, requests only read it.
+type Server struct {
+	mux       *http.ServeMux
+	places    map[uint64]download.GeoName
+	gazetteer *gazetteer.Gazetteer
+	search    *search.Index
+	geocoder  *reverse.Geocoder
+	spatial   *spatial.Index
+	// boundary is nil without shapes
+	boundary       *boundary.Index
+	alternateNames map[uint64][]download.AlternateName
+	featureNames   map[string]string
+	countryBoxes   map[uint64]value.BoundingBox
+	hierarchy      *hierarchy
+	now            func() time.Time
+}
+
+type Option func(*Server)
+
+// WithNow sets the clock used for the current time of timezoneJSON, default is time.Now.
+func WithNow(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Build reads all source iterators and builds the indexes of all endpoints.
+func Build(src Source, opts ...Option) (*Server, error) {
+	if src.Places == nil {
+		return nil, ErrMissingPlaces
+	}
+
+	res := &Server{
+		mux:            http.NewServeMux(),
+		places:         make(map[uint64]download.GeoName),
+		gazetteer:      nil,
+		search:         nil,
+		geocoder:       nil,
+		spatial:        nil,
+		boundary:       nil,
+		alternateNames: make(map[uint64][]download.AlternateName),
+		featureNames:   make(map[string]string),
+		countryBoxes:   make(map[uint64]value.BoundingBox),
+		hierarchy:      nil,
+		now:            time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	data, err := collectSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = res.build(data); err != nil {
+		return nil, err
+	}
+
+	res.routes()
+
+	return res, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// data holds the collected source items, the indexes are built from several passes over them.
+type data struct {
+	places         []download.GeoName
+	alternateNames []download.AlternateName
+	adminFirst     []download.AdminDivision
+	adminSecond    []download.AdminDivision
+	countries      []download.Country
+	hierarchy      []download.HierarchyItem
+	shapes         []download.Shape
+	features       []download.Feature
+}
+
+func collectSource(src Source) (data, error) {
+	var (
+		res data
+		err error
+	)
+
+	if res.places, err = collect(src.Places); err != nil {
+		return res, fmt.Errorf("read places => %w", err)
+	}
+
+	if res.alternateNames, err = collect(src.AlternateNames); err != nil {
+		return res, fmt.Errorf("read alternate names => %w", err)
+	}
+
+	if res.adminFirst, err = collect(src.AdminDivisionFirst); err != nil {
+		return res, fmt.Errorf("read first admin divisions => %w", err)
+	}
+
+	if res.adminSecond, err = collect(src.AdminDivisionSecond); err != nil {
+		return res, fmt.Errorf("read second admin divisions => %w", err)
+	}
+
+	if res.countries, err = collect(src.Countries); err != nil {
+		return res, fmt.Errorf("read countries => %w", err)
+	}
+
+	if res.hierarchy, err = collect(src.Hierarchy); err != nil {
+		return res, fmt.Errorf("read hierarchy => %w", err)
+	}
+
+	if res.shapes, err = collect(src.Shapes); err != nil {
+		return res, fmt.Errorf("read shapes => %w", err)
+	}
+
+	if res.features, err = collect(src.Features); err != nil {
+		return res, fmt.Errorf("read features => %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *Server) build(d data) error {
+	var err error
+
+	s.gazetteer, err = gazetteer.Build(
+		download.FromSlice(d.adminFirst),
+		download.FromSlice(d.adminSecond),
+		download.FromSlice(d.countries),
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.search, err = search.Build(search.Source{
+		Places:              download.FromSlice(d.places),
+		AlternateNames:      download.FromSlice(d.alternateNames),
+		AdminDivisionFirst:  download.FromSlice(d.adminFirst),
+		AdminDivisionSecond: download.FromSlice(d.adminSecond),
+		Countries:           download.FromSlice(d.countries),
+	}); err != nil {
+		return fmt.Errorf("build search index => %w", err)
+	}
+
+	if s.geocoder, err = reverse.Build(reverse.Source{
+		Places:              download.FromSlice(d.places),
+		AdminDivisionFirst:  download.FromSlice(d.adminFirst),
+		AdminDivisionSecond: download.FromSlice(d.adminSecond),
+		Countries:           download.FromSlice(d.countries),
+	}); err != nil {
+		return fmt.Errorf("build geocoder => %w", err)
+	}
+
+	if len(d.shapes) > 0 {
+		if s.boundary, err = boundary.Build(boundary.Source{
+			Shapes:    download.FromSlice(d.shapes),
+			Countries: download.FromSlice(d.countries),
+		}); err != nil {
+			return fmt.Errorf("build boundary index => %w", err)
+		}
+	}
+
+	s.spatial = spatial.New(d.places)
+
+	for _, item := range d.places {
+		s.places[item.ID] = item
+	}
+
+	for _, item := range d.alternateNames {
+		if _, ok := s.places[item.GeoNameID]; ok {
+			s.alternateNames[item.GeoNameID] = append(s.alternateNames[item.GeoNameID], item)
+		}
+	}
+
+	for _, item := range d.features {
+		s.featureNames[item.Code] = item.Name
+	}
+
+	for _, item := range d.shapes {
+		s.countryBoxes[item.GeoNameID] = item.Geometry.BoundingBox()
+	}
+
+	s.hierarchy = newHierarchy(s.places, s.gazetteer, d.hierarchy)
+
+	return nil
+}
+
+func collect[T any](items download.Iterator[T]) ([]T, error) {
+	if items == nil {
+		return nil, nil
+	}
+
+	res := make([]T, 0)
+
+	for item, err := range items {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, item)
+	}
+
+	return slices.Clip(res), nil
+}
@@ -0,0 +1,512 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+var testNow = time.Date(2024, time.June, 21, 10, 0, 0, 0, time.UTC)
+
+func testSource() Source {
+	return Source{
+		Places:              download.FromSlice(fixture.Places()),
+		AlternateNames:      download.FromSlice(fixture.AlternateNames()),
+		AdminDivisionFirst:  download.FromSlice(fixture.AdminDivisionsFirst()),
+		AdminDivisionSecond: download.FromSlice(fixture.AdminDivisionsSecond()),
+		Countries:           download.FromSlice(fixture.Countries()),
+		Hierarchy:           download.FromSlice(fixture.Hierarchy()),
+		Shapes:              download.FromSlice(fixture.Shapes()),
+		Features:            download.FromSlice(fixture.Features()),
+	}
+}
+
+// newTestClient returns a webservice client of a server built from the source.
+func newTestClient(t *testing.T, src Source) *webservice.Client {
+	t.Helper()
+
+	srv, err := Build(src, WithNow(func() time.Time { return testNow }))
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	return webservice.NewClient("demo", webservice.WithBaseURL(ts.URL))
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	withSource := func(modify func(src *Source)) Source {
+		src := testSource()
+		modify(&src)
+
+		return src
+	}
+
+	tests := []struct {
+		name string
+		src  Source
+		err  string
+	}{
+		{
+			name: "missing places",
+			src:  withSource(func(src *Source) { src.Places = nil }),
+			err:  ErrMissingPlaces.Error(),
+		},
+		{
+			name: "places error",
+			src:  withSource(func(src *Source) { src.Places = fixture.Failing[download.GeoName](nil, assert.AnError) }),
+			err:  "read places => " + assert.AnError.Error(),
+		},
+		{
+			name: "alternate names error",
+			src:  withSource(func(src *Source) { src.AlternateNames = fixture.Failing[download.AlternateName](nil, assert.AnError) }),
+			err:  "read alternate names => " + assert.AnError.Error(),
+		},
+		{
+			name: "countries error",
+			src:  withSource(func(src *Source) { src.Countries = fixture.Failing[download.Country](nil, assert.AnError) }),
+			err:  "read countries => " + assert.AnError.Error(),
+		},
+		{
+			name: "hierarchy error",
+			src:  withSource(func(src *Source) { src.Hierarchy = fixture.Failing[download.HierarchyItem](nil, assert.AnError) }),
+			err:  "read hierarchy => " + assert.AnError.Error(),
+		},
+		{
+			name: "shapes error",
+			src:  withSource(func(src *Source) { src.Shapes = fixture.Failing[download.Shape](nil, assert.AnError) }),
+			err:  "read shapes => " + assert.AnError.Error(),
+		},
+		{
+			name: "features error",
+			src:  withSource(func(src *Source) { src.Features = fixture.Failing[download.Feature](nil, assert.AnError) }),
+			err:  "read features => " + assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := Build(tt.src)
+
+			require.EqualError(t, err, tt.err)
+			assert.Nil(t, actual)
+		})
+	}
+
+	t.Run("places only", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Build(Source{Places: download.FromSlice(fixture.Places())})
+
+		require.NoError(t, err)
+		assert.NotNil(t, actual)
+	})
+}
+
+func Test_Server_Search(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	actual, err := client.Search(context.Background(), webservice.SearchRequest{
+		Name:         "paris",
+		Country:      []value.CountryCode{value.CountryCodeFrance},
+		FeatureClass: []string{"P"},
+		Language:     "ru",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []webservice.GeoName{{
+		ID:      2988507,
+		Country: value.Country{ID: 3017382, Code: value.CountryCodeFrance, Name: "France"},
+		AdminSubdivision: value.AdminDivisions{
+			First:  value.AdminDivision{Code: "11", Name: "Île-de-France"},
+			Second: value.AdminDivision{Code: "75", Name: "Paris"},
+			Third:  value.AdminDivision{Code: "751"},
+			Fourth: value.AdminDivision{Code: "75056"},
+		},
+		Feature: value.Feature{
+			Class:     "P",
+			ClassName: "city, village,...",
+			Code:      "PPLC",
+			CodeName:  "capital of a political entity",
+		},
+		Position:    value.Position{Latitude: 48.85341, Longitude: 2.3488},
+		Name:        "Париж",
+		ToponymName: "Paris",
+		Population:  2138551,
+	}}, actual)
+}
+
+func Test_Server_Get(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	t.Run("place", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := client.Get(context.Background(), webservice.GetRequest{ID: 2988507, Language: "ru"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Париж", actual.Name)
+		assert.Equal(t, "Paris", actual.ASCIIName)
+		assert.Equal(t, value.ContinentCodeEurope, actual.ContinentCode)
+		assert.Equal(t, []value.AlternateName{
+			{Language: "ru", Value: "Парижъ"},
+			{Language: "ru", Value: "Париж"},
+			{Language: "de", Value: "Paris"},
+			{Language: "link", Value: "https://en.wikipedia.org/wiki/Paris"},
+		}, actual.AlternateNames)
+		assert.Equal(t, value.Timezone{Name: "Europe/Paris", GMTOffset: 1, DSTOffset: 2}, actual.Timezone)
+		assert.Equal(t, int32(35), actual.Elevation)
+		assert.Equal(t, int32(42), actual.SRTM3)
+		assert.Equal(t, value.BoundingBox{}, actual.BoundingBox)
+	})
+
+	t.Run("country", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := client.Get(context.Background(), webservice.GetRequest{ID: 3017382, Language: "de"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Frankreich", actual.Name)
+		assert.Equal(t, "Frankreich", actual.Country.Name)
+		assert.Equal(t, value.BoundingBox{East: 7.5, West: -5, North: 51, South: 42}, actual.BoundingBox)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := client.Get(context.Background(), webservice.GetRequest{ID: 1})
+
+		var respErr *webservice.ResponseError
+
+		require.ErrorAs(t, err, &respErr)
+		assert.True(t, respErr.MatchCode(value.ErrCodeRecordNotExist))
+	})
+}
+
+func Test_Server_FindNearbyPlaceName(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	actual, err := client.FindNearbyPlaceName(context.Background(), webservice.FindNearbyPlaceNameRequest{
+		Position: value.Position{Latitude: 48.8, Longitude: 2.14},
+		MaxRows:  2,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2970153, 2988507}, nearbyIDs(actual))
+	assert.InDelta(t, 0.58, actual[0].Distance, 0.01)
+}
+
+func Test_Server_FindNearby(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	tests := []struct {
+		name  string
+		given webservice.FindNearbyRequest
+		exp   []uint64
+	}{
+		{
+			name:  "nearest",
+			given: webservice.FindNearbyRequest{Position: value.Position{Latitude: 48.858, Longitude: 2.29}},
+			exp:   []uint64{6254976},
+		},
+		{
+			name: "feature class",
+			given: webservice.FindNearbyRequest{
+				Position:     value.Position{Latitude: 48.858, Longitude: 2.29},
+				FeatureClass: []string{"A"},
+				MaxRows:      2,
+			},
+			exp: []uint64{2968815, 3012874},
+		},
+		{
+			name: "local country",
+			given: webservice.FindNearbyRequest{
+				Position:     value.Position{Latitude: 47.36667, Longitude: 8.55},
+				MaxRows:      10,
+				LocalCountry: true,
+			},
+			exp: []uint64{2657896, 2658434},
+		},
+		{
+			name: "radius",
+			given: webservice.FindNearbyRequest{
+				Position: value.Position{Latitude: 47.36667, Longitude: 8.55},
+				MaxRows:  10,
+				Radius:   value.Ptr(100.0),
+			},
+			exp: []uint64{2657896, 2658434},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := client.FindNearby(context.Background(), tt.given)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, nearbyIDs(actual))
+		})
+	}
+}
+
+func Test_Server_Hierarchy(t *testing.T) {
+	t.Parallel()
+
+	// the parent of an ADM record is unknown, the admin codes are used instead
+	src := testSource()
+	src.Hierarchy = download.FromSlice(append(fixture.Hierarchy(), download.HierarchyItem{ParentID: 1, ChildID: 2988507, Type: "ADM"}))
+
+	client := newTestClient(t, src)
+
+	tests := []struct {
+		name  string
+		given uint64
+		exp   []uint64
+	}{
+		{name: "monument", given: 6254976, exp: []uint64{6295630, 6255148, 3017382, 3012874, 2968815, 6254976}},
+		{name: "without admin division record", given: 2657896, exp: []uint64{6295630, 6255148, 2658434, 2657896}},
+		{name: "unknown parent", given: 2988507, exp: []uint64{6295630, 6255148, 3017382, 3012874, 2968815, 2988507}},
+		{name: "root", given: 6295630, exp: []uint64{6295630}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := client.Hierarchy(context.Background(), webservice.HierarchyRequest{ID: tt.given})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, ids(actual))
+		})
+	}
+}
+
+func Test_Server_Children(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	tests := []struct {
+		name  string
+		given webservice.ChildrenRequest
+		exp   []uint64
+	}{
+		{name: "continent", given: webservice.ChildrenRequest{ID: 6255148}, exp: []uint64{3017382, 2658434}},
+		{name: "admin division", given: webservice.ChildrenRequest{ID: 3012874}, exp: []uint64{2968815, 2970153}},
+		{name: "spots excluded", given: webservice.ChildrenRequest{ID: 2968815}, exp: []uint64{2988507}},
+		{name: "max rows", given: webservice.ChildrenRequest{ID: 3012874, MaxRows: 1}, exp: []uint64{2968815}},
+		{
+			name:  "other hierarchy",
+			given: webservice.ChildrenRequest{ID: 3017382, Hierarchy: value.HierarchyTourism},
+			exp:   []uint64{2970153},
+		},
+		{name: "leaf", given: webservice.ChildrenRequest{ID: 2988507}, exp: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := client.Children(context.Background(), tt.given)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, ids(actual))
+		})
+	}
+}
+
+func Test_Server_Siblings(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	tests := []struct {
+		name  string
+		given uint64
+		exp   []uint64
+	}{
+		{name: "place", given: 2970153, exp: []uint64{2968815, 2970153}},
+		{name: "spot", given: 6254976, exp: []uint64{2988507, 6254976}},
+		{name: "root", given: 6295630, exp: []uint64{6295630}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := client.Siblings(context.Background(), webservice.SiblingsRequest{ID: tt.given})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, ids(actual))
+		})
+	}
+}
+
+func Test_Server_CountryInfo(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := client.CountryInfo(context.Background(), webservice.CountryInfoRequest{})
+
+		require.NoError(t, err)
+
+		codes := make([]value.CountryCode, 0, len(actual))
+		for _, item := range actual {
+			codes = append(codes, item.Code)
+		}
+
+		assert.Equal(t, []value.CountryCode{
+			"BE", "BR", "CA", "FJ", "FR", "DE", "IL", "IT", "MC", "RU", "WS", "SG", "CH", "UA", "GB", "US",
+		}, codes)
+	})
+
+	t.Run("filtered and localized", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := client.CountryInfo(context.Background(), webservice.CountryInfoRequest{
+			Country:  []value.CountryCode{value.CountryCodeFrance},
+			Language: "de",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []webservice.CountryDetailed{{
+			Country:          value.Country{ID: 3017382, Code: value.CountryCodeFrance, Name: "Frankreich"},
+			Continent:        value.Continent{Code: value.ContinentCodeEurope, Name: "Europe"},
+			Capital:          "Paris",
+			Languages:        []string{"fr-FR", "frp"},
+			BoundingBox:      value.BoundingBox{East: 7.5, West: -5, North: 51, South: 42},
+			IsoAlpha3:        "FRA",
+			IsoNumeric:       250,
+			FipsCode:         "FR",
+			Population:       66987244,
+			AreaInSqKm:       547030,
+			PostalCodeFormat: "#####",
+			CurrencyCode:     "EUR",
+		}}, actual)
+	})
+}
+
+func Test_Server_CountryCode(t *testing.T) {
+	t.Parallel()
+
+	withShapes := newTestClient(t, testSource())
+
+	withoutShapes := testSource()
+	withoutShapes.Shapes = nil
+
+	tests := []struct {
+		name   string
+		client *webservice.Client
+		given  value.Position
+		exp    value.CountryCode
+		err    value.ErrCode
+	}{
+		{
+			name:   "shape",
+			client: withShapes,
+			given:  value.Position{Latitude: 48.8, Longitude: 2.3},
+			exp:    value.CountryCodeFrance,
+		},
+		{
+			name:   "outside shapes",
+			client: withShapes,
+			given:  value.Position{Latitude: 47.36, Longitude: 8.5},
+			err:    value.ErrCodeNoResultFound,
+		},
+		{
+			name:   "nearest place",
+			client: newTestClient(t, withoutShapes),
+			given:  value.Position{Latitude: 47.36, Longitude: 8.5},
+			exp:    value.CountryCodeSwitzerland,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tt.client.CountryCode(context.Background(), webservice.CountryCodeRequest{Position: tt.given})
+
+			if tt.err != 0 {
+				var respErr *webservice.ResponseError
+
+				require.ErrorAs(t, err, &respErr)
+				assert.True(t, respErr.MatchCode(tt.err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, actual.Code)
+		})
+	}
+}
+
+func Test_Server_Timezone(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, testSource())
+
+	actual, err := client.Timezone(context.Background(), webservice.TimezoneRequest{
+		Position: value.Position{Latitude: 48.85341, Longitude: 2.3488},
+		Date:     time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", actual.Name)
+	assert.Equal(t, value.CountryCodeFrance, actual.Country.Code)
+	assert.Equal(t, "France", actual.Country.Name)
+	assert.Equal(t, "2024-06-21 12:00", actual.Time.Format(timeFormat))
+	assert.Equal(t, "2024-12-21", actual.Sunrise.Format(time.DateOnly))
+	assert.Equal(t, 8, actual.Sunrise.Hour())
+	assert.Equal(t, 16, actual.Sunset.Hour())
+	assert.Equal(t, 1, actual.GMTOffset)
+	assert.Equal(t, 2, actual.DSTOffset)
+	assert.Equal(t, 1, actual.RawOffset)
+}
+
+func nearbyIDs(items []webservice.GeoNameNearby) []uint64 {
+	res := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.ID)
+	}
+
+	return res
+}
+
+func ids(items []webservice.GeoName) []uint64 {
+	res := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.ID)
+	}
+
+	return res
+}
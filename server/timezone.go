@@ -0,0 +1,62 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/platx/geonames/value"
+)
+
+const (
+	// julianUnixEpoch is the Julian date of 1970-01-01 00:00 UTC
+	julianUnixEpoch = 2440587.5
+	// julian2000 is the Julian date of 2000-01-01 12:00 UTC
+	julian2000 = 2451545.0
+	// days2000 is the number of days from 1970-01-01 to 2000-01-01
+	days2000      = 10957
+	secondsPerDay = 86400
+	// sunAltitude is the altitude of the sun center at sunrise and sunset, corrected for refraction and disc size
+	sunAltitude = -0.833
+	// earthTilt is the obliquity of the ecliptic
+	earthTilt = 23.4397
+)
+
+// offsets returns the offsets in hours from GMT on the 1st of January and the 1st of July of the year.
+func offsets(location *time.Location, year int) (float64, float64) {
+	const secondsPerHour = 3600
+
+	_, january := time.Date(year, time.January, 1, 0, 0, 0, 0, location).Zone()
+	_, july := time.Date(year, time.July, 1, 0, 0, 0, 0, location).Zone()
+
+	return float64(january) / secondsPerHour, float64(july) / secondsPerHour
+}
+
+// sunTimes returns sunrise and sunset on the date at the position using the sunrise equation, the precision is
+// about a minute. Sunrise and sunset are both solar noon in a polar night and solar midnight in a midnight sun.
+func sunTimes(position value.Position, year int, month time.Month, day int) (time.Time, time.Time) {
+	days := float64(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()/secondsPerDay - days2000)
+
+	// mean solar time, solar mean anomaly, equation of the center and ecliptic longitude
+	meanTime := days - position.Longitude/360
+	anomaly := math.Mod(357.5291+0.98560028*meanTime, 360)
+	center := 1.9148*sin(anomaly) + 0.02*sin(2*anomaly) + 0.0003*sin(3*anomaly)
+	longitude := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := julian2000 + meanTime + 0.0053*sin(anomaly) - 0.0069*sin(2*longitude)
+
+	declination := math.Asin(sin(longitude) * sin(earthTilt))
+	latitude := position.Latitude * math.Pi / 180
+	cosHourAngle := (sin(sunAltitude) - math.Sin(latitude)*math.Sin(declination)) /
+		(math.Cos(latitude) * math.Cos(declination))
+	hourAngle := math.Acos(max(-1, min(1, cosHourAngle))) * 180 / math.Pi
+
+	return julianTime(transit - hourAngle/360), julianTime(transit + hourAngle/360)
+}
+
+func julianTime(date float64) time.Time {
+	return time.Unix(int64(math.Round((date-julianUnixEpoch)*secondsPerDay)), 0).UTC()
+}
+
+// sin returns the sine of the angle in degrees.
+func sin(degrees float64) float64 {
+	return math.Sin(degrees * math.Pi / 180)
+}
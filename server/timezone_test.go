@@ -0,0 +1,96 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
+)
+
+func Test_offsets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		given   string
+		january float64
+		july    float64
+	}{
+		{name: "northern dst", given: "Europe/Paris", january: 1, july: 2},
+		{name: "southern dst", given: "Australia/Sydney", january: 11, july: 10},
+		{name: "half hour", given: "Asia/Kolkata", january: 5.5, july: 5.5},
+		{name: "utc", given: "UTC", january: 0, july: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			location, err := time.LoadLocation(tt.given)
+			require.NoError(t, err)
+
+			january, july := offsets(location, 2024)
+
+			assert.InDelta(t, tt.january, january, 0)
+			assert.InDelta(t, tt.july, july, 0)
+		})
+	}
+}
+
+func Test_sunTimes(t *testing.T) {
+	t.Parallel()
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		position value.Position
+		date     time.Time
+		sunrise  time.Time
+		sunset   time.Time
+	}{
+		{
+			name:     "summer solstice",
+			position: value.Position{Latitude: 48.85341, Longitude: 2.3488},
+			date:     time.Date(2024, time.June, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:  time.Date(2024, time.June, 21, 5, 47, 0, 0, paris),
+			sunset:   time.Date(2024, time.June, 21, 21, 58, 0, 0, paris),
+		},
+		{
+			name:     "winter solstice",
+			position: value.Position{Latitude: 48.85341, Longitude: 2.3488},
+			date:     time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:  time.Date(2024, time.December, 21, 8, 41, 0, 0, paris),
+			sunset:   time.Date(2024, time.December, 21, 16, 56, 0, 0, paris),
+		},
+		{
+			name:     "polar night",
+			position: value.Position{Latitude: 78.22, Longitude: 15.65},
+			date:     time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:  time.Date(2024, time.December, 21, 10, 57, 0, 0, time.UTC),
+			sunset:   time.Date(2024, time.December, 21, 10, 57, 0, 0, time.UTC),
+		},
+		{
+			name:     "date line",
+			position: value.Position{Latitude: -36.85, Longitude: 174.76},
+			date:     time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:  time.Date(2024, time.December, 20, 16, 57, 0, 0, time.UTC),
+			sunset:   time.Date(2024, time.December, 21, 7, 41, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sunrise, sunset := sunTimes(tt.position, tt.date.Year(), tt.date.Month(), tt.date.Day())
+
+			assert.WithinDuration(t, tt.sunrise, sunrise, 2*time.Minute)
+			assert.WithinDuration(t, tt.sunset, sunset, 2*time.Minute)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package spatial
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/platx/geonames/download"
+)
+
+const formatVersion = 1
+
+var ErrUnsupportedFormat = errors.New("unsupported index format")
+
+type encodedIndex struct {
+	Version uint8
+	Items   []download.GeoName
+}
+
+// WriteTo serializes the index, records are written in tree order so loading does not rebuild the tree.
+func (idx *Index) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w, n: 0}
+
+	err := gob.NewEncoder(counter).Encode(encodedIndex{Version: formatVersion, Items: idx.items})
+	if err != nil {
+		return counter.n, fmt.Errorf("encode index => %w", err)
+	}
+
+	return counter.n, nil
+}
+
+// Load reads an index previously serialized with WriteTo.
+func Load(r io.Reader) (*Index, error) {
+	var encoded encodedIndex
+
+	if err := gob.NewDecoder(r).Decode(&encoded); err != nil {
+		return nil, fmt.Errorf("decode index => %w", err)
+	}
+
+	if encoded.Version != formatVersion {
+		return nil, fmt.Errorf("%w => version %d", ErrUnsupportedFormat, encoded.Version)
+	}
+
+	idx := &Index{
+		items:  encoded.Items,
+		points: make([]point, len(encoded.Items)),
+	}
+
+	for i, item := range encoded.Items {
+		idx.points[i] = toPoint(item.Position)
+	}
+
+	return idx, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
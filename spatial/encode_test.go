@@ -0,0 +1,93 @@
+package spatial
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Index_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		items := fixture.Places()
+
+		// gob decodes empty slices as nil
+		for i := range items {
+			if len(items[i].AlternateNames) == 0 {
+				items[i].AlternateNames = nil
+			}
+
+			if len(items[i].AlternateCountryCodes) == 0 {
+				items[i].AlternateCountryCodes = nil
+			}
+		}
+
+		idx := New(items)
+
+		var buf bytes.Buffer
+
+		n, err := idx.WriteTo(&buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		loaded, err := Load(&buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, idx.All(), loaded.All())
+
+		target := value.Position{Latitude: 51.45, Longitude: -0.11}
+		assert.Equal(t, idx.Nearest(target, 3, Filter{}), loaded.Nearest(target, 3, Filter{}))
+	})
+
+	t.Run("write failed", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := newTestIndex().WriteTo(failingWriter{})
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, n)
+	})
+}
+
+func Test_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid data", func(t *testing.T) {
+		t.Parallel()
+
+		idx, err := Load(bytes.NewReader([]byte("invalid")))
+
+		require.Error(t, err)
+		assert.Nil(t, idx)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		require.NoError(t, gob.NewEncoder(&buf).Encode(encodedIndex{Version: 99, Items: nil}))
+
+		idx, err := Load(&buf)
+
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+		require.EqualError(t, err, ErrUnsupportedFormat.Error()+" => version 99")
+		assert.Nil(t, idx)
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, assert.AnError
+}
@@ -0,0 +1,37 @@
+package spatial
+
+import (
+	"slices"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+)
+
+// Filter restricts query results, zero values do not filter.
+type Filter struct {
+	// FeatureClass only records of the given feature classes are returned
+	FeatureClass []string
+	// FeatureCode only records of the given feature codes are returned
+	FeatureCode []string
+	// Country only records of the given countries are returned
+	Country []value.CountryCode
+	// MinPopulation only records with at least the given population are returned
+	MinPopulation int64
+}
+
+// Match reports whether the record passes the filter.
+func (f Filter) Match(item download.GeoName) bool {
+	if len(f.FeatureClass) > 0 && !slices.Contains(f.FeatureClass, item.FeatureClass) {
+		return false
+	}
+
+	if len(f.FeatureCode) > 0 && !slices.Contains(f.FeatureCode, item.FeatureCode) {
+		return false
+	}
+
+	if len(f.Country) > 0 && !slices.Contains(f.Country, item.CountryCode) {
+		return false
+	}
+
+	return item.Population >= f.MinPopulation
+}
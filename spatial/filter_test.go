@@ -0,0 +1,26 @@
+package spatial
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Filter_Match(t *testing.T) {
+	t.Parallel()
+
+	item := fixture.Place(2652221)
+
+	assert.True(t, Filter{}.Match(item))
+	assert.True(t, Filter{FeatureClass: []string{"A", "P"}}.Match(item))
+	assert.False(t, Filter{FeatureClass: []string{"A"}}.Match(item))
+	assert.True(t, Filter{FeatureCode: []string{"PPLA3"}}.Match(item))
+	assert.False(t, Filter{FeatureCode: []string{"PPLC"}}.Match(item))
+	assert.True(t, Filter{Country: []value.CountryCode{value.CountryCodeUnitedKingdom}}.Match(item))
+	assert.False(t, Filter{Country: []value.CountryCode{value.CountryCodeFrance}}.Match(item))
+	assert.True(t, Filter{MinPopulation: 173314}.Match(item))
+	assert.False(t, Filter{MinPopulation: 173315}.Match(item))
+}
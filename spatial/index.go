@@ -0,0 +1,353 @@
+// Package spatial provides an offline nearest-neighbour index over GeoNames records,
+// an alternative to the findNearby* webservice calls.
+package spatial
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+)
+
+const dimensions = 3
+
+// point is a position on the unit sphere, the chord distance between points grows monotonically
+// with the great-circle distance and has no antimeridian discontinuity.
+type point [dimensions]float64
+
+// Result is a record found by a query.
+type Result struct {
+	download.GeoName
+
+	// Distance in km from the queried position, same as webservice.GeoNameNearby.Distance
+	Distance float64
+}
+
+// Index is an immutable k-d tree over GeoNames records, safe for concurrent use.
+type Index struct {
+	items  []download.GeoName
+	points []point
+}
+
+// Build reads all records from the iterator and builds the index, the first iteration error is returned.
+func Build(items download.Iterator[download.GeoName]) (*Index, error) {
+	res := make([]download.GeoName, 0)
+
+	for item, err := range items {
+		if err != nil {
+			return nil, fmt.Errorf("read item => %w", err)
+		}
+
+		res = append(res, item)
+	}
+
+	return New(res), nil
+}
+
+// New builds the index from the given records, the slice is reordered in place.
+func New(items []download.GeoName) *Index {
+	idx := &Index{
+		items:  items,
+		points: make([]point, len(items)),
+	}
+
+	for i, item := range items {
+		idx.points[i] = toPoint(item.Position)
+	}
+
+	idx.build(0, len(items), 0)
+
+	return idx
+}
+
+// Len returns the number of indexed records.
+func (idx *Index) Len() int {
+	return len(idx.items)
+}
+
+// All returns the indexed records in tree order.
+func (idx *Index) All() []download.GeoName {
+	return idx.items
+}
+
+// Nearest returns up to k records matching the filter ordered by distance from the position.
+func (idx *Index) Nearest(position value.Position, k int, filter Filter) []Result {
+	if k <= 0 || len(idx.items) == 0 {
+		return nil
+	}
+
+	search := &nearestSearch{
+		idx:    idx,
+		target: toPoint(position),
+		k:      k,
+		filter: filter,
+		heap:   make(candidateHeap, 0, k),
+	}
+
+	search.visit(0, len(idx.items), 0)
+
+	candidates := search.heap
+	slices.SortFunc(candidates, compareCandidates)
+
+	return idx.results(position, candidates)
+}
+
+// Radius returns all records matching the filter within the given distance in km ordered by distance.
+func (idx *Index) Radius(position value.Position, radius float64, filter Filter) []Result {
+	if radius < 0 || len(idx.items) == 0 {
+		return nil
+	}
+
+	chord := chordLength(radius)
+	search := &rangeSearch{
+		idx:      idx,
+		target:   toPoint(position),
+		maxChord: chord * chord,
+		filter:   filter,
+		found:    nil,
+	}
+
+	search.visit(0, len(idx.items), 0)
+
+	slices.SortFunc(search.found, compareCandidates)
+
+	return idx.results(position, search.found)
+}
+
+// Within returns all records matching the filter inside the bounding box ordered by distance from its center.
+func (idx *Index) Within(box value.BoundingBox, filter Filter) []Result {
+	center := box.Center()
+
+	// The corners and edge midpoints only bound the distance of boxes narrower than a hemisphere, scan the others.
+	if wideBox(box) {
+		return idx.scan(center, func(item download.GeoName) bool {
+			return filter.Match(item) && box.Contains(item.Position)
+		})
+	}
+
+	radius := 0.0
+
+	for _, edge := range []value.Position{
+		{Latitude: box.North, Longitude: box.West},
+		{Latitude: box.North, Longitude: center.Longitude},
+		{Latitude: box.North, Longitude: box.East},
+		{Latitude: center.Latitude, Longitude: box.West},
+		{Latitude: center.Latitude, Longitude: box.East},
+		{Latitude: box.South, Longitude: box.West},
+		{Latitude: box.South, Longitude: center.Longitude},
+		{Latitude: box.South, Longitude: box.East},
+	} {
+		radius = math.Max(radius, center.Distance(edge))
+	}
+
+	res := idx.Radius(center, radius, filter)
+
+	return slices.DeleteFunc(res, func(item Result) bool {
+		return !box.Contains(item.Position)
+	})
+}
+
+// wideBox reports whether the box spans 180 degrees of longitude or more, or contains a pole.
+func wideBox(box value.BoundingBox) bool {
+	span := box.East - box.West
+	if box.CrossesAntimeridian() {
+		span += 360
+	}
+
+	return span >= 180 || box.North >= 90 || box.South <= -90
+}
+
+// scan returns all records passing match ordered by distance from the position without using the tree.
+func (idx *Index) scan(position value.Position, match func(item download.GeoName) bool) []Result {
+	target := toPoint(position)
+	found := make([]candidate, 0)
+
+	for i, item := range idx.items {
+		if match(item) {
+			found = append(found, candidate{index: i, chord: squaredChord(target, idx.points[i]), id: item.ID})
+		}
+	}
+
+	slices.SortFunc(found, compareCandidates)
+
+	return idx.results(position, found)
+}
+
+func (idx *Index) results(position value.Position, candidates []candidate) []Result {
+	res := make([]Result, 0, len(candidates))
+
+	for _, c := range candidates {
+		item := idx.items[c.index]
+		res = append(res, Result{GeoName: item, Distance: position.Distance(item.Position)})
+	}
+
+	return res
+}
+
+// build arranges the range so that each median element splits its sub-ranges along the axis of its depth.
+func (idx *Index) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+
+	axis := depth % dimensions
+	order := make([]int, hi-lo)
+
+	for i := range order {
+		order[i] = lo + i
+	}
+
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(idx.points[a][axis], idx.points[b][axis])
+	})
+
+	items := make([]download.GeoName, len(order))
+	points := make([]point, len(order))
+
+	for i, j := range order {
+		items[i], points[i] = idx.items[j], idx.points[j]
+	}
+
+	copy(idx.items[lo:hi], items)
+	copy(idx.points[lo:hi], points)
+
+	mid := (lo + hi) / 2
+
+	idx.build(lo, mid, depth+1)
+	idx.build(mid+1, hi, depth+1)
+}
+
+type candidate struct {
+	index int
+	chord float64
+	id    uint64
+}
+
+func compareCandidates(a, b candidate) int {
+	return cmp.Or(cmp.Compare(a.chord, b.chord), cmp.Compare(a.id, b.id))
+}
+
+// candidateHeap is a max-heap keeping the worst candidate on top.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return compareCandidates(h[i], h[j]) > 0 }
+func (h candidateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *candidateHeap) Push(x any) {
+	if c, ok := x.(candidate); ok {
+		*h = append(*h, c)
+	}
+}
+
+func (h *candidateHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+type nearestSearch struct {
+	idx    *Index
+	target point
+	k      int
+	filter Filter
+	heap   candidateHeap
+}
+
+func (s *nearestSearch) visit(lo, hi, depth int) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	axis := depth % dimensions
+
+	if item := s.idx.items[mid]; s.filter.Match(item) {
+		c := candidate{index: mid, chord: squaredChord(s.target, s.idx.points[mid]), id: item.ID}
+
+		switch {
+		case s.heap.Len() < s.k:
+			heap.Push(&s.heap, c)
+		case compareCandidates(c, s.heap[0]) < 0:
+			s.heap[0] = c
+			heap.Fix(&s.heap, 0)
+		}
+	}
+
+	diff := s.target[axis] - s.idx.points[mid][axis]
+
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+
+	s.visit(nearLo, nearHi, depth+1)
+
+	if s.heap.Len() < s.k || diff*diff <= s.heap[0].chord {
+		s.visit(farLo, farHi, depth+1)
+	}
+}
+
+type rangeSearch struct {
+	idx      *Index
+	target   point
+	maxChord float64
+	filter   Filter
+	found    []candidate
+}
+
+func (s *rangeSearch) visit(lo, hi, depth int) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	axis := depth % dimensions
+
+	if chord := squaredChord(s.target, s.idx.points[mid]); chord <= s.maxChord {
+		if item := s.idx.items[mid]; s.filter.Match(item) {
+			s.found = append(s.found, candidate{index: mid, chord: chord, id: item.ID})
+		}
+	}
+
+	diff := s.target[axis] - s.idx.points[mid][axis]
+
+	if diff <= 0 || diff*diff <= s.maxChord {
+		s.visit(lo, mid, depth+1)
+	}
+
+	if diff >= 0 || diff*diff <= s.maxChord {
+		s.visit(mid+1, hi, depth+1)
+	}
+}
+
+func toPoint(p value.Position) point {
+	lat := p.Latitude * math.Pi / 180
+	lng := p.Longitude * math.Pi / 180
+
+	return point{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+func squaredChord(a, b point) float64 {
+	var res float64
+
+	for i := range dimensions {
+		d := a[i] - b[i]
+		res += d * d
+	}
+
+	return res
+}
+
+// chordLength converts a great-circle distance in km into the chord length on the unit sphere.
+func chordLength(distance float64) float64 {
+	angle := math.Min(distance/value.EarthRadius, math.Pi)
+
+	return 2 * math.Sin(angle/2)
+}
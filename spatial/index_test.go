@@ -0,0 +1,206 @@
+package spatial
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/testutil/fixture"
+	"github.com/platx/geonames/value"
+)
+
+func newTestIndex() *Index {
+	return New(fixture.Places())
+}
+
+func Test_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		idx, err := Build(download.FromSlice(fixture.Places()))
+
+		require.NoError(t, err)
+		assert.Equal(t, len(fixture.Places()), idx.Len())
+		assert.ElementsMatch(t, fixture.Places(), idx.All())
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		t.Parallel()
+
+		idx, err := Build(fixture.Failing(fixture.Places(), assert.AnError))
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.EqualError(t, err, "read item => "+assert.AnError.Error())
+		assert.Nil(t, idx)
+	})
+}
+
+func Test_Index_Nearest(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex()
+
+	t.Run("nearest populated places", func(t *testing.T) {
+		t.Parallel()
+
+		actual := idx.Nearest(value.Position{Latitude: 51.45, Longitude: -0.11}, 2, Filter{FeatureClass: []string{"P"}})
+
+		require.Len(t, actual, 2)
+		assert.Equal(t, "London", actual[0].Name)
+		assert.InDelta(t, 6.599, actual[0].Distance, 1e-3)
+		assert.Equal(t, "Croydon", actual[1].Name)
+		assert.InDelta(t, 7.446, actual[1].Distance, 1e-3)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		actual := idx.Nearest(value.Position{Latitude: -15, Longitude: -179.5}, 1, Filter{})
+
+		require.Len(t, actual, 1)
+		assert.Equal(t, "Suva", actual[0].Name)
+	})
+
+	t.Run("population filter", func(t *testing.T) {
+		t.Parallel()
+
+		actual := idx.Nearest(value.Position{Latitude: 51.38, Longitude: -0.1}, 3, Filter{FeatureClass: []string{"P"}, MinPopulation: 1000000})
+
+		assert.Equal(t, []string{"London", "Paris", "München"}, names(actual))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, idx.Nearest(value.Position{}, 0, Filter{}))
+		assert.Empty(t, New(nil).Nearest(value.Position{}, 1, Filter{}))
+	})
+}
+
+func Test_Index_Radius(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex()
+
+	actual := idx.Radius(value.Position{Latitude: 51.45, Longitude: -0.11}, 60, Filter{})
+
+	assert.Equal(t, []string{"London", "Croydon", "River Thames"}, names(actual))
+
+	actual = idx.Radius(value.Position{Latitude: 51.45, Longitude: -0.11}, 60, Filter{Country: []value.CountryCode{value.CountryCodeFrance}})
+
+	assert.Empty(t, actual)
+	assert.Empty(t, idx.Radius(value.Position{}, -1, Filter{}))
+}
+
+func Test_Index_Within(t *testing.T) {
+	t.Parallel()
+
+	idx := newTestIndex()
+
+	actual := idx.Within(value.BoundingBox{East: 1, West: -1, North: 52, South: 51.4}, Filter{})
+
+	assert.Equal(t, []string{"London", "River Thames"}, names(actual))
+
+	actual = idx.Within(value.BoundingBox{East: -170, West: 170, North: -10, South: -20}, Filter{FeatureCode: []string{"PPLC"}})
+
+	assert.ElementsMatch(t, []string{"Suva", "Apia"}, names(actual))
+}
+
+func Test_Index_Within_wide(t *testing.T) {
+	t.Parallel()
+
+	idx := New([]download.GeoName{
+		{ID: 1, Name: "East", Position: value.Position{Latitude: 0, Longitude: 179}},
+		{ID: 2, Name: "Center", Position: value.Position{Latitude: 0, Longitude: 10}},
+		{ID: 3, Name: "Pole", Position: value.Position{Latitude: 89.5, Longitude: -100}},
+	})
+
+	tests := []struct {
+		name  string
+		given value.BoundingBox
+		exp   []string
+	}{
+		{name: "world", given: value.BoundingBox{East: 180, West: -180, North: 10, South: -10}, exp: []string{"Center", "East"}},
+		{name: "wider than 180", given: value.BoundingBox{East: 179.5, West: -100, North: 10, South: -10}, exp: []string{"Center", "East"}},
+		{name: "crossing wider than 180", given: value.BoundingBox{East: 20, West: 100, North: 10, South: -10}, exp: []string{"Center", "East"}},
+		{name: "pole", given: value.BoundingBox{East: -90, West: -110, North: 90, South: 80}, exp: []string{"Pole"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ElementsMatch(t, tt.exp, names(idx.Within(tt.given, Filter{})))
+		})
+	}
+}
+
+func Test_Index_BruteForce(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewPCG(1, 2))
+	items := make([]download.GeoName, 2000)
+
+	for i := range items {
+		items[i] = download.GeoName{
+			ID:           uint64(i + 1),
+			FeatureClass: []string{"P", "A", "H"}[rnd.IntN(3)],
+			Population:   rnd.Int64N(100000),
+			Position: value.Position{
+				Latitude:  rnd.Float64()*180 - 90,
+				Longitude: rnd.Float64()*360 - 180,
+			},
+		}
+	}
+
+	idx := New(slices.Clone(items))
+	filter := Filter{FeatureClass: []string{"P"}, MinPopulation: 5000}
+
+	for range 50 {
+		target := value.Position{Latitude: rnd.Float64()*180 - 90, Longitude: rnd.Float64()*360 - 180}
+
+		expected := make([]Result, 0)
+
+		for _, item := range items {
+			if filter.Match(item) {
+				expected = append(expected, Result{GeoName: item, Distance: target.Distance(item.Position)})
+			}
+		}
+
+		slices.SortFunc(expected, func(a, b Result) int {
+			return cmp.Or(cmp.Compare(a.Distance, b.Distance), cmp.Compare(a.ID, b.ID))
+		})
+
+		assert.Equal(t, ids(expected[:7]), ids(idx.Nearest(target, 7, filter)))
+
+		inRadius := slices.DeleteFunc(slices.Clone(expected), func(item Result) bool { return item.Distance > 1500 })
+		assert.Equal(t, ids(inRadius), ids(idx.Radius(target, 1500, filter)))
+	}
+}
+
+func names(items []Result) []string {
+	res := make([]string, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.Name)
+	}
+
+	return res
+}
+
+func ids(items []Result) []uint64 {
+	res := make([]uint64, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.ID)
+	}
+
+	return res
+}
@@ -0,0 +1,341 @@
+package testutil
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	downloaddata "github.com/platx/geonames/download/testdata"
+	"github.com/platx/geonames/value"
+	webservicedata "github.com/platx/geonames/webservice/testdata"
+)
+
+// webserviceFixtures maps the webservice endpoints to their fixture in webservice/testdata.
+var webserviceFixtures = map[string]string{
+	"/addressJSON":               "address_single.json",
+	"/astergdemJSON":             "astergdem.json",
+	"/childrenJSON":              "geonames.json",
+	"/containsJSON":              "geonames.json",
+	"/countryCodeJSON":           "country_nearby.json",
+	"/countryInfoJSON":           "country_detailed.json",
+	"/countrySubdivisionJSON":    "country_subdivision.json",
+	"/earthquakesJSON":           "earthquakes.json",
+	"/findNearbyJSON":            "geonames_nearby.json",
+	"/findNearbyPlaceNameJSON":   "geonames_nearby.json",
+	"/findNearbyPostalCodesJSON": "postalcodes_nearby.json",
+	"/findNearByWeatherJSON":     "weather_nearby.json",
+	"/findNearbyWikipediaJSON":   "wikipedia_nearby.json",
+	"/geoCodeAddressJSON":        "address_single.json",
+	"/getJSON":                   "geoname_detailed.json",
+	"/gtopo30JSON":               "gtopo30.json",
+	"/hierarchyJSON":             "geonames.json",
+	"/neighboursJSON":            "geonames.json",
+	"/oceanJSON":                 "ocean.json",
+	"/postalCodeLookupJSON":      "postalcodes.json",
+	"/postalCodeSearchJSON":      "postalcodes.json",
+	"/searchJSON":                "geonames.json",
+	"/siblingsJSON":              "geonames.json",
+	"/srtm1JSON":                 "srtm1.json",
+	"/srtm3JSON":                 "srtm3.json",
+	"/streetNameLookupJSON":      "address.json",
+	"/timezoneJSON":              "timezone.json",
+	"/weatherJSON":               "weather.json",
+	"/weatherIcaoJSON":           "weather_nearby.json",
+	"/wikipediaBoundingBoxJSON":  "wikipedia.json",
+	"/wikipediaSearchJSON":       "wikipedia.json",
+}
+
+// webserviceTextFixtures maps the plain text endpoints to their fixture in webservice/testdata, the elevations
+// of the positions lats=50.01,51.01 and lngs=10.2,11.2 one per line.
+var webserviceTextFixtures = map[string]string{
+	"/astergdem": "astergdem_points.txt",
+	"/gtopo30":   "gtopo30_points.txt",
+	"/srtm1":     "srtm1_points.txt",
+	"/srtm3":     "srtm3_points.txt",
+}
+
+// fixtureModTime is the last modification time of the fixture files, fixed for stable conditional requests.
+var fixtureModTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// FakeResponse is a canned response of FakeServer.
+type FakeResponse struct {
+	// Status code, default is 200, ignored for files
+	Status int
+	// Header added to the response
+	Header http.Header
+	// Body of the response
+	Body []byte
+	// Delay before the response is written, the request context cancels the wait
+	Delay time.Duration
+	// Truncate writes only the first n bytes of the body while announcing its full length, 0 writes all
+	Truncate int
+	// File serves the body like the download server, honouring Range and conditional requests
+	File bool
+	// ETag of the file, conditional requests are only honoured when set
+	ETag string
+	// ModTime of the file
+	ModTime time.Time
+	// IgnoreRange serves the whole file for Range requests, like servers without range support
+	IgnoreRange bool
+}
+
+// JSONResponse returns a 200 response with the JSON body.
+func JSONResponse(body []byte) FakeResponse {
+	return FakeResponse{
+		Status:      http.StatusOK,
+		Header:      http.Header{"Content-Type": {"application/json;charset=UTF-8"}},
+		Body:        body,
+		Delay:       0,
+		Truncate:    0,
+		File:        false,
+		ETag:        "",
+		ModTime:     time.Time{},
+		IgnoreRange: false,
+	}
+}
+
+// TextResponse returns a 200 response with the plain text body.
+func TextResponse(body []byte) FakeResponse {
+	res := JSONResponse(body)
+	res.Header.Set("Content-Type", "text/plain;charset=UTF-8")
+
+	return res
+}
+
+// FileResponse returns a download file response, the ETag is derived from the content.
+func FileResponse(content []byte) FakeResponse {
+	sum := sha256.Sum256(content)
+
+	return FakeResponse{
+		Status:      http.StatusOK,
+		Header:      http.Header{},
+		Body:        content,
+		Delay:       0,
+		Truncate:    0,
+		File:        true,
+		ETag:        `"` + hex.EncodeToString(sum[:8]) + `"`,
+		ModTime:     fixtureModTime,
+		IgnoreRange: false,
+	}
+}
+
+// ErrorResponse returns the status response of a webservice error. The status code is 200 like the one
+// of the webservice, set Status to return another one.
+func ErrorResponse(code value.ErrCode, message string) FakeResponse {
+	body, err := json.Marshal(map[string]any{"status": map[string]any{"message": message, "value": code}})
+	if err != nil {
+		panic(err)
+	}
+
+	return JSONResponse(body)
+}
+
+// RateLimitResponse returns a 429 response with the Retry-After header and the hourly limit status.
+func RateLimitResponse(retryAfter time.Duration) FakeResponse {
+	res := ErrorResponse(
+		value.ErrCodeHourlyLimitExceeded,
+		"the hourly limit of 1000 credits for demo has been exceeded. Please throttle your requests.",
+	)
+	res.Status = http.StatusTooManyRequests
+	res.Header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+
+	return res
+}
+
+// FakeServer is an httptest server serving canned webservice and download responses by path,
+// usable via the WithBaseURL options of both clients. It is safe for concurrent use.
+type FakeServer struct {
+	server    *httptest.Server
+	mu        sync.Mutex
+	responses map[string]FakeResponse
+	queued    map[string][]FakeResponse
+	requests  map[string][]*http.Request
+}
+
+type FakeOption func(*FakeServer)
+
+// WithWebserviceFixtures serves the fixtures of webservice/testdata on all webservice endpoints.
+func WithWebserviceFixtures() FakeOption {
+	return func(s *FakeServer) {
+		for endpoint, name := range webserviceFixtures {
+			s.responses[endpoint] = JSONResponse(mustRead(webservicedata.FS, name))
+		}
+
+		for endpoint, name := range webserviceTextFixtures {
+			s.responses[endpoint] = TextResponse(mustRead(webservicedata.FS, name))
+		}
+	}
+}
+
+// WithDownloadFixtures serves the files of download/testdata under their names, e.g. `/countryInfo.txt`.
+func WithDownloadFixtures() FakeOption {
+	return func(s *FakeServer) {
+		s.HandleFS(downloaddata.FS)
+	}
+}
+
+// NewFakeServer starts a fake server which is closed when the test finishes. Paths without response
+// are answered with 404.
+func NewFakeServer(tb testing.TB, opts ...FakeOption) *FakeServer {
+	tb.Helper()
+
+	res := &FakeServer{
+		server:    nil,
+		mu:        sync.Mutex{},
+		responses: make(map[string]FakeResponse),
+		queued:    make(map[string][]FakeResponse),
+		requests:  make(map[string][]*http.Request),
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	res.server = httptest.NewServer(http.HandlerFunc(res.serveHTTP))
+	tb.Cleanup(res.server.Close)
+
+	return res
+}
+
+// URL returns the base URL of the server.
+func (s *FakeServer) URL() string {
+	return s.server.URL
+}
+
+// Handle sets the response of the path served whenever no queued response is left.
+func (s *FakeServer) Handle(path string, res FakeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = res
+}
+
+// HandleFS serves all files of the file system as download files under their names.
+func (s *FakeServer) HandleFS(fsys fs.FS) {
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		s.Handle("/"+name, FileResponse(mustRead(fsys, name)))
+
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Enqueue adds responses of the path which are served once each in the given order before the one set by
+// Handle, e.g. to fail the first requests of a retry.
+func (s *FakeServer) Enqueue(path string, res ...FakeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queued[path] = append(s.queued[path], res...)
+}
+
+// Requests returns the requests received on the path.
+func (s *FakeServer) Requests(path string) []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*http.Request(nil), s.requests[path]...)
+}
+
+func (s *FakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.next(r)
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if res.Delay > 0 {
+		select {
+		case <-time.After(res.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for key, values := range res.Header {
+		w.Header()[key] = values
+	}
+
+	if res.Truncate > 0 && res.Truncate < len(res.Body) {
+		// the server closes the connection since less than the announced length is written
+		w.Header().Set("Content-Length", strconv.Itoa(len(res.Body)))
+		w = &truncatingWriter{ResponseWriter: w, remaining: res.Truncate}
+	}
+
+	if res.File && !res.IgnoreRange {
+		if res.ETag != "" {
+			w.Header().Set("ETag", res.ETag)
+		}
+
+		http.ServeContent(w, r, path.Base(r.URL.Path), res.ModTime, bytes.NewReader(res.Body))
+
+		return
+	}
+
+	w.WriteHeader(cmp.Or(res.Status, http.StatusOK))
+	_, _ = w.Write(res.Body)
+}
+
+// next records the request and returns its response, the first queued one if any.
+func (s *FakeServer) next(r *http.Request) (FakeResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[r.URL.Path] = append(s.requests[r.URL.Path], r.Clone(context.Background()))
+
+	if queued := s.queued[r.URL.Path]; len(queued) > 0 {
+		s.queued[r.URL.Path] = queued[1:]
+
+		return queued[0], true
+	}
+
+	res, ok := s.responses[r.URL.Path]
+
+	return res, ok
+}
+
+// truncatingWriter discards everything written after the remaining bytes.
+type truncatingWriter struct {
+	http.ResponseWriter
+
+	remaining int
+}
+
+func (w *truncatingWriter) Write(p []byte) (int, error) {
+	n := min(len(p), w.remaining)
+	w.remaining -= n
+
+	if _, err := w.ResponseWriter.Write(p[:n]); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func mustRead(fsys fs.FS, name string) []byte {
+	res, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		panic(fmt.Errorf("read fixture %s => %w", name, err))
+	}
+
+	return res
+}
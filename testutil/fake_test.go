@@ -0,0 +1,268 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+	"github.com/platx/geonames/webservice"
+)
+
+func get(t *testing.T, url string, header http.Header) (*http.Response, []byte, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	req.Header = header
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(res.Body)
+
+	return res, body, err
+}
+
+func Test_FakeServer_webservice(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeServer(t, WithWebserviceFixtures())
+	client := webservice.NewClient("demo", webservice.WithBaseURL(fake.URL()))
+
+	actual, err := client.Search(context.Background(), webservice.SearchRequest{Name: "test"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, actual)
+
+	requests := fake.Requests("/searchJSON")
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, "test", requests[0].URL.Query().Get("name"))
+	assert.Equal(t, "demo", requests[0].URL.Query().Get("username"))
+
+	elevations, err := client.SRTM3Batch(context.Background(), []value.Position{
+		{Latitude: 50.01, Longitude: 10.2},
+		{Latitude: 51.01, Longitude: 11.2},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []value.Elevation{{Meters: 206, Valid: true}, {Meters: 323, Valid: true}}, elevations)
+}
+
+func Test_FakeServer_download(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeServer(t, WithDownloadFixtures())
+	client := download.NewClient(download.WithBaseURL(fake.URL()))
+
+	items, err := client.TimeZones(context.Background())
+	require.NoError(t, err)
+
+	// the fixtures end with invalid rows
+	for item, err := range items {
+		require.NoError(t, err)
+		assert.Equal(t, value.CountryCodeUnitedStates, item.CountryCode)
+
+		break
+	}
+
+	assert.Len(t, fake.Requests("/timeZones.txt"), 1)
+}
+
+func Test_FakeServer_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeServer(t)
+	fake.Handle("/getJSON", JSONResponse([]byte(`{"geonameId":1}`)))
+	fake.Enqueue("/getJSON", RateLimitResponse(time.Minute), ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+	res, body, err := get(t, fake.URL()+"/getJSON", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "60", res.Header.Get("Retry-After"))
+	assert.JSONEq(t, `{"status":{"message":"the hourly limit of 1000 credits for demo has been exceeded. `+
+		`Please throttle your requests.","value":19}}`, string(body))
+
+	res, body, err = get(t, fake.URL()+"/getJSON", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.JSONEq(t, `{"status":{"message":"overloaded","value":22}}`, string(body))
+
+	for range 2 {
+		res, body, err = get(t, fake.URL()+"/getJSON", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{"geonameId":1}`, string(body))
+	}
+
+	assert.Len(t, fake.Requests("/getJSON"), 4)
+}
+
+func Test_FakeServer_errors(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeServer(t)
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		res, _, err := get(t, fake.URL()+"/missing.zip", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+
+		res := ErrorResponse(value.ErrCodeAuthorization, "user does not exist.")
+		res.Status = http.StatusUnauthorized
+		fake.Handle("/timezoneJSON", res)
+
+		client := webservice.NewClient("demo", webservice.WithBaseURL(fake.URL()))
+
+		_, err := client.Timezone(context.Background(), webservice.TimezoneRequest{})
+
+		var respErr *webservice.ResponseError
+
+		require.ErrorAs(t, err, &respErr)
+		assert.True(t, respErr.MatchCode(value.ErrCodeAuthorization))
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		t.Parallel()
+
+		res := JSONResponse([]byte(`{"geonames":[]}`))
+		res.Delay = time.Minute
+		fake.Handle("/childrenJSON", res)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		client := webservice.NewClient("demo", webservice.WithBaseURL(fake.URL()))
+
+		_, err := client.Children(ctx, webservice.ChildrenRequest{ID: 1})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		res := JSONResponse([]byte(`{"geonames":[{"geonameId":1}]}`))
+		res.Truncate = 10
+		fake.Handle("/siblingsJSON", res)
+
+		_, body, err := get(t, fake.URL()+"/siblingsJSON", nil)
+
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, `{"geonames`, string(body))
+	})
+}
+
+func Test_FakeServer_file(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("0123456789abcdef")
+
+	fake := NewFakeServer(t)
+	fake.Handle("/file.txt", FileResponse(content))
+
+	ignoring := FileResponse(content)
+	ignoring.IgnoreRange = true
+	fake.Handle("/ignoring.txt", ignoring)
+
+	etag := FileResponse(content).ETag
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		status int
+		body   string
+	}{
+		{name: "whole", path: "/file.txt", status: http.StatusOK, body: string(content)},
+		{
+			name:   "range",
+			path:   "/file.txt",
+			header: http.Header{"Range": {"bytes=10-"}},
+			status: http.StatusPartialContent,
+			body:   "abcdef",
+		},
+		{
+			name:   "not modified",
+			path:   "/file.txt",
+			header: http.Header{"If-None-Match": {etag}},
+			status: http.StatusNotModified,
+		},
+		{
+			name:   "range of changed file",
+			path:   "/file.txt",
+			header: http.Header{"Range": {"bytes=10-"}, "If-Range": {`"changed"`}},
+			status: http.StatusOK,
+			body:   string(content),
+		},
+		{
+			name:   "range ignored",
+			path:   "/ignoring.txt",
+			header: http.Header{"Range": {"bytes=10-"}},
+			status: http.StatusOK,
+			body:   string(content),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, body, err := get(t, fake.URL()+tt.path, tt.header)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, res.StatusCode)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+
+	t.Run("etag", func(t *testing.T) {
+		t.Parallel()
+
+		res, _, err := get(t, fake.URL()+"/file.txt", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, etag, res.Header.Get("ETag"))
+	})
+}
+
+func Test_ErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	res := ErrorResponse(value.ErrCodeNoResultFound, "no result")
+
+	var actual struct {
+		Status struct {
+			Message string        `json:"message"`
+			Value   value.ErrCode `json:"value"`
+		} `json:"status"`
+	}
+
+	require.NoError(t, json.Unmarshal(res.Body, &actual))
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, "no result", actual.Status.Message)
+	assert.Equal(t, value.ErrCode(value.ErrCodeNoResultFound), actual.Status.Value)
+}
@@ -0,0 +1,108 @@
+// Package fixture provides a small gazetteer in the GeoNames dump format shared by the tests of the offline indexes.
+// Every function parses the embedded files again, so the callers are free to modify the returned records.
+package fixture
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"github.com/platx/geonames/download"
+)
+
+//go:embed testdata/*.txt
+var files embed.FS
+
+// Places returns the records of allCountries.txt, places of all feature classes around the world.
+func Places() []download.GeoName {
+	return parse[download.GeoName]("allCountries.txt")
+}
+
+// Place returns the record of Places with the given ID.
+func Place(id uint64) download.GeoName {
+	for _, item := range Places() {
+		if item.ID == id {
+			return item
+		}
+	}
+
+	panic(fmt.Sprintf("place %d is not in the fixture", id))
+}
+
+// AlternateNames returns the records of alternateNamesV2.txt, including one of an unknown GeoNameID.
+func AlternateNames() []download.AlternateName {
+	return parse[download.AlternateName]("alternateNamesV2.txt")
+}
+
+// AdminDivisionsFirst returns the records of admin1CodesASCII.txt.
+func AdminDivisionsFirst() []download.AdminDivision {
+	return parse[download.AdminDivision]("admin1CodesASCII.txt")
+}
+
+// AdminDivisionsSecond returns the records of admin2Codes.txt.
+func AdminDivisionsSecond() []download.AdminDivision {
+	return parse[download.AdminDivision]("admin2Codes.txt")
+}
+
+// Countries returns the records of countryInfo.txt.
+func Countries() []download.Country {
+	return parse[download.Country]("countryInfo.txt")
+}
+
+// Hierarchy returns the records of hierarchy.txt.
+func Hierarchy() []download.HierarchyItem {
+	return parse[download.HierarchyItem]("hierarchy.txt")
+}
+
+// Shapes returns the records of shapes_all_low.txt, rough rectangles of France, Germany and Belgium.
+func Shapes() []download.Shape {
+	return parse[download.Shape]("shapes_all_low.txt")
+}
+
+// Features returns the records of featureCodes_en.txt.
+func Features() []download.Feature {
+	return parse[download.Feature]("featureCodes_en.txt")
+}
+
+// Failing returns an iterator over the items that fails with err after the last one.
+func Failing[T any](items []T, err error) download.Iterator[T] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+
+		var empty T
+
+		yield(empty, err)
+	}
+}
+
+func parse[T any, P interface {
+	*T
+	UnmarshalRow(row []string) error
+}](name string) []T {
+	data, err := files.ReadFile("testdata/" + name)
+	if err != nil {
+		panic(err)
+	}
+
+	var res []T
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var item T
+
+		if err = P(&item).UnmarshalRow(strings.Split(line, "\t")); err != nil {
+			panic(fmt.Sprintf("parse %s line %d => %v", name, i+1, err))
+		}
+
+		res = append(res, item)
+	}
+
+	return res
+}
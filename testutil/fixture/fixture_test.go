@@ -0,0 +1,74 @@
+package fixture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/download"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, Places(), 38)
+	assert.Len(t, AlternateNames(), 17)
+	assert.Len(t, AdminDivisionsFirst(), 21)
+	assert.Len(t, AdminDivisionsSecond(), 4)
+	assert.Len(t, Countries(), 16)
+	assert.Len(t, Hierarchy(), 1)
+	assert.Len(t, Shapes(), 3)
+	assert.Len(t, Features(), 11)
+}
+
+func Test_Place(t *testing.T) {
+	t.Parallel()
+
+	actual := Place(2988507)
+
+	assert.Equal(t, "Paris", actual.Name)
+	assert.Equal(t, value.CountryCodeFrance, actual.CountryCode)
+	assert.Equal(t, value.AdminCode{First: "11", Second: "75", Third: "751", Fourth: "75056"}, actual.AdminCode)
+
+	actual.Name = "Lutetia"
+
+	assert.Equal(t, "Paris", Place(2988507).Name)
+	assert.Panics(t, func() { Place(1) })
+}
+
+func Test_Failing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		items []int
+		errs  []error
+	)
+
+	for item, err := range Failing([]int{1, 2}, assert.AnError) {
+		items = append(items, item)
+		errs = append(errs, err)
+	}
+
+	assert.Equal(t, []int{1, 2, 0}, items)
+	assert.Equal(t, []error{nil, nil, assert.AnError}, errs)
+
+	for range Failing([]int{1, 2}, assert.AnError) {
+		break
+	}
+}
+
+func Test_FromSlice(t *testing.T) {
+	t.Parallel()
+
+	var actual []download.Country
+
+	for item, err := range download.FromSlice(Countries()) {
+		require.NoError(t, err)
+
+		actual = append(actual, item)
+	}
+
+	assert.Equal(t, Countries(), actual)
+}
@@ -0,0 +1,31 @@
+package translit
+
+// arabic is a consonantal romanization for Arabic and Persian, short vowels are not written and harakat are dropped.
+// Waw and yeh are read as vowels inside words.
+var arabic = map[rune]string{
+	'ا': "a", 'أ': "a", 'إ': "i", 'آ': "a", 'ٱ': "a", 'ء': "", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j",
+	'ح': "h", 'خ': "kh", 'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "d",
+	'ط': "t", 'ظ': "z", 'ع': "", 'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n",
+	'ه': "h", 'ة': "a", 'ى': "a", 'ئ': "", 'ؤ': "",
+	// Persian and Urdu
+	'پ': "p", 'چ': "ch", 'ژ': "zh", 'گ': "g", 'ک': "k",
+}
+
+// arabicVowels are consonants at the start of a word and long vowels inside it.
+var arabicVowels = map[rune][2]string{
+	'و': {"w", "u"},
+	'ي': {"y", "i"},
+	'ی': {"y", "i"},
+}
+
+func arabicRule(word []rune, i int) (string, int) {
+	if vowel, ok := arabicVowels[word[i]]; ok {
+		if initial(word, i) {
+			return vowel[0], 1
+		}
+
+		return vowel[1], 1
+	}
+
+	return single(arabic)(word, i)
+}
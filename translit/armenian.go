@@ -0,0 +1,28 @@
+package translit
+
+// armenian follows BGN/PCGN, aspirates are marked with an apostrophe which is dropped in search keys.
+var armenian = map[rune]string{
+	'ա': "a", 'բ': "b", 'գ': "g", 'դ': "d", 'ե': "e", 'զ': "z", 'է': "e", 'ը': "y", 'թ': "t'", 'ժ': "zh",
+	'ի': "i", 'լ': "l", 'խ': "kh", 'ծ': "ts", 'կ': "k", 'հ': "h", 'ձ': "dz", 'ղ': "gh", 'ճ': "ch", 'մ': "m",
+	'յ': "y", 'ն': "n", 'շ': "sh", 'ո': "o", 'չ': "ch'", 'պ': "p", 'ջ': "j", 'ռ': "r", 'ս': "s", 'վ': "v",
+	'տ': "t", 'ր': "r", 'ց': "ts'", 'ւ': "v", 'փ': "p'", 'ք': "k'", 'օ': "o", 'ֆ': "f", 'և': "ev",
+}
+
+// armenianInitial letters are romanized differently at the start of a word.
+var armenianInitial = map[rune]string{
+	'ե': "ye",
+	'ո': "vo",
+	'և': "yev",
+}
+
+func armenianRule(word []rune, i int) (string, int) {
+	if word[i] == 'ո' && i+1 < len(word) && word[i+1] == 'ւ' {
+		return "u", 2
+	}
+
+	if res, ok := armenianInitial[word[i]]; ok && initial(word, i) {
+		return res, 1
+	}
+
+	return single(armenian)(word, i)
+}
@@ -0,0 +1,50 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillic follows BGN/PCGN for Russian, extended with Ukrainian, Belarusian, Serbian, Macedonian and Kazakh letters.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+	// Ukrainian and Belarusian
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "w",
+	// Serbian and Macedonian
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+	// Kazakh
+	'ә': "a", 'ғ': "gh", 'қ': "q", 'ң': "ng", 'ө': "o", 'ұ': "u", 'ү': "u", 'һ': "h",
+}
+
+// cyrillicIotated are romanized with a leading y at the start of a word and after vowels, й, ъ and ь. The diaeresis
+// of ё is dropped like in GeoName.NameASCII.
+var cyrillicIotated = map[rune]string{
+	'е': "ye",
+	'ё': "ye",
+}
+
+// cyrillicIotating are the runes after which cyrillicIotated get their leading y.
+const cyrillicIotating = "аеёиоуыэюяйъь"
+
+func cyrillicRule(word []rune, i int) (string, int) {
+	if res, ok := cyrillicIotated[word[i]]; ok && iotated(word, i) {
+		return res, 1
+	}
+
+	return single(cyrillic)(word, i)
+}
+
+// iotated reports whether the rune at position i starts the word or follows one of cyrillicIotating, ignoring
+// combining marks.
+func iotated(word []rune, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if !unicode.Is(unicode.Mn, word[j]) {
+			return strings.ContainsRune(cyrillicIotating, word[j])
+		}
+	}
+
+	return true
+}
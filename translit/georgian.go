@@ -0,0 +1,12 @@
+package translit
+
+// georgian follows the national system of 2002, ejectives are marked with an apostrophe
+// which is dropped in search keys.
+var georgian = map[rune]string{
+	'ა': "a", 'ბ': "b", 'გ': "g", 'დ': "d", 'ე': "e", 'ვ': "v", 'ზ': "z", 'თ': "t", 'ი': "i", 'კ': "k'",
+	'ლ': "l", 'მ': "m", 'ნ': "n", 'ო': "o", 'პ': "p'", 'ჟ': "zh", 'რ': "r", 'ს': "s", 'ტ': "t'", 'უ': "u",
+	'ფ': "p", 'ქ': "k", 'ღ': "gh", 'ყ': "q'", 'შ': "sh", 'ჩ': "ch", 'ც': "ts", 'ძ': "dz", 'წ': "ts'",
+	'ჭ': "ch'", 'ხ': "kh", 'ჯ': "j", 'ჰ': "h",
+}
+
+var georgianRule = single(georgian)
@@ -0,0 +1,35 @@
+package translit
+
+// greek follows ELOT 743, accents and diaereses are dropped.
+var greek = map[rune]string{
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i",
+	'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y", 'ϋ': "y",
+	'ΰ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// greekDigraphs are romanized as a whole, the word initial ones only at the start of a word.
+var (
+	greekDigraphs = map[string]string{
+		"ου": "ou", "ού": "ou", "αυ": "av", "αύ": "av", "ευ": "ev", "εύ": "ev", "γγ": "ng", "γκ": "gk", "γχ": "nch",
+	}
+	greekInitialDigraphs = map[string]string{
+		"μπ": "b", "ντ": "d", "γκ": "g",
+	}
+)
+
+func greekRule(word []rune, i int) (string, int) {
+	if i+1 < len(word) {
+		pair := string(word[i : i+2])
+
+		if res, ok := greekInitialDigraphs[pair]; ok && initial(word, i) {
+			return res, 2
+		}
+
+		if res, ok := greekDigraphs[pair]; ok {
+			return res, 2
+		}
+	}
+
+	return single(greek)(word, i)
+}
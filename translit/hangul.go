@@ -0,0 +1,74 @@
+package translit
+
+// Hangul syllables are romanized with the Revised Romanization of Korean by decomposing them into jamo.
+const (
+	hangulBase         = 0xAC00
+	hangulLast         = 0xD7A3
+	hangulMedials      = 21
+	hangulFinals       = 28
+	hangulSilent       = 11 // index of the silent initial ㅇ
+	hangulRieulInitial = 5  // index of the initial ㄹ
+	hangulRieulFinal   = 8  // index of the final ㄹ
+)
+
+var (
+	hangulInitials = [...]string{
+		"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h",
+	}
+	hangulVowels = [...]string{
+		"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi",
+		"yu", "eu", "ui", "i",
+	}
+	// hangulFinalsPlain finals before a consonant or at the end of a word.
+	hangulFinalsPlain = [...]string{
+		"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t",
+		"t", "ng", "t", "t", "k", "t", "p", "t",
+	}
+	// hangulFinalsLinked finals followed by a syllable starting with a vowel, they are carried over.
+	hangulFinalsLinked = [...]string{
+		"", "g", "kk", "ks", "n", "nj", "n", "d", "r", "lg", "lm", "lb", "ls", "lt", "lp", "r", "m", "b", "ps",
+		"s", "ss", "ng", "j", "ch", "k", "t", "p", "",
+	}
+)
+
+func hangulRule(word []rune, i int) (string, int) {
+	initialIdx, vowel, final, ok := decomposeHangul(word[i])
+	if !ok {
+		return string(word[i]), 1
+	}
+
+	res := hangulInitials[initialIdx]
+
+	// ㄹ after a final ㄹ is romanized as l
+	if initialIdx == hangulRieulInitial && i > 0 {
+		if _, _, previousFinal, ok := decomposeHangul(word[i-1]); ok && previousFinal == hangulRieulFinal {
+			res = "l"
+		}
+	}
+
+	res += hangulVowels[vowel]
+
+	if final == 0 {
+		return res, 1
+	}
+
+	if i+1 < len(word) {
+		if next, _, _, ok := decomposeHangul(word[i+1]); ok && next == hangulSilent {
+			return res + hangulFinalsLinked[final], 1
+		}
+	}
+
+	return res + hangulFinalsPlain[final], 1
+}
+
+// decomposeHangul returns the initial, vowel and final jamo indexes of a precomposed syllable.
+func decomposeHangul(r rune) (int, int, int, bool) {
+	if r < hangulBase || r > hangulLast {
+		return 0, 0, 0, false
+	}
+
+	offset := int(r - hangulBase)
+
+	return offset / (hangulMedials * hangulFinals), offset % (hangulMedials * hangulFinals) / hangulFinals,
+		offset % hangulFinals, true
+}
@@ -0,0 +1,28 @@
+package translit
+
+// hebrew is a consonantal romanization, vowel points are dropped. Alef starts a word with a vowel,
+// vav and yod are read as vowels inside words.
+var hebrew = map[rune]string{
+	'א': "", 'ב': "v", 'ג': "g", 'ד': "d", 'ה': "h", 'ז': "z", 'ח': "kh", 'ט': "t", 'כ': "k", 'ך': "kh",
+	'ל': "l", 'מ': "m", 'ם': "m", 'נ': "n", 'ן': "n", 'ס': "s", 'ע': "", 'פ': "p", 'ף': "f", 'צ': "ts",
+	'ץ': "ts", 'ק': "k", 'ר': "r", 'ש': "sh", 'ת': "t",
+}
+
+// hebrewVowels are romanized depending on the position in the word.
+var hebrewVowels = map[rune][2]string{
+	'א': {"a", ""},
+	'ו': {"v", "o"},
+	'י': {"y", "i"},
+}
+
+func hebrewRule(word []rune, i int) (string, int) {
+	if vowel, ok := hebrewVowels[word[i]]; ok {
+		if initial(word, i) {
+			return vowel[0], 1
+		}
+
+		return vowel[1], 1
+	}
+
+	return single(hebrew)(word, i)
+}
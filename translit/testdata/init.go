@@ -0,0 +1,6 @@
+package testdata
+
+import "embed"
+
+//go:embed *.txt
+var FS embed.FS
@@ -0,0 +1,223 @@
+// Package translit romanizes place names written in Cyrillic, Greek, Arabic, Hebrew, Georgian, Armenian
+// and Hangul, and folds latin diacritics like GeoName.NameASCII.
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Script is a writing system with romanization rules.
+type Script uint8
+
+const (
+	ScriptUnknown Script = iota
+	ScriptLatin
+	ScriptCyrillic
+	ScriptGreek
+	ScriptArabic
+	ScriptHebrew
+	ScriptGeorgian
+	ScriptArmenian
+	ScriptHangul
+)
+
+// rule romanizes the rune at position i of the word, returning the lower case romanization and the number
+// of consumed runes, which is more than one for digraphs.
+type rule func(word []rune, i int) (string, int)
+
+var (
+	scriptNames = [...]string{
+		"Unknown", "Latin", "Cyrillic", "Greek", "Arabic", "Hebrew", "Georgian", "Armenian", "Hangul",
+	}
+	scriptRules = map[Script]rule{
+		ScriptCyrillic: cyrillicRule,
+		ScriptGreek:    greekRule,
+		ScriptArabic:   arabicRule,
+		ScriptHebrew:   hebrewRule,
+		ScriptGeorgian: georgianRule,
+		ScriptArmenian: armenianRule,
+		ScriptHangul:   hangulRule,
+	}
+)
+
+// String returns the name of the script.
+func (s Script) String() string {
+	if int(s) < len(scriptNames) {
+		return scriptNames[s]
+	}
+
+	return scriptNames[ScriptUnknown]
+}
+
+// ScriptOf returns the script of the rune, ScriptUnknown for non letters and unsupported scripts.
+func ScriptOf(r rune) Script {
+	switch {
+	case r < unicode.MaxASCII:
+		if unicode.IsLetter(r) {
+			return ScriptLatin
+		}
+
+		return ScriptUnknown
+	case unicode.Is(unicode.Latin, r):
+		return ScriptLatin
+	case unicode.Is(unicode.Cyrillic, r):
+		return ScriptCyrillic
+	case unicode.Is(unicode.Greek, r):
+		return ScriptGreek
+	case unicode.Is(unicode.Arabic, r):
+		return ScriptArabic
+	case unicode.Is(unicode.Hebrew, r):
+		return ScriptHebrew
+	case unicode.Is(unicode.Georgian, r):
+		return ScriptGeorgian
+	case unicode.Is(unicode.Armenian, r):
+		return ScriptArmenian
+	case unicode.Is(unicode.Hangul, r):
+		return ScriptHangul
+	default:
+		return ScriptUnknown
+	}
+}
+
+// Detect returns the script most letters of the text are written in.
+func Detect(text string) Script {
+	var counts [len(scriptNames)]int
+
+	for _, r := range text {
+		counts[ScriptOf(r)]++
+	}
+
+	res := ScriptUnknown
+
+	for script := ScriptLatin; int(script) < len(counts); script++ {
+		if counts[script] > counts[res] || (res == ScriptUnknown && counts[script] > 0) {
+			res = script
+		}
+	}
+
+	return res
+}
+
+// Romanize returns the display romanization of the text. Case, spaces and punctuation are kept,
+// letters of unsupported scripts are returned as is.
+func Romanize(text string) string {
+	var res strings.Builder
+
+	runes := []rune(text)
+
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && isWordRune(runes[end]) == isWordRune(runes[start]) {
+			end++
+		}
+
+		if isWordRune(runes[start]) {
+			romanizeWord(&res, runes[start:end])
+		} else {
+			res.WriteString(string(runes[start:end]))
+		}
+
+		start = end
+	}
+
+	return res.String()
+}
+
+// Key returns the search key of the text: the romanization lower cased, reduced to letters and digits
+// separated by single spaces, e.g. `Санкт-Петербург` becomes `sankt peterburg`. Apostrophes are dropped
+// so ejective marks do not split words.
+func Key(text string) string {
+	romanized := strings.ReplaceAll(strings.ToLower(Romanize(text)), "'", "")
+
+	return strings.Join(strings.FieldsFunc(romanized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func romanizeWord(res *strings.Builder, word []rune) {
+	lowered := make([]rune, len(word))
+	for i, r := range word {
+		lowered[i] = unicode.ToLower(r)
+	}
+
+	for i := 0; i < len(word); {
+		r := word[i]
+
+		if unicode.Is(unicode.Mn, r) {
+			// combining marks of decomposed text and vowel points
+			i++
+
+			continue
+		}
+
+		script := ScriptOf(r)
+
+		if script == ScriptLatin {
+			if folded, ok := latin[r]; ok {
+				res.WriteString(folded)
+			} else {
+				res.WriteRune(r)
+			}
+
+			i++
+
+			continue
+		}
+
+		romanize, ok := scriptRules[script]
+		if !ok {
+			res.WriteRune(r)
+			i++
+
+			continue
+		}
+
+		romanized, consumed := romanize(lowered, i)
+		res.WriteString(applyCase(romanized, word, i))
+
+		i += max(consumed, 1)
+	}
+}
+
+// applyCase upper cases the romanization of an upper case rune, fully when its neighbours are upper case too.
+func applyCase(romanized string, word []rune, i int) string {
+	if !unicode.IsUpper(word[i]) || romanized == "" {
+		return romanized
+	}
+
+	if (i+1 < len(word) && unicode.IsUpper(word[i+1])) || (i > 0 && unicode.IsUpper(word[i-1])) {
+		return strings.ToUpper(romanized)
+	}
+
+	runes := []rune(romanized)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// initial reports whether the rune at position i starts the word, ignoring combining marks.
+func initial(word []rune, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if !unicode.Is(unicode.Mn, word[j]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// single returns a rule mapping each rune using the given table.
+func single(table map[rune]string) rule {
+	return func(word []rune, i int) (string, int) {
+		if res, ok := table[word[i]]; ok {
+			return res, 1
+		}
+
+		return string(word[i]), 1
+	}
+}
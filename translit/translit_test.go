@@ -0,0 +1,153 @@
+package translit
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/translit/testdata"
+)
+
+func Test_Romanize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		given string
+		exp   string
+	}{
+		{name: "latin", given: "Thành phố Hồ Chí Minh", exp: "Thanh pho Ho Chi Minh"},
+		{name: "latin decomposed", given: "Zürich", exp: "Zurich"},
+		{name: "latin ligature", given: "Ærøskøbing", exp: "AEroskobing"},
+		{name: "russian", given: "Москва", exp: "Moskva"},
+		{name: "russian with punctuation", given: "Санкт-Петербург", exp: "Sankt-Peterburg"},
+		{name: "russian soft sign", given: "Ярославль", exp: "Yaroslavl"},
+		{name: "russian two words", given: "Нижний Новгород", exp: "Nizhniy Novgorod"},
+		{name: "russian upper case", given: "ЧЕЛЯБИНСК", exp: "CHELYABINSK"},
+		{name: "russian initial ye", given: "ЕЛЕЦ Енисейск", exp: "YELETS Yeniseysk"},
+		{name: "russian ye after soft sign", given: "Заречье", exp: "Zarechye"},
+		{name: "ukrainian", given: "Запоріжжя", exp: "Zaporizhzhya"},
+		{name: "serbian", given: "Београд", exp: "Beograd"},
+		{name: "greek", given: "Αθήνα", exp: "Athina"},
+		{name: "greek digraph", given: "Θεσσαλονίκη", exp: "Thessaloniki"},
+		{name: "greek vowel digraphs", given: "Ευρώπη Λουτράκι", exp: "Evropi Loutraki"},
+		{name: "greek initial digraph", given: "Ντίσνεϊλαντ Μπαλί", exp: "Disneilant Bali"},
+		{name: "greek upsilon", given: "Κέρκυρα", exp: "Kerkyra"},
+		{name: "arabic", given: "بغداد", exp: "bghdad"},
+		{name: "arabic long vowel", given: "دبي", exp: "dbi"},
+		{name: "arabic initial waw", given: "وهران", exp: "whran"},
+		{name: "hebrew", given: "תל אביב", exp: "tl aviv"},
+		{name: "hebrew with points", given: "יְרוּשָׁלַיִם", exp: "yroshlim"},
+		{name: "georgian", given: "თბილისი", exp: "tbilisi"},
+		{name: "georgian ejective", given: "ქუთაისი პარკი", exp: "kutaisi p'ark'i"},
+		{name: "armenian", given: "Երևան", exp: "Yerevan"},
+		{name: "armenian digraph", given: "Գյումրի", exp: "Gyumri"},
+		{name: "armenian initial vo", given: "Ոսկեպար", exp: "Voskepar"},
+		{name: "hangul", given: "서울", exp: "seoul"},
+		{name: "hangul final before consonant", given: "광주 인천", exp: "gwangju incheon"},
+		{name: "hangul linked final", given: "한국어", exp: "hangugeo"},
+		{name: "hangul double rieul", given: "울릉도", exp: "ulleungdo"},
+		{name: "unsupported script", given: "東京 Tokyo", exp: "東京 Tokyo"},
+		{name: "digits and punctuation", given: "Улица 1905 года, 7", exp: "Ulitsa 1905 goda, 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, Romanize(tt.given))
+		})
+	}
+}
+
+func Test_Romanize_ASCIINames(t *testing.T) {
+	t.Parallel()
+
+	file := testutil.MustOpen(testdata.FS, "ascii_names.txt")
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "#") {
+			continue
+		}
+
+		name, ascii, ok := strings.Cut(scanner.Text(), "\t")
+		require.True(t, ok, scanner.Text())
+
+		switch Detect(name) {
+		case ScriptArabic, ScriptHebrew:
+			// short vowels are not written, so only the consonants can match
+			assert.Equal(t, consonants(ascii), consonants(Romanize(name)), name)
+		case ScriptGeorgian, ScriptHangul:
+			// caseless scripts are romanized in lower case
+			assert.Equal(t, strings.ToLower(ascii), Romanize(name), name)
+		default:
+			assert.Equal(t, ascii, Romanize(name), name)
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+}
+
+func Test_Key(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "sankt peterburg", Key("Санкт-Петербург"))
+	assert.Equal(t, "kutaisi parki", Key("ქუთაისი პარკი"))
+	assert.Equal(t, "st malo", Key(" St.-Malo "))
+	assert.Equal(t, "laquila", Key("L'Aquila"))
+	assert.Equal(t, "東京", Key("東京"))
+	assert.Empty(t, Key(" - "))
+}
+
+func Test_Detect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		given string
+		exp   Script
+	}{
+		{given: "Zürich", exp: ScriptLatin},
+		{given: "Москва", exp: ScriptCyrillic},
+		{given: "Αθήνα", exp: ScriptGreek},
+		{given: "بغداد", exp: ScriptArabic},
+		{given: "תל אביב", exp: ScriptHebrew},
+		{given: "თბილისი", exp: ScriptGeorgian},
+		{given: "Երևան", exp: ScriptArmenian},
+		{given: "서울", exp: ScriptHangul},
+		{given: "Москва (MSK)", exp: ScriptCyrillic},
+		{given: "東京", exp: ScriptUnknown},
+		{given: "123", exp: ScriptUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, Detect(tt.given))
+		})
+	}
+}
+
+func Test_Script_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Cyrillic", ScriptCyrillic.String())
+	assert.Equal(t, "Hangul", ScriptHangul.String())
+	assert.Equal(t, "Unknown", Script(200).String())
+}
+
+// consonants returns the key of the text without vowels.
+func consonants(text string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune("aeiou", r) {
+			return -1
+		}
+
+		return r
+	}, Key(text))
+}
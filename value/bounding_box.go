@@ -1,8 +1,167 @@
 package value
 
+import (
+	"math"
+	"net/url"
+	"strconv"
+)
+
+const fullCircle = 360
+
+// BoundingBox is a latitude/longitude rectangle. A box whose West is greater than its East
+// crosses the antimeridian (180th meridian).
 type BoundingBox struct {
 	East  float64 `url:"east"`
 	West  float64 `url:"west"`
 	North float64 `url:"north"`
 	South float64 `url:"south"`
 }
+
+// MarshalURLValues encodes the bounds with full precision, the zero box is not encoded.
+func (b BoundingBox) MarshalURLValues(_ string, values url.Values) {
+	if b == (BoundingBox{}) {
+		return
+	}
+
+	values.Set("east", strconv.FormatFloat(b.East, 'f', -1, 64))
+	values.Set("west", strconv.FormatFloat(b.West, 'f', -1, 64))
+	values.Set("north", strconv.FormatFloat(b.North, 'f', -1, 64))
+	values.Set("south", strconv.FormatFloat(b.South, 'f', -1, 64))
+}
+
+// BoundingBoxAround returns the smallest box containing the circle with the given radius in km around center.
+// When the circle covers a pole the box spans all longitudes.
+func BoundingBoxAround(center Position, radius float64) BoundingBox {
+	delta := degrees(radius / EarthRadius)
+
+	res := BoundingBox{
+		East:  180,
+		West:  -180,
+		North: center.Latitude + delta,
+		South: center.Latitude - delta,
+	}
+
+	if res.North >= 90 || res.South <= -90 {
+		res.North = math.Min(res.North, 90)
+		res.South = math.Max(res.South, -90)
+
+		return res
+	}
+
+	dLng := degrees(math.Asin(math.Sin(radius/EarthRadius) / math.Cos(radians(center.Latitude))))
+	if dLng >= fullCircle/2 {
+		return res
+	}
+
+	res.West = NormalizeLongitude(center.Longitude - dLng)
+	res.East = NormalizeLongitude(center.Longitude + dLng)
+
+	return res
+}
+
+// CrossesAntimeridian reports whether the box wraps around the 180th meridian.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+// Center returns the middle point of the box, taking the antimeridian into account.
+func (b BoundingBox) Center() Position {
+	return Position{
+		Latitude:  (b.North + b.South) / 2,
+		Longitude: NormalizeLongitude(b.West + lngSpan(b.West, b.East)/2),
+	}
+}
+
+// Contains reports whether the given position is inside the box (edges included).
+func (b BoundingBox) Contains(p Position) bool {
+	if p.Latitude < b.South || p.Latitude > b.North {
+		return false
+	}
+
+	return lngContains(b.West, b.East, p.Longitude)
+}
+
+// Intersects reports whether the box shares at least one point with the given box.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if b.South > other.North || b.North < other.South {
+		return false
+	}
+
+	return lngContains(b.West, b.East, other.West) || lngContains(other.West, other.East, b.West)
+}
+
+// Union returns the smallest box containing both boxes.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	west, east := lngUnion(b.West, b.East, other.West, other.East)
+
+	return BoundingBox{
+		East:  east,
+		West:  west,
+		North: math.Max(b.North, other.North),
+		South: math.Min(b.South, other.South),
+	}
+}
+
+// Expand returns the smallest box containing both the box and the given position.
+func (b BoundingBox) Expand(p Position) BoundingBox {
+	return b.Union(BoundingBox{
+		East:  p.Longitude,
+		West:  p.Longitude,
+		North: p.Latitude,
+		South: p.Latitude,
+	})
+}
+
+// lngSpan returns the width in degrees of the longitude interval going east from west to east.
+func lngSpan(west, east float64) float64 {
+	if west == -180 && east == 180 {
+		return fullCircle
+	}
+
+	if east >= west {
+		return east - west
+	}
+
+	return east - west + fullCircle
+}
+
+func lngContains(west, east, lng float64) bool {
+	if west <= east {
+		return lng >= west && lng <= east
+	}
+
+	return lng >= west || lng <= east
+}
+
+// lngCovers reports whether the interval going east from west to east fully covers the other interval.
+func lngCovers(west, east, otherWest, otherEast float64) bool {
+	span := lngSpan(west, east)
+	if span == fullCircle {
+		return true
+	}
+
+	if !lngContains(west, east, otherWest) {
+		return false
+	}
+
+	return math.Mod(otherWest-west+fullCircle, fullCircle)+lngSpan(otherWest, otherEast) <= span
+}
+
+// lngUnion returns the narrowest longitude interval covering both given intervals.
+func lngUnion(aWest, aEast, bWest, bEast float64) (float64, float64) {
+	candidates := [][2]float64{{aWest, aEast}, {bWest, bEast}, {aWest, bEast}, {bWest, aEast}}
+
+	best, bestSpan := [2]float64{-180, 180}, float64(fullCircle)
+
+	for _, c := range candidates {
+		if !lngCovers(c[0], c[1], aWest, aEast) || !lngCovers(c[0], c[1], bWest, bEast) {
+			continue
+		}
+
+		if span := lngSpan(c[0], c[1]); span < bestSpan {
+			best, bestSpan = c, span
+		}
+	}
+
+	return best[0], best[1]
+}
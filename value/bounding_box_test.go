@@ -0,0 +1,214 @@
+package value
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	boxEurope = BoundingBox{East: 40, West: -10, North: 70, South: 35}
+	boxFiji   = BoundingBox{East: -178, West: 177, North: -15, South: -21}
+)
+
+func Test_BoundingBoxAround(t *testing.T) {
+	t.Parallel()
+
+	t.Run("equator", func(t *testing.T) {
+		t.Parallel()
+
+		actual := BoundingBoxAround(Position{}, 111.19508)
+
+		assert.InDelta(t, 1, actual.North, coordinateDelta)
+		assert.InDelta(t, -1, actual.South, coordinateDelta)
+		assert.InDelta(t, 1, actual.East, coordinateDelta)
+		assert.InDelta(t, -1, actual.West, coordinateDelta)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		actual := BoundingBoxAround(Position{Latitude: 0, Longitude: 179.5}, 111.19508)
+
+		assert.True(t, actual.CrossesAntimeridian())
+		assert.InDelta(t, 178.5, actual.West, coordinateDelta)
+		assert.InDelta(t, -179.5, actual.East, coordinateDelta)
+	})
+
+	t.Run("covers pole", func(t *testing.T) {
+		t.Parallel()
+
+		actual := BoundingBoxAround(Position{Latitude: 89.5, Longitude: 10}, 100)
+
+		assert.Equal(t, BoundingBox{East: 180, West: -180, North: 90, South: actual.South}, actual)
+		assert.InDelta(t, 88.600679, actual.South, coordinateDelta)
+	})
+
+	t.Run("every point of circle is inside", func(t *testing.T) {
+		t.Parallel()
+
+		box := BoundingBoxAround(positionLondon, 500)
+
+		for bearing := 0.0; bearing < 360; bearing += 15 {
+			assert.True(t, box.Contains(positionLondon.Destination(499.999, bearing)), "bearing %v", bearing)
+		}
+	})
+}
+
+func Test_BoundingBox_MarshalURLValues(t *testing.T) {
+	t.Parallel()
+
+	values := url.Values{}
+	BoundingBox{}.MarshalURLValues("", values)
+
+	assert.Empty(t, values)
+
+	BoundingBox{East: 0, West: -10, North: 0.5, South: -1}.MarshalURLValues("", values)
+
+	assert.Equal(t, url.Values{"east": {"0"}, "west": {"-10"}, "north": {"0.5"}, "south": {"-1"}}, values)
+}
+
+func Test_BoundingBox_Contains(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, boxEurope.Contains(positionParis))
+	assert.True(t, boxEurope.Contains(Position{Latitude: 35, Longitude: -10}))
+	assert.False(t, boxEurope.Contains(Position{Latitude: 34.9, Longitude: 0}))
+	assert.False(t, boxEurope.Contains(Position{Latitude: 50, Longitude: 41}))
+
+	assert.True(t, boxFiji.Contains(Position{Latitude: -18, Longitude: 178.4}))
+	assert.True(t, boxFiji.Contains(Position{Latitude: -18, Longitude: -179}))
+	assert.False(t, boxFiji.Contains(Position{Latitude: -18, Longitude: 0}))
+}
+
+func Test_BoundingBox_Intersects(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		a, b     BoundingBox
+		expected bool
+	}{
+		{
+			name:     "overlapping",
+			a:        boxEurope,
+			b:        BoundingBox{East: 50, West: 30, North: 40, South: 20},
+			expected: true,
+		},
+		{
+			name:     "contained",
+			a:        boxEurope,
+			b:        BoundingBox{East: 1, West: 0, North: 51, South: 50},
+			expected: true,
+		},
+		{
+			name:     "disjoint latitude",
+			a:        boxEurope,
+			b:        BoundingBox{East: 1, West: 0, North: 30, South: 20},
+			expected: false,
+		},
+		{
+			name:     "disjoint longitude",
+			a:        boxEurope,
+			b:        BoundingBox{East: 60, West: 50, North: 60, South: 40},
+			expected: false,
+		},
+		{
+			name:     "both across antimeridian",
+			a:        boxFiji,
+			b:        BoundingBox{East: -170, West: 179, North: -10, South: -30},
+			expected: true,
+		},
+		{
+			name:     "one across antimeridian",
+			a:        boxFiji,
+			b:        BoundingBox{East: -170, West: -179, North: -10, South: -30},
+			expected: true,
+		},
+		{
+			name:     "across antimeridian disjoint",
+			a:        boxFiji,
+			b:        BoundingBox{East: 170, West: 160, North: -10, South: -30},
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, testCase.a.Intersects(testCase.b))
+			assert.Equal(t, testCase.expected, testCase.b.Intersects(testCase.a))
+		})
+	}
+}
+
+func Test_BoundingBox_Union(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		a, b     BoundingBox
+		expected BoundingBox
+	}{
+		{
+			name:     "disjoint",
+			a:        BoundingBox{East: 10, West: 0, North: 10, South: 0},
+			b:        BoundingBox{East: 30, West: 20, North: 30, South: 20},
+			expected: BoundingBox{East: 30, West: 0, North: 30, South: 0},
+		},
+		{
+			name:     "contained",
+			a:        boxEurope,
+			b:        BoundingBox{East: 1, West: 0, North: 51, South: 50},
+			expected: boxEurope,
+		},
+		{
+			name:     "shorter way across antimeridian",
+			a:        BoundingBox{East: 175, West: 170, North: 10, South: 0},
+			b:        BoundingBox{East: -170, West: -175, North: 10, South: 0},
+			expected: BoundingBox{East: -170, West: 170, North: 10, South: 0},
+		},
+		{
+			name:     "across antimeridian with regular",
+			a:        boxFiji,
+			b:        BoundingBox{East: 179, West: 170, North: -10, South: -20},
+			expected: BoundingBox{East: -178, West: 170, North: -10, South: -21},
+		},
+		{
+			name:     "covering whole world",
+			a:        BoundingBox{East: 90, West: -90, North: 10, South: 0},
+			b:        BoundingBox{East: -90, West: 90, North: 10, South: 0},
+			expected: BoundingBox{East: 180, West: -180, North: 10, South: 0},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, testCase.a.Union(testCase.b))
+			assert.Equal(t, testCase.expected, testCase.b.Union(testCase.a))
+		})
+	}
+}
+
+func Test_BoundingBox_Expand(t *testing.T) {
+	t.Parallel()
+
+	actual := boxFiji.Expand(Position{Latitude: -25, Longitude: -175})
+
+	assert.Equal(t, BoundingBox{East: -175, West: 177, North: -15, South: -25}, actual)
+
+	actual = boxEurope.Expand(positionParis)
+
+	assert.Equal(t, boxEurope, actual)
+}
+
+func Test_BoundingBox_Center(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Position{Latitude: 52.5, Longitude: 15}, boxEurope.Center())
+	assert.Equal(t, Position{Latitude: -18, Longitude: 179.5}, boxFiji.Center())
+}
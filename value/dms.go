@@ -0,0 +1,299 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	minutesPerDegree = 60
+	secondsPerDegree = 3600
+	maxLatitude      = 90
+	maxLongitude     = 180
+	twoDigits        = 10
+)
+
+var ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+// ParseDMS parses a latitude/longitude pair written in degrees-minutes-seconds, decimal minutes or decimal degrees,
+// e.g. `40°26′46″N 79°58′56″W`, `N40°26.767' W79°58.933'`, `40 26 46 N, 79 58 56 W` or `-40.446, 79.982`.
+// Without hemisphere letters the first coordinate is the latitude.
+func ParseDMS(given string) (Position, error) {
+	groups, err := splitCoordinates(tokenizeCoordinates(given))
+	if err != nil {
+		return Position{}, err
+	}
+
+	var (
+		res            Position
+		hasLat, hasLng bool
+	)
+
+	for i, group := range groups {
+		angle, err := group.angle()
+		if err != nil {
+			return Position{}, err
+		}
+
+		isLatitude := i == 0
+		if group.hemisphere != 0 {
+			isLatitude = group.hemisphere == 'N' || group.hemisphere == 'S'
+		}
+
+		switch {
+		case isLatitude && !hasLat:
+			if math.Abs(angle) > maxLatitude {
+				return Position{}, fmt.Errorf("%w: latitude %v out of range", ErrInvalidCoordinate, angle)
+			}
+
+			res.Latitude, hasLat = angle, true
+		case !isLatitude && !hasLng:
+			if math.Abs(angle) > maxLongitude {
+				return Position{}, fmt.Errorf("%w: longitude %v out of range", ErrInvalidCoordinate, angle)
+			}
+
+			res.Longitude, hasLng = angle, true
+		default:
+			return Position{}, fmt.Errorf("%w: duplicated hemisphere in %q", ErrInvalidCoordinate, given)
+		}
+	}
+
+	return res, nil
+}
+
+// FormatDMS formats the position as degrees-minutes-seconds with the given number of decimals for seconds,
+// e.g. `40°26′46″N 79°58′56″W`.
+func (p Position) FormatDMS(precision int) string {
+	return formatDMS(p.Latitude, 'N', 'S', precision) + " " + formatDMS(p.Longitude, 'E', 'W', precision)
+}
+
+// FormatDecimalMinutes formats the position as degrees and decimal minutes with the given number of decimals,
+// e.g. `40°26.767′N 79°58.933′W`.
+func (p Position) FormatDecimalMinutes(precision int) string {
+	return formatDecimalMinutes(p.Latitude, 'N', 'S', precision) +
+		" " + formatDecimalMinutes(p.Longitude, 'E', 'W', precision)
+}
+
+func formatDMS(angle float64, positive, negative byte, precision int) string {
+	precision = max(precision, 0)
+	scale := math.Pow10(precision)
+	total := math.Round(math.Abs(angle) * secondsPerDegree * scale)
+
+	deg := math.Floor(total / (secondsPerDegree * scale))
+	total -= deg * secondsPerDegree * scale
+	minutes := math.Floor(total / (minutesPerDegree * scale))
+	seconds := (total - minutes*minutesPerDegree*scale) / scale
+
+	return fmt.Sprintf(
+		"%d°%02d′%s″%c",
+		int(deg),
+		int(minutes),
+		formatSexagesimal(seconds, precision),
+		hemisphere(angle, positive, negative),
+	)
+}
+
+func formatDecimalMinutes(angle float64, positive, negative byte, precision int) string {
+	precision = max(precision, 0)
+	scale := math.Pow10(precision)
+	total := math.Round(math.Abs(angle) * minutesPerDegree * scale)
+
+	deg := math.Floor(total / (minutesPerDegree * scale))
+	minutes := (total - deg*minutesPerDegree*scale) / scale
+
+	return fmt.Sprintf(
+		"%d°%s′%c",
+		int(deg),
+		formatSexagesimal(minutes, precision),
+		hemisphere(angle, positive, negative),
+	)
+}
+
+func formatSexagesimal(v float64, precision int) string {
+	res := strconv.FormatFloat(v, 'f', precision, 64)
+	// minutes and seconds are zero padded to two integer digits
+	if v < twoDigits {
+		res = "0" + res
+	}
+
+	return res
+}
+
+func hemisphere(angle float64, positive, negative byte) byte {
+	if angle < 0 {
+		return negative
+	}
+
+	return positive
+}
+
+type coordinateToken struct {
+	number    string
+	letter    rune
+	separator bool
+}
+
+type coordinateGroup struct {
+	numbers    []string
+	hemisphere rune
+}
+
+// angle converts degrees, minutes and seconds of the group into signed decimal degrees.
+func (g coordinateGroup) angle() (float64, error) {
+	const maxComponents = 3
+
+	if len(g.numbers) == 0 || len(g.numbers) > maxComponents {
+		return 0, fmt.Errorf("%w: expected 1 to 3 numbers, got %d", ErrInvalidCoordinate, len(g.numbers))
+	}
+
+	var (
+		res      float64
+		negative bool
+	)
+
+	for i, raw := range g.numbers {
+		if i > 0 && (strings.HasPrefix(raw, "-") || strings.HasPrefix(raw, "+")) {
+			return 0, fmt.Errorf("%w: unexpected sign in %q", ErrInvalidCoordinate, raw)
+		}
+
+		if i < len(g.numbers)-1 && strings.Contains(raw, ".") {
+			return 0, fmt.Errorf("%w: only the last component may be fractional", ErrInvalidCoordinate)
+		}
+
+		component, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidCoordinate, err)
+		}
+
+		if i == 0 {
+			negative = math.Signbit(component)
+			res = math.Abs(component)
+
+			continue
+		}
+
+		if component >= minutesPerDegree {
+			return 0, fmt.Errorf("%w: minutes and seconds must be below 60, got %v", ErrInvalidCoordinate, component)
+		}
+
+		res += component / math.Pow(minutesPerDegree, float64(i))
+	}
+
+	if g.hemisphere == 'S' || g.hemisphere == 'W' {
+		if negative {
+			return 0, fmt.Errorf("%w: both sign and hemisphere given", ErrInvalidCoordinate)
+		}
+
+		negative = true
+	}
+
+	if negative {
+		res = -res
+	}
+
+	return res, nil
+}
+
+func tokenizeCoordinates(given string) []coordinateToken {
+	var (
+		res    []coordinateToken
+		number strings.Builder
+	)
+
+	flush := func() {
+		if number.Len() > 0 {
+			res = append(res, coordinateToken{number: number.String(), letter: 0, separator: false})
+			number.Reset()
+		}
+	}
+
+	for _, r := range given {
+		switch {
+		case unicode.IsDigit(r) || r == '.' || ((r == '-' || r == '+') && number.Len() == 0):
+			number.WriteRune(r)
+		case strings.ContainsRune("NSEWnsew", r):
+			flush()
+			res = append(res, coordinateToken{number: "", letter: unicode.ToUpper(r), separator: false})
+		case r == ',' || r == ';' || r == '/':
+			flush()
+			res = append(res, coordinateToken{number: "", letter: 0, separator: true})
+		case unicode.IsLetter(r):
+			flush()
+			res = append(res, coordinateToken{number: "", letter: r, separator: false})
+		default:
+			flush()
+		}
+	}
+
+	flush()
+
+	return res
+}
+
+// splitCoordinates groups tokens into latitude and longitude parts. Hemisphere letters may lead or trail
+// the numbers, without letters or separators the numbers are split in half.
+func splitCoordinates(tokens []coordinateToken) ([]coordinateGroup, error) {
+	const coordinates = 2
+
+	var (
+		groups   []coordinateGroup
+		current  coordinateGroup
+		lettered bool
+	)
+
+	closeGroup := func() {
+		if len(current.numbers) > 0 || current.hemisphere != 0 {
+			groups = append(groups, current)
+		}
+
+		current = coordinateGroup{numbers: nil, hemisphere: 0}
+	}
+
+	for _, token := range tokens {
+		switch {
+		case token.separator:
+			closeGroup()
+		case token.letter != 0:
+			if !strings.ContainsRune("NSEW", token.letter) {
+				return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidCoordinate, token.letter)
+			}
+
+			lettered = true
+
+			switch {
+			case current.hemisphere == 0 && len(current.numbers) > 0:
+				current.hemisphere = token.letter
+
+				closeGroup()
+			case current.hemisphere != 0 && len(current.numbers) == 0:
+				return nil, fmt.Errorf("%w: unexpected hemisphere %q", ErrInvalidCoordinate, token.letter)
+			default:
+				closeGroup()
+
+				current.hemisphere = token.letter
+			}
+		default:
+			current.numbers = append(current.numbers, token.number)
+		}
+	}
+
+	closeGroup()
+
+	if len(groups) == 1 && !lettered && len(groups[0].numbers)%coordinates == 0 {
+		half := len(groups[0].numbers) / coordinates
+		groups = []coordinateGroup{
+			{numbers: groups[0].numbers[:half], hemisphere: 0},
+			{numbers: groups[0].numbers[half:], hemisphere: 0},
+		}
+	}
+
+	if len(groups) != coordinates {
+		return nil, fmt.Errorf("%w: expected latitude and longitude, got %d parts", ErrInvalidCoordinate, len(groups))
+	}
+
+	return groups, nil
+}
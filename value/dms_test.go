@@ -0,0 +1,93 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// positionCNTower published reference point from https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system
+var positionCNTower = Position{Latitude: 43.642567, Longitude: -79.387139}
+
+func Test_ParseDMS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		given    string
+		expected Position
+	}{
+		{given: "43°38′33.24″N 79°23′13.7″W", expected: positionCNTower},
+		{given: `43°38'33.24"N, 79°23'13.7"W`, expected: positionCNTower},
+		{given: "43 38 33.24 N 79 23 13.7 W", expected: positionCNTower},
+		{given: "N43°38′33.24″ W79°23′13.7″", expected: positionCNTower},
+		{given: "79°23′13.7″W 43°38′33.24″N", expected: positionCNTower},
+		{given: "43°38.554′N 79°23.2283′W", expected: positionCNTower},
+		{given: "n43 38.554 w79 23.2283", expected: positionCNTower},
+		{given: "43.642567, -79.387139", expected: positionCNTower},
+		{given: "43.642567 -79.387139", expected: positionCNTower},
+		{given: "43 38 33.24 -79 23 13.7", expected: positionCNTower},
+		{given: "33°52′8″S 151°12′33″E", expected: Position{Latitude: -33.868889, Longitude: 151.209167}},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseDMS(testCase.given)
+
+		require.NoError(t, err, testCase.given)
+		assert.InDelta(t, testCase.expected.Latitude, actual.Latitude, coordinateDelta, testCase.given)
+		assert.InDelta(t, testCase.expected.Longitude, actual.Longitude, coordinateDelta, testCase.given)
+	}
+
+	errorCases := map[string]string{
+		"43°38′33″N":                 "invalid coordinate: expected latitude and longitude, got 1 parts",
+		"43°61′N 79°23′W":            "invalid coordinate: minutes and seconds must be below 60, got 61",
+		"91°N 79°W":                  "invalid coordinate: latitude 91 out of range",
+		"43°N 181°W":                 "invalid coordinate: longitude -181 out of range",
+		"43°N 79°N":                  "invalid coordinate: duplicated hemisphere in \"43°N 79°N\"",
+		"-43°S 79°W":                 "invalid coordinate: both sign and hemisphere given",
+		"43.5 30 N 79 W":             "invalid coordinate: only the last component may be fractional",
+		"43 -30 N 79 W":              "invalid coordinate: unexpected sign in \"-30\"",
+		"43 30 10 5 N 79 W":          "invalid coordinate: expected 1 to 3 numbers, got 4",
+		"N S 43 79":                  "invalid coordinate: unexpected hemisphere 'S'",
+		"43 X 79":                    "invalid coordinate: unexpected character 'X'",
+		"43..1 79":                   "invalid coordinate: strconv.ParseFloat: parsing \"43..1\": invalid syntax",
+		"43 30 79":                   "invalid coordinate: expected latitude and longitude, got 1 parts",
+		"43°38′33″N 79°23′W 10°20′E": "invalid coordinate: expected latitude and longitude, got 3 parts",
+	}
+
+	for given, expected := range errorCases {
+		actual, err := ParseDMS(given)
+
+		require.ErrorIs(t, err, ErrInvalidCoordinate, given)
+		require.EqualError(t, err, expected, given)
+		assert.Empty(t, actual)
+	}
+}
+
+func Test_Position_FormatDMS(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "43°38′33.24″N 79°23′13.70″W", positionCNTower.FormatDMS(2))
+	assert.Equal(t, "43°38′33″N 79°23′14″W", positionCNTower.FormatDMS(0))
+	assert.Equal(t, "33°52′08″S 151°12′33″E", Position{Latitude: -33.868889, Longitude: 151.209167}.FormatDMS(0))
+	assert.Equal(t, "1°00′00″N 0°00′00″E", Position{Latitude: 0.9999999, Longitude: 0}.FormatDMS(0))
+
+	actual, err := ParseDMS(positionCNTower.FormatDMS(4))
+
+	require.NoError(t, err)
+	assert.InDelta(t, positionCNTower.Latitude, actual.Latitude, coordinateDelta)
+	assert.InDelta(t, positionCNTower.Longitude, actual.Longitude, coordinateDelta)
+}
+
+func Test_Position_FormatDecimalMinutes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "43°38.554′N 79°23.228′W", positionCNTower.FormatDecimalMinutes(3))
+	assert.Equal(t, "0°05′S 0°05′W", Position{Latitude: -0.08, Longitude: -0.08}.FormatDecimalMinutes(0))
+
+	actual, err := ParseDMS(positionCNTower.FormatDecimalMinutes(5))
+
+	require.NoError(t, err)
+	assert.InDelta(t, positionCNTower.Latitude, actual.Latitude, coordinateDelta)
+	assert.InDelta(t, positionCNTower.Longitude, actual.Longitude, coordinateDelta)
+}
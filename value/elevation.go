@@ -0,0 +1,30 @@
+package value
+
+const (
+	// NoDataSRTM is the elevation of the "no data" areas of srtm1 and srtm3, e.g. oceans.
+	NoDataSRTM = -32768
+	// NoDataAstergdem is the elevation of the "no data" areas of aster gdem.
+	NoDataAstergdem = -32768
+	// NoDataGTOPO30 is the elevation of the "no data" areas of gtopo30.
+	NoDataGTOPO30 = -9999
+)
+
+// Elevation in meters of a digital elevation model.
+type Elevation struct {
+	Meters int32
+	// Valid is false for positions without data, Meters is 0 then
+	Valid bool
+}
+
+// NoElevation is the elevation of positions without data.
+var NoElevation = Elevation{Meters: 0, Valid: false}
+
+// NewElevation returns the elevation of the meters, NoElevation for the "no data" marker of the model like
+// NoDataSRTM or NoDataGTOPO30.
+func NewElevation(meters, noData int32) Elevation {
+	if meters == noData {
+		return NoElevation
+	}
+
+	return Elevation{Meters: meters, Valid: true}
+}
@@ -0,0 +1,39 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewElevation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		given  int32
+		noData int32
+		exp    Elevation
+	}{
+		{name: "above sea level", given: 4808, noData: NoDataSRTM, exp: Elevation{Meters: 4808, Valid: true}},
+		{name: "below sea level", given: -430, noData: NoDataSRTM, exp: Elevation{Meters: -430, Valid: true}},
+		{name: "sea level", given: 0, noData: NoDataGTOPO30, exp: Elevation{Meters: 0, Valid: true}},
+		{name: "no data of srtm", given: NoDataSRTM, noData: NoDataSRTM, exp: NoElevation},
+		{name: "no data of aster gdem", given: NoDataAstergdem, noData: NoDataAstergdem, exp: NoElevation},
+		{name: "no data of gtopo30", given: NoDataGTOPO30, noData: NoDataGTOPO30, exp: NoElevation},
+		{
+			name:   "marker of another model",
+			given:  NoDataGTOPO30,
+			noData: NoDataSRTM,
+			exp:    Elevation{Meters: NoDataGTOPO30, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, NewElevation(tt.given, tt.noData))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package value
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	geohashAlphabet     = "0123456789bcdefghjkmnpqrstuvwxyz"
+	geohashBitsPerChar  = 5
+	GeohashMaxPrecision = 12
+)
+
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// Direction is a compass direction used to look up neighbour cells.
+type Direction uint8
+
+const (
+	DirectionNorth Direction = iota
+	DirectionNorthEast
+	DirectionEast
+	DirectionSouthEast
+	DirectionSouth
+	DirectionSouthWest
+	DirectionWest
+	DirectionNorthWest
+)
+
+// Geohash is a base32 encoded cell of the latitude/longitude grid, see https://en.wikipedia.org/wiki/Geohash.
+type Geohash string
+
+// EncodeGeohash returns the geohash cell of the given precision (1-12 characters) containing the position.
+func EncodeGeohash(p Position, precision int) Geohash {
+	precision = min(max(precision, 1), GeohashMaxPrecision)
+
+	latMin, latMax := -90.0, 90.0
+	lngMin, lngMax := -180.0, 180.0
+	lng := NormalizeLongitude(p.Longitude)
+	lat := min(max(p.Latitude, -90), 90)
+
+	var (
+		res  strings.Builder
+		bits int
+		idx  int
+		even = true
+	)
+
+	res.Grow(precision)
+
+	for res.Len() < precision {
+		if even {
+			if mid := (lngMin + lngMax) / 2; lng >= mid {
+				idx = idx<<1 | 1
+				lngMin = mid
+			} else {
+				idx <<= 1
+				lngMax = mid
+			}
+		} else {
+			if mid := (latMin + latMax) / 2; lat >= mid {
+				idx = idx<<1 | 1
+				latMin = mid
+			} else {
+				idx <<= 1
+				latMax = mid
+			}
+		}
+
+		even = !even
+
+		if bits++; bits == geohashBitsPerChar {
+			res.WriteByte(geohashAlphabet[idx])
+
+			bits, idx = 0, 0
+		}
+	}
+
+	return Geohash(res.String())
+}
+
+// Valid reports whether the geohash is non-empty and contains only geohash alphabet characters.
+func (h Geohash) Valid() bool {
+	if h == "" || len(h) > GeohashMaxPrecision {
+		return false
+	}
+
+	for _, r := range string(h) {
+		if !strings.ContainsRune(geohashAlphabet, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BoundingBox returns the cell covered by the geohash.
+func (h Geohash) BoundingBox() (BoundingBox, error) {
+	if !h.Valid() {
+		return BoundingBox{}, ErrInvalidGeohash
+	}
+
+	res := BoundingBox{East: 180, West: -180, North: 90, South: -90}
+	even := true
+
+	for i := range len(h) {
+		idx := strings.IndexByte(geohashAlphabet, h[i])
+
+		for bit := geohashBitsPerChar - 1; bit >= 0; bit-- {
+			set := idx>>bit&1 == 1
+
+			if even {
+				mid := (res.West + res.East) / 2
+				if set {
+					res.West = mid
+				} else {
+					res.East = mid
+				}
+			} else {
+				mid := (res.South + res.North) / 2
+				if set {
+					res.South = mid
+				} else {
+					res.North = mid
+				}
+			}
+
+			even = !even
+		}
+	}
+
+	return res, nil
+}
+
+// Decode returns the center of the geohash cell.
+func (h Geohash) Decode() (Position, error) {
+	box, err := h.BoundingBox()
+	if err != nil {
+		return Position{}, err
+	}
+
+	return box.Center(), nil
+}
+
+// Neighbour returns the adjacent cell of the same precision in the given direction.
+// Cells beyond the poles do not exist, an empty geohash is returned for them.
+func (h Geohash) Neighbour(direction Direction) (Geohash, error) {
+	box, err := h.BoundingBox()
+	if err != nil {
+		return "", err
+	}
+
+	var dLat, dLng float64
+
+	switch direction {
+	case DirectionNorth, DirectionNorthEast, DirectionNorthWest:
+		dLat = 1
+	case DirectionSouth, DirectionSouthEast, DirectionSouthWest:
+		dLat = -1
+	case DirectionEast, DirectionWest:
+	}
+
+	switch direction {
+	case DirectionEast, DirectionNorthEast, DirectionSouthEast:
+		dLng = 1
+	case DirectionWest, DirectionNorthWest, DirectionSouthWest:
+		dLng = -1
+	case DirectionNorth, DirectionSouth:
+	}
+
+	center := box.Center()
+	center.Latitude += dLat * (box.North - box.South)
+	center.Longitude += dLng * (box.East - box.West)
+
+	if center.Latitude > 90 || center.Latitude < -90 {
+		return "", nil
+	}
+
+	return EncodeGeohash(center, len(h)), nil
+}
+
+// Neighbours returns all 8 adjacent cells indexed by Direction.
+func (h Geohash) Neighbours() ([8]Geohash, error) {
+	var res [8]Geohash
+
+	for direction := range res {
+		neighbour, err := h.Neighbour(Direction(direction))
+		if err != nil {
+			return [8]Geohash{}, err
+		}
+
+		res[direction] = neighbour
+	}
+
+	return res, nil
+}
+
+// HasPrefix reports whether the geohash lies within the given (shorter or equal) geohash cell.
+func (h Geohash) HasPrefix(prefix Geohash) bool {
+	return strings.HasPrefix(string(h), string(prefix))
+}
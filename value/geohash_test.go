@@ -0,0 +1,132 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncodeGeohash(t *testing.T) {
+	t.Parallel()
+
+	given := Position{Latitude: 57.64911, Longitude: 10.40744}
+
+	assert.Equal(t, Geohash("u4pruydqqvj"), EncodeGeohash(given, 11))
+	assert.Equal(t, Geohash("u4pru"), EncodeGeohash(given, 5))
+	assert.Equal(t, Geohash("u"), EncodeGeohash(given, 0))
+	assert.Equal(t, Geohash("u4pruydqqvj8"), EncodeGeohash(given, 20))
+	assert.Equal(t, Geohash("ezs42"), EncodeGeohash(Position{Latitude: 42.605, Longitude: -5.603}, 5))
+}
+
+func Test_Geohash_Valid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Geohash("ezs42").Valid())
+	assert.False(t, Geohash("").Valid())
+	assert.False(t, Geohash("ezs4a").Valid())
+	assert.False(t, Geohash("EZS42").Valid())
+	assert.False(t, Geohash("u4pruydqqvjqq").Valid())
+}
+
+func Test_Geohash_BoundingBox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("ezs42").BoundingBox()
+
+		require.NoError(t, err)
+		assert.Equal(t, BoundingBox{East: -5.581054, West: -5.625, North: 42.626953, South: 42.583007}, roundBox(actual))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("a").BoundingBox()
+
+		require.ErrorIs(t, err, ErrInvalidGeohash)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_Geohash_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("ezs42").Decode()
+
+		require.NoError(t, err)
+		assert.InDelta(t, 42.60498, actual.Latitude, 1e-5)
+		assert.InDelta(t, -5.60302, actual.Longitude, 1e-5)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("").Decode()
+
+		require.ErrorIs(t, err, ErrInvalidGeohash)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_Geohash_Neighbours(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("ezs42").Neighbours()
+
+		require.NoError(t, err)
+		assert.Equal(t, [8]Geohash{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"}, actual)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("8").Neighbour(DirectionWest)
+
+		require.NoError(t, err)
+		assert.Equal(t, Geohash("x"), actual)
+	})
+
+	t.Run("beyond pole", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("u").Neighbour(DirectionNorth)
+
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Geohash("i").Neighbours()
+
+		require.ErrorIs(t, err, ErrInvalidGeohash)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_Geohash_HasPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Geohash("u4pruydqqvj").HasPrefix("u4pr"))
+	assert.False(t, Geohash("u4pruydqqvj").HasPrefix("u4ps"))
+}
+
+func roundBox(box BoundingBox) BoundingBox {
+	const factor = 1e6
+
+	round := func(v float64) float64 {
+		return float64(int64(v*factor)) / factor
+	}
+
+	return BoundingBox{East: round(box.East), West: round(box.West), North: round(box.North), South: round(box.South)}
+}
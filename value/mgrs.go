@@ -0,0 +1,134 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	mgrsSquareSize     = 100000
+	mgrsRowCycle       = 2000000
+	mgrsMaxPrecision   = 5
+	mgrsBandCenterLng  = 3
+	mgrsColumnSetCount = 3
+)
+
+var (
+	ErrInvalidMGRS = errors.New("invalid MGRS coordinate")
+
+	mgrsColumnLetters = [mgrsColumnSetCount]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsRowLetters    = [2]string{"ABCDEFGHJKLMNPQRSTUV", "FGHJKLMNPQRSTUVABCDE"}
+)
+
+// ParseMGRS parses a Military Grid Reference System coordinate with or without spaces,
+// e.g. `17T PJ 30084 33438` or `17TPJ3008433438`. The returned UTM reference points to the center
+// of the grid square described by the given precision.
+func ParseMGRS(given string) (UTM, error) {
+	const minLength = 5
+
+	given = strings.ToUpper(strings.Join(strings.Fields(given), ""))
+
+	zoneEnd := strings.IndexFunc(given, func(r rune) bool { return !unicode.IsDigit(r) })
+	if zoneEnd < 1 || len(given) < zoneEnd+3 || len(given) < minLength {
+		return UTM{}, fmt.Errorf("%w: %q", ErrInvalidMGRS, given)
+	}
+
+	zone, err := strconv.ParseUint(given[:zoneEnd], 10, 8)
+	if err != nil {
+		return UTM{}, fmt.Errorf("%w: zone => %w", ErrInvalidMGRS, err)
+	}
+
+	res := UTM{Zone: uint8(zone), Band: given[zoneEnd], Easting: 0, Northing: 0}
+	if err = res.validate(); err != nil {
+		return UTM{}, fmt.Errorf("%w: %w", ErrInvalidMGRS, err)
+	}
+
+	column := strings.IndexByte(mgrsColumnLetters[(res.Zone-1)%mgrsColumnSetCount], given[zoneEnd+1])
+	row := strings.IndexByte(mgrsRowLetters[(res.Zone-1)%2], given[zoneEnd+2])
+
+	if column == -1 || row == -1 {
+		return UTM{}, fmt.Errorf("%w: unknown 100km square %q", ErrInvalidMGRS, given[zoneEnd+1:zoneEnd+3])
+	}
+
+	digits := given[zoneEnd+3:]
+	if len(digits)%2 == 1 || len(digits) > 2*mgrsMaxPrecision {
+		return UTM{}, fmt.Errorf("%w: odd number of digits %q", ErrInvalidMGRS, digits)
+	}
+
+	precision := len(digits) / 2
+	squareSize := math.Pow10(mgrsMaxPrecision - precision)
+
+	easting, northing := 0.0, 0.0
+	if precision > 0 {
+		if easting, err = strconv.ParseFloat(digits[:precision], 64); err != nil {
+			return UTM{}, fmt.Errorf("%w: easting => %w", ErrInvalidMGRS, err)
+		}
+
+		if northing, err = strconv.ParseFloat(digits[precision:], 64); err != nil {
+			return UTM{}, fmt.Errorf("%w: northing => %w", ErrInvalidMGRS, err)
+		}
+	}
+
+	res.Easting = float64(column+1)*mgrsSquareSize + easting*squareSize + squareSize/2
+	res.Northing = float64(row)*mgrsSquareSize + northing*squareSize + squareSize/2
+
+	// The row letters repeat every 2000km, the band gives the 2000km block the square belongs to.
+	bandBottom, err := Position{Latitude: mgrsBandSouth(res.Band), Longitude: mgrsBandCenterLng}.UTM()
+	if err != nil {
+		return UTM{}, fmt.Errorf("%w: %w", ErrInvalidMGRS, err)
+	}
+
+	minNorthing := math.Round(bandBottom.Northing/mgrsSquareSize) * mgrsSquareSize
+	for res.Northing < minNorthing {
+		res.Northing += mgrsRowCycle
+	}
+
+	return res, nil
+}
+
+// MGRS formats the UTM reference as a Military Grid Reference System coordinate with the given number
+// of digits (0-5) per easting and northing, e.g. `17T PJ 30084 33438` for precision 5 (1m).
+func (u UTM) MGRS(precision int) (string, error) {
+	if err := u.validate(); err != nil {
+		return "", err
+	}
+
+	precision = min(max(precision, 0), mgrsMaxPrecision)
+
+	column := int(math.Floor(u.Easting/mgrsSquareSize)) - 1
+	row := int(math.Floor(u.Northing/mgrsSquareSize)) % (mgrsRowCycle / mgrsSquareSize)
+
+	columns := mgrsColumnLetters[(u.Zone-1)%mgrsColumnSetCount]
+	if column < 0 || column >= len(columns) {
+		return "", fmt.Errorf("%w: easting %v out of range", ErrInvalidUTM, u.Easting)
+	}
+
+	res := fmt.Sprintf("%d%c %c%c", u.Zone, u.Band, columns[column], mgrsRowLetters[(u.Zone-1)%2][row])
+	if precision == 0 {
+		return res, nil
+	}
+
+	divisor := math.Pow10(mgrsMaxPrecision - precision)
+	easting := int64(math.Floor(math.Mod(u.Easting, mgrsSquareSize) / divisor))
+	northing := int64(math.Floor(math.Mod(u.Northing, mgrsSquareSize) / divisor))
+
+	return fmt.Sprintf("%s %0*d %0*d", res, precision, easting, precision, northing), nil
+}
+
+// MGRS converts the position into a Military Grid Reference System coordinate, see UTM.MGRS.
+func (p Position) MGRS(precision int) (string, error) {
+	utm, err := p.UTM()
+	if err != nil {
+		return "", err
+	}
+
+	return utm.MGRS(precision)
+}
+
+func mgrsBandSouth(band byte) float64 {
+	return float64((strings.IndexByte(utmBandLetters, band) - 10) * utmBandHeight)
+}
@@ -0,0 +1,142 @@
+package value
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseMGRS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		given    string
+		utm      UTM
+		position Position
+	}{
+		{
+			given:    "17T PJ 30084 33438",
+			utm:      UTM{Zone: 17, Band: 'T', Easting: 630084.5, Northing: 4833438.5},
+			position: positionCNTower,
+		},
+		{
+			given:    "18SUJ2348306479",
+			utm:      UTM{Zone: 18, Band: 'S', Easting: 323483.5, Northing: 4306479.5},
+			position: Position{Latitude: 38.889471, Longitude: -77.035231},
+		},
+		{
+			given:    "33UXP04",
+			utm:      UTM{Zone: 33, Band: 'U', Easting: 605000, Northing: 5345000},
+			position: Position{Latitude: 48.249511, Longitude: 16.414441},
+		},
+		{
+			given:    "4Q FJ 12345 67890",
+			utm:      UTM{Zone: 4, Band: 'Q', Easting: 612345.5, Northing: 2367890.5},
+			position: Position{Latitude: 21.409806, Longitude: -157.916072},
+		},
+		{
+			given:    "56H LH 34368 50948",
+			utm:      UTM{Zone: 56, Band: 'H', Easting: 334368.5, Northing: 6250948.5},
+			position: Position{Latitude: -33.868798, Longitude: 151.209298},
+		},
+		{
+			given:    "31N AA",
+			utm:      UTM{Zone: 31, Band: 'N', Easting: 150000, Northing: 50000},
+			position: Position{Latitude: 0.451680, Longitude: -0.143869},
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseMGRS(testCase.given)
+
+		require.NoError(t, err, testCase.given)
+		assert.Equal(t, testCase.utm, actual, testCase.given)
+
+		position, err := actual.Position()
+
+		require.NoError(t, err, testCase.given)
+		assert.InDelta(t, testCase.position.Latitude, position.Latitude, 1e-5, testCase.given)
+		assert.InDelta(t, testCase.position.Longitude, position.Longitude, 1e-5, testCase.given)
+	}
+
+	errorCases := map[string]string{
+		"T PJ 1 1":        "invalid MGRS coordinate: \"TPJ11\"",
+		"17T":             "invalid MGRS coordinate: \"17T\"",
+		"99T PJ 1 1":      "invalid MGRS coordinate: invalid UTM coordinate: zone 99 out of range",
+		"17T AJ 1 1":      "invalid MGRS coordinate: unknown 100km square \"AJ\"",
+		"17T PJ 1 12":     "invalid MGRS coordinate: odd number of digits \"112\"",
+		"17T PJ 1X 12":    "invalid MGRS coordinate: easting => strconv.ParseFloat: parsing \"1X\": invalid syntax",
+		"17T PJ 12 1X":    "invalid MGRS coordinate: northing => strconv.ParseFloat: parsing \"1X\": invalid syntax",
+		"999T PJ 12 12":   "invalid MGRS coordinate: zone => strconv.ParseUint: parsing \"999\": value out of range",
+		"17T PJ 123456 1": "invalid MGRS coordinate: odd number of digits \"1234561\"",
+	}
+
+	for given, expected := range errorCases {
+		actual, err := ParseMGRS(given)
+
+		require.ErrorIs(t, err, ErrInvalidMGRS, given)
+		require.EqualError(t, err, expected, given)
+		assert.Empty(t, actual)
+	}
+}
+
+func Test_Position_MGRS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		given     Position
+		precision int
+		expected  string
+	}{
+		{given: positionCNTower, precision: 5, expected: "17T PJ 30084 33438"},
+		{given: positionCNTower, precision: 2, expected: "17T PJ 30 33"},
+		{given: positionCNTower, precision: 0, expected: "17T PJ"},
+		{given: Position{Latitude: 0, Longitude: 0}, precision: 5, expected: "31N AA 66021 00000"},
+		{given: Position{Latitude: 38.8895, Longitude: -77.0353}, precision: 5, expected: "18S UJ 23478 06483"},
+		{given: Position{Latitude: -33.8688, Longitude: 151.2093}, precision: 9, expected: "56H LH 34368 50948"},
+		{given: Position{Latitude: 78.22, Longitude: 15.65}, precision: 3, expected: "33X WG 148 830"},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := testCase.given.MGRS(testCase.precision)
+
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual)
+
+		parsed, err := ParseMGRS(actual)
+
+		require.NoError(t, err)
+
+		back, err := parsed.Position()
+
+		require.NoError(t, err)
+		// the parsed reference is the center of the grid square, at most half of its diagonal away
+		squareSize := math.Pow10(mgrsMaxPrecision-min(testCase.precision, mgrsMaxPrecision)) / metersPerKm
+		assert.Less(t, back.Distance(testCase.given), squareSize*math.Sqrt2/2, actual)
+	}
+
+	t.Run("outside bounds", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Position{Latitude: -85, Longitude: 0}.MGRS(5)
+
+		require.ErrorIs(t, err, ErrOutsideUTMBounds)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("invalid utm", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := UTM{Zone: 17, Band: 'T', Easting: 1, Northing: 1}.MGRS(5)
+
+		require.ErrorIs(t, err, ErrInvalidUTM)
+		assert.Empty(t, actual)
+
+		actual, err = UTM{Zone: 17, Band: 'A', Easting: 1, Northing: 1}.MGRS(5)
+
+		require.ErrorIs(t, err, ErrInvalidUTM)
+		assert.Empty(t, actual)
+	})
+}
@@ -0,0 +1,285 @@
+package value
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	plusCodeAlphabet       = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator      = '+'
+	plusCodePadding        = '0'
+	plusCodeSeparatorPos   = 8
+	plusCodeBase           = 20
+	plusCodePairLength     = 10
+	plusCodeGridLength     = 5
+	plusCodeGridRows       = 5
+	plusCodeGridColumns    = 4
+	plusCodeMaxLength      = plusCodePairLength + plusCodeGridLength
+	plusCodePairPrecision  = plusCodeBase * plusCodeBase * plusCodeBase // 1/8000 degree
+	plusCodeGridLatFull    = 3125                                       // plusCodeGridRows ^ plusCodeGridLength
+	plusCodeGridLngFull    = 1024                                       // plusCodeGridColumns ^ plusCodeGridLength
+	plusCodeFinalLatFactor = plusCodePairPrecision * plusCodeGridLatFull
+	plusCodeFinalLngFactor = plusCodePairPrecision * plusCodeGridLngFull
+
+	// PlusCodeDefaultLength code length giving an area of about 14x14 meters.
+	PlusCodeDefaultLength = plusCodePairLength
+)
+
+var (
+	ErrInvalidPlusCode = errors.New("invalid plus code")
+	ErrPlusCodeNotFull = errors.New("plus code is not a full code")
+	ErrPlusCodePadded  = errors.New("padded plus code can not be shortened")
+)
+
+// PlusCode is an Open Location Code, see https://github.com/google/open-location-code.
+type PlusCode string
+
+// EncodePlusCode returns the full plus code of the given length (2-15, odd lengths below 10 are rounded up)
+// for the position.
+func EncodePlusCode(p Position, length int) PlusCode {
+	length = min(max(length, 2), plusCodeMaxLength)
+	if length < plusCodePairLength && length%2 == 1 {
+		length++
+	}
+
+	latVal := int64(math.Round(min(max(p.Latitude, -90), 90)*plusCodeFinalLatFactor)) + 90*plusCodeFinalLatFactor
+	latVal = min(max(latVal, 0), 180*plusCodeFinalLatFactor-1)
+
+	lngVal := int64(math.Round(p.Longitude*plusCodeFinalLngFactor)) + 180*plusCodeFinalLngFactor
+	lngVal %= 360 * plusCodeFinalLngFactor
+
+	if lngVal < 0 {
+		lngVal += 360 * plusCodeFinalLngFactor
+	}
+
+	digits := make([]byte, plusCodeMaxLength)
+
+	for i := plusCodeMaxLength - 1; i >= plusCodePairLength; i-- {
+		digits[i] = plusCodeAlphabet[(latVal%plusCodeGridRows)*plusCodeGridColumns+lngVal%plusCodeGridColumns]
+		latVal /= plusCodeGridRows
+		lngVal /= plusCodeGridColumns
+	}
+
+	for i := plusCodePairLength - 2; i >= 0; i -= 2 {
+		digits[i] = plusCodeAlphabet[latVal%plusCodeBase]
+		digits[i+1] = plusCodeAlphabet[lngVal%plusCodeBase]
+		latVal /= plusCodeBase
+		lngVal /= plusCodeBase
+	}
+
+	var res strings.Builder
+
+	if length < plusCodeSeparatorPos {
+		res.Write(digits[:length])
+		res.WriteString(strings.Repeat(string(plusCodePadding), plusCodeSeparatorPos-length))
+		res.WriteByte(plusCodeSeparator)
+
+		return PlusCode(res.String())
+	}
+
+	res.Write(digits[:plusCodeSeparatorPos])
+	res.WriteByte(plusCodeSeparator)
+	res.Write(digits[plusCodeSeparatorPos:length])
+
+	return PlusCode(res.String())
+}
+
+// Valid reports whether the code is a syntactically valid full or short plus code.
+func (c PlusCode) Valid() bool {
+	code := strings.ToUpper(string(c))
+
+	sep := strings.IndexByte(code, plusCodeSeparator)
+	if sep == -1 || sep != strings.LastIndexByte(code, plusCodeSeparator) || sep%2 == 1 || sep > plusCodeSeparatorPos {
+		return false
+	}
+
+	if len(code) == 1 || len(code)-sep-1 == 1 {
+		return false
+	}
+
+	if pad := strings.IndexByte(code, plusCodePadding); pad != -1 {
+		if sep < plusCodeSeparatorPos || pad == 0 || pad%2 == 1 || sep != len(code)-1 {
+			return false
+		}
+
+		if strings.Trim(code[pad:sep], string(plusCodePadding)) != "" {
+			return false
+		}
+
+		code = code[:pad] + code[sep:]
+	}
+
+	for _, r := range strings.Replace(code, string(plusCodeSeparator), "", 1) {
+		if !strings.ContainsRune(plusCodeAlphabet, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Full reports whether the code is a valid full code which can be decoded without a reference position.
+func (c PlusCode) Full() bool {
+	if !c.Valid() || strings.IndexByte(string(c), plusCodeSeparator) != plusCodeSeparatorPos {
+		return false
+	}
+
+	code := strings.ToUpper(string(c))
+
+	return strings.IndexByte(plusCodeAlphabet, code[0]) < 180/plusCodeBase &&
+		strings.IndexByte(plusCodeAlphabet, code[1]) < 360/plusCodeBase
+}
+
+// Short reports whether the code is a valid short code which must be recovered relative to a reference position.
+func (c PlusCode) Short() bool {
+	return c.Valid() && strings.IndexByte(string(c), plusCodeSeparator) < plusCodeSeparatorPos
+}
+
+// Length returns the number of significant digits of the code, without separator and padding.
+func (c PlusCode) Length() int {
+	return len(c.digits())
+}
+
+// BoundingBox returns the area covered by a full code.
+func (c PlusCode) BoundingBox() (BoundingBox, error) {
+	if !c.Full() {
+		return BoundingBox{}, ErrPlusCodeNotFull
+	}
+
+	digits := c.digits()
+
+	var (
+		latVal, lngVal int64
+		latRes, lngRes int64 = plusCodeFinalLatFactor * plusCodeBase * plusCodeBase,
+			plusCodeFinalLngFactor * plusCodeBase * plusCodeBase
+	)
+
+	for i := 0; i < len(digits) && i < plusCodePairLength; i += 2 {
+		latRes /= plusCodeBase
+		lngRes /= plusCodeBase
+		latVal += int64(strings.IndexByte(plusCodeAlphabet, digits[i])) * latRes
+		lngVal += int64(strings.IndexByte(plusCodeAlphabet, digits[i+1])) * lngRes
+	}
+
+	for i := plusCodePairLength; i < len(digits); i++ {
+		idx := int64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+		latRes /= plusCodeGridRows
+		lngRes /= plusCodeGridColumns
+		latVal += idx / plusCodeGridColumns * latRes
+		lngVal += idx % plusCodeGridColumns * lngRes
+	}
+
+	south := float64(latVal)/plusCodeFinalLatFactor - 90
+	west := float64(lngVal)/plusCodeFinalLngFactor - 180
+
+	return BoundingBox{
+		East:  west + float64(lngRes)/plusCodeFinalLngFactor,
+		West:  west,
+		North: math.Min(south+float64(latRes)/plusCodeFinalLatFactor, 90),
+		South: south,
+	}, nil
+}
+
+// Decode returns the center of the area covered by a full code.
+func (c PlusCode) Decode() (Position, error) {
+	box, err := c.BoundingBox()
+	if err != nil {
+		return Position{}, err
+	}
+
+	return Position{
+		Latitude:  (box.North + box.South) / 2,
+		Longitude: (box.East + box.West) / 2,
+	}, nil
+}
+
+// Shorten removes as many leading digits as possible while the code can still be recovered
+// unambiguously from the given reference position. At most 8 and at least 4 digits are removed.
+func (c PlusCode) Shorten(reference Position) (PlusCode, error) {
+	if !c.Full() {
+		return "", ErrPlusCodeNotFull
+	}
+
+	if strings.IndexByte(string(c), plusCodePadding) != -1 {
+		return "", ErrPlusCodePadded
+	}
+
+	code := strings.ToUpper(string(c))
+
+	center, err := c.Decode()
+	if err != nil {
+		return "", err
+	}
+
+	distance := math.Max(
+		math.Abs(center.Latitude-min(max(reference.Latitude, -90), 90)),
+		math.Abs(center.Longitude-NormalizeLongitude(reference.Longitude)),
+	)
+
+	for pairs := plusCodeSeparatorPos/2 - 1; pairs >= 1; pairs-- {
+		// The range must be less than half of the resolution, 0.3 is used to allow some safety margin.
+		if distance < plusCodePairResolution(pairs+1)*0.3 {
+			return PlusCode(code[(pairs+1)*2:]), nil
+		}
+	}
+
+	return PlusCode(code), nil
+}
+
+// Recover returns the full code nearest to the given reference position that ends with the short code.
+// Full codes are returned as is.
+func (c PlusCode) Recover(reference Position) (PlusCode, error) {
+	if c.Full() {
+		return PlusCode(strings.ToUpper(string(c))), nil
+	}
+
+	if !c.Short() {
+		return "", ErrInvalidPlusCode
+	}
+
+	reference.Latitude = min(max(reference.Latitude, -90), 90)
+	reference.Longitude = NormalizeLongitude(reference.Longitude)
+
+	code := strings.ToUpper(string(c))
+	paddingLength := plusCodeSeparatorPos - strings.IndexByte(code, plusCodeSeparator)
+	resolution := math.Pow(plusCodeBase, float64(2-paddingLength/2))
+	halfResolution := resolution / 2
+
+	prefix := EncodePlusCode(reference, PlusCodeDefaultLength)[:paddingLength]
+
+	center, err := (prefix + PlusCode(code)).Decode()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case reference.Latitude+halfResolution < center.Latitude && center.Latitude-resolution >= -90:
+		center.Latitude -= resolution
+	case reference.Latitude-halfResolution > center.Latitude && center.Latitude+resolution <= 90:
+		center.Latitude += resolution
+	}
+
+	switch {
+	case reference.Longitude+halfResolution < center.Longitude:
+		center.Longitude -= resolution
+	case reference.Longitude-halfResolution > center.Longitude:
+		center.Longitude += resolution
+	}
+
+	return EncodePlusCode(center, len(prefix)+c.Length()), nil
+}
+
+// digits returns upper cased code without separator and padding.
+func (c PlusCode) digits() string {
+	code := strings.ToUpper(string(c))
+	code = strings.Replace(code, string(plusCodeSeparator), "", 1)
+
+	return strings.TrimRight(code, string(plusCodePadding))
+}
+
+// plusCodePairResolution returns the size in degrees of the cell described by the given number of pairs.
+func plusCodePairResolution(pairs int) float64 {
+	return math.Pow(plusCodeBase, float64(2-pairs))
+}
@@ -0,0 +1,219 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncodePlusCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		position Position
+		length   int
+		expected PlusCode
+	}{
+		{position: Position{Latitude: 20.375, Longitude: 2.775}, length: 6, expected: "7FG49Q00+"},
+		{position: Position{Latitude: 20.3700625, Longitude: 2.7821875}, length: 10, expected: "7FG49QCJ+2V"},
+		{position: Position{Latitude: 20.3701125, Longitude: 2.782234375}, length: 11, expected: "7FG49QCJ+2VX"},
+		{position: Position{Latitude: 20.3701135, Longitude: 2.78223535156}, length: 13, expected: "7FG49QCJ+2VXGJ"},
+		{position: Position{Latitude: 47.0000625, Longitude: 8.0000625}, length: 10, expected: "8FVC2222+22"},
+		{position: Position{Latitude: -41.2730625, Longitude: 174.7859375}, length: 10, expected: "4VCPPQGP+Q9"},
+		{position: Position{Latitude: 0.5, Longitude: -179.5}, length: 4, expected: "62G20000+"},
+		{position: Position{Latitude: -89.5, Longitude: -179.5}, length: 4, expected: "22220000+"},
+		{position: Position{Latitude: 0.5, Longitude: 179.5}, length: 4, expected: "6VGX0000+"},
+		{position: Position{Latitude: 1, Longitude: 1}, length: 11, expected: "6FH32222+222"},
+		{position: Position{Latitude: 90, Longitude: 1}, length: 4, expected: "CFX30000+"},
+		{position: Position{Latitude: 90, Longitude: 1}, length: 10, expected: "CFX3X2X2+X2"},
+		{position: Position{Latitude: 1, Longitude: 180}, length: 4, expected: "62H20000+"},
+		{position: Position{Latitude: 1, Longitude: 181}, length: 4, expected: "62H30000+"},
+		{position: Position{Latitude: 20.375, Longitude: 2.775}, length: 5, expected: "7FG49Q00+"},
+		{position: Position{Latitude: 1, Longitude: 1}, length: 99, expected: "6FH32222+2222222"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, EncodePlusCode(testCase.position, testCase.length))
+	}
+}
+
+func Test_PlusCode_Validity(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		code  PlusCode
+		valid bool
+		short bool
+		full  bool
+	}{
+		{code: "8fwc2345+G6", valid: true, short: false, full: true},
+		{code: "8FWC2345+G6G", valid: true, short: false, full: true},
+		{code: "8fwc2345+", valid: true, short: false, full: true},
+		{code: "8FWCX400+", valid: true, short: false, full: true},
+		{code: "WC2345+G6g", valid: true, short: true, full: false},
+		{code: "2345+G6", valid: true, short: true, full: false},
+		{code: "45+G6", valid: true, short: true, full: false},
+		{code: "+G6", valid: true, short: true, full: false},
+		{code: "G+", valid: false, short: false, full: false},
+		{code: "+", valid: false, short: false, full: false},
+		{code: "8FWC2345+G", valid: false, short: false, full: false},
+		{code: "8FWC2_45+G6", valid: false, short: false, full: false},
+		{code: "8FWC2η45+G6", valid: false, short: false, full: false},
+		{code: "8FWC2345+G6+", valid: false, short: false, full: false},
+		{code: "8FWC2345G6+", valid: false, short: false, full: false},
+		{code: "8FWC2300+G6", valid: false, short: false, full: false},
+		{code: "WC2300+G6g", valid: false, short: false, full: false},
+		{code: "WC2345+G", valid: false, short: false, full: false},
+		{code: "WC2300+", valid: false, short: false, full: false},
+		{code: "8FWC0300+", valid: false, short: false, full: false},
+		{code: "22222222+", valid: true, short: false, full: true},
+		{code: "F2222222+", valid: true, short: false, full: false},
+		{code: "2W222222+", valid: true, short: false, full: false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.valid, testCase.code.Valid(), "valid %s", testCase.code)
+		assert.Equal(t, testCase.short, testCase.code.Short(), "short %s", testCase.code)
+		assert.Equal(t, testCase.full, testCase.code.Full(), "full %s", testCase.code)
+	}
+}
+
+func Test_PlusCode_BoundingBox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("7FG49QCJ+2V").BoundingBox()
+
+		require.NoError(t, err)
+		assert.InDelta(t, 20.37, actual.South, coordinateDelta)
+		assert.InDelta(t, 20.370125, actual.North, coordinateDelta)
+		assert.InDelta(t, 2.782125, actual.West, coordinateDelta)
+		assert.InDelta(t, 2.78225, actual.East, coordinateDelta)
+	})
+
+	t.Run("clipped at pole", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("CFX30000+").BoundingBox()
+
+		require.NoError(t, err)
+		assert.Equal(t, BoundingBox{East: 2, West: 1, North: 90, South: 89}, actual)
+	})
+
+	t.Run("short code", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("9QCJ+2VX").BoundingBox()
+
+		require.ErrorIs(t, err, ErrPlusCodeNotFull)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_PlusCode_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("8FVC2222+22").Decode()
+
+		require.NoError(t, err)
+		assert.InDelta(t, 47.0000625, actual.Latitude, coordinateDelta)
+		assert.InDelta(t, 8.0000625, actual.Longitude, coordinateDelta)
+		assert.Equal(t, PlusCode("8FVC2222+22"), EncodePlusCode(actual, 10))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("8FVC2222").Decode()
+
+		require.ErrorIs(t, err, ErrPlusCodeNotFull)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_PlusCode_Shorten(t *testing.T) {
+	t.Parallel()
+
+	code := PlusCode("9C3W9QCJ+2VX")
+
+	testCases := []struct {
+		reference Position
+		expected  PlusCode
+	}{
+		{reference: Position{Latitude: 51.3701125, Longitude: -1.217765625}, expected: "+2VX"},
+		{reference: Position{Latitude: 51.3708675, Longitude: -1.217765625}, expected: "CJ+2VX"},
+		{reference: Position{Latitude: 51.3693575, Longitude: -1.217765625}, expected: "CJ+2VX"},
+		{reference: Position{Latitude: 51.3701125, Longitude: -1.218520625}, expected: "CJ+2VX"},
+		{reference: Position{Latitude: 51.3852125, Longitude: -1.217765625}, expected: "9QCJ+2VX"},
+		{reference: Position{Latitude: 51.3701125, Longitude: -1.232865625}, expected: "9QCJ+2VX"},
+		{reference: Position{Latitude: 60, Longitude: 10}, expected: code},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := code.Shorten(testCase.reference)
+
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual)
+
+		recovered, err := actual.Recover(testCase.reference)
+
+		require.NoError(t, err)
+		assert.Equal(t, code, recovered)
+	}
+
+	t.Run("padded", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("9C3W0000+").Shorten(Position{})
+
+		require.ErrorIs(t, err, ErrPlusCodePadded)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("short", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("9QCJ+2VX").Shorten(Position{})
+
+		require.ErrorIs(t, err, ErrPlusCodeNotFull)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_PlusCode_Recover(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		code      PlusCode
+		reference Position
+		expected  PlusCode
+	}{
+		{code: "9G8F+6X", reference: Position{Latitude: 47.4, Longitude: 8.6}, expected: "8FVC9G8F+6X"},
+		{code: "8fvc9g8f+6x", reference: Position{}, expected: "8FVC9G8F+6X"},
+		{code: "2222+22", reference: Position{Latitude: 89.6, Longitude: 0}, expected: "CFX22222+22"},
+		{code: "XXXX+XX", reference: Position{Latitude: 0, Longitude: 179.9}, expected: "6VFXXXXX+XX"},
+		{code: "2222+22", reference: Position{Latitude: 0, Longitude: 179.99}, expected: "62G22222+22"},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := testCase.code.Recover(testCase.reference)
+
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual, testCase.code)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := PlusCode("9G8F+6").Recover(Position{})
+
+		require.ErrorIs(t, err, ErrInvalidPlusCode)
+		assert.Empty(t, actual)
+	})
+}
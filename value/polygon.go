@@ -0,0 +1,274 @@
+package value
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	geoJSONPolygon      = "Polygon"
+	geoJSONMultiPolygon = "MultiPolygon"
+	minRingLength       = 3
+)
+
+var ErrInvalidGeometry = errors.New("invalid geometry")
+
+// Ring is a closed line of positions, the closing position may be omitted.
+type Ring []Position
+
+// Polygon is an outer ring followed by optional hole rings.
+type Polygon []Ring
+
+// MultiPolygon is a set of polygons, e.g. a country with islands.
+type MultiPolygon []Polygon
+
+// ParseGeoJSON parses a GeoJSON Polygon or MultiPolygon geometry, as used by the GeoNames shapes files.
+func ParseGeoJSON(data []byte) (MultiPolygon, error) {
+	var raw struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w => %w", ErrInvalidGeometry, err)
+	}
+
+	var coordinates [][][][]float64
+
+	switch raw.Type {
+	case geoJSONPolygon:
+		var polygon [][][]float64
+		if err := json.Unmarshal(raw.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("%w => %w", ErrInvalidGeometry, err)
+		}
+
+		coordinates = [][][][]float64{polygon}
+	case geoJSONMultiPolygon:
+		if err := json.Unmarshal(raw.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("%w => %w", ErrInvalidGeometry, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w => unsupported type %q", ErrInvalidGeometry, raw.Type)
+	}
+
+	res := make(MultiPolygon, 0, len(coordinates))
+
+	for _, rawPolygon := range coordinates {
+		polygon := make(Polygon, 0, len(rawPolygon))
+
+		for _, rawRing := range rawPolygon {
+			if len(rawRing) < minRingLength {
+				return nil, fmt.Errorf("%w => ring with %d positions", ErrInvalidGeometry, len(rawRing))
+			}
+
+			ring := make(Ring, 0, len(rawRing))
+
+			for _, rawPosition := range rawRing {
+				if len(rawPosition) < 2 {
+					return nil, fmt.Errorf("%w => position with %d values", ErrInvalidGeometry, len(rawPosition))
+				}
+
+				// GeoJSON positions are longitude first.
+				ring = append(ring, Position{Latitude: rawPosition[1], Longitude: rawPosition[0]})
+			}
+
+			polygon = append(polygon, ring)
+		}
+
+		res = append(res, polygon)
+	}
+
+	return res, nil
+}
+
+// Contains reports whether the position is inside the ring using the even-odd rule. Rings crossing the antimeridian
+// are tested with longitudes shifted to 0-360.
+func (r Ring) Contains(p Position) bool {
+	return r.ContainsWithin(r.BoundingBox(), p)
+}
+
+// ContainsWithin is Contains with the bounding box of the ring already known, e.g. kept by an index. Positions
+// outside the box are rejected without visiting the ring.
+func (r Ring) ContainsWithin(box BoundingBox, p Position) bool {
+	if !box.Contains(p) {
+		return false
+	}
+
+	lng := func(q Position) float64 { return q.Longitude }
+
+	if box.CrossesAntimeridian() {
+		lng = func(q Position) float64 {
+			res := NormalizeLongitude(q.Longitude)
+			if res < 0 {
+				res += fullCircle
+			}
+
+			return res
+		}
+	}
+
+	inside := false
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+
+		if (a.Latitude > p.Latitude) == (b.Latitude > p.Latitude) {
+			continue
+		}
+
+		edge := lng(a) + (p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)*(lng(b)-lng(a))
+		if lng(p) < edge {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Distance returns the approximate distance in km from the position to the nearest edge of the ring.
+// Edges are projected onto a plane tangent at the position, which is accurate for short distances.
+func (r Ring) Distance(p Position) float64 {
+	res := math.Inf(1)
+	scale := math.Cos(radians(p.Latitude))
+
+	project := func(q Position) (float64, float64) {
+		return radians(NormalizeLongitude(q.Longitude-p.Longitude)) * scale * EarthRadius,
+			radians(q.Latitude-p.Latitude) * EarthRadius
+	}
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		ax, ay := project(r[j])
+		bx, by := project(r[i])
+
+		res = math.Min(res, segmentDistance(ax, ay, bx, by))
+	}
+
+	return res
+}
+
+// BoundingBox returns the box containing all positions of the ring, West is greater than East when the ring crosses
+// the antimeridian. Longitudes are followed along the edges, so a ring around a pole spans all longitudes.
+func (r Ring) BoundingBox() BoundingBox {
+	if len(r) == 0 {
+		return BoundingBox{}
+	}
+
+	lng := r[0].Longitude
+	res := BoundingBox{East: lng, West: lng, North: r[0].Latitude, South: r[0].Latitude}
+
+	for i := 1; i < len(r); i++ {
+		lng += NormalizeLongitude(r[i].Longitude - r[i-1].Longitude)
+
+		res.West = min(res.West, lng)
+		res.East = max(res.East, lng)
+		res.South = min(res.South, r[i].Latitude)
+		res.North = max(res.North, r[i].Latitude)
+	}
+
+	if res.East-res.West >= fullCircle {
+		res.West, res.East = -fullCircle/2, fullCircle/2
+
+		return res
+	}
+
+	shift := NormalizeLongitude(res.West) - res.West
+	res.West, res.East = res.West+shift, res.East+shift
+
+	if res.East > fullCircle/2 {
+		res.East -= fullCircle
+	}
+
+	return res
+}
+
+// Contains reports whether the position is inside the outer ring and outside all holes.
+func (p Polygon) Contains(position Position) bool {
+	return p.ContainsWithin(p.BoundingBox(), position)
+}
+
+// ContainsWithin is Contains with the bounding box of the outer ring already known, see Ring.ContainsWithin.
+// The holes lie inside the outer ring, so they are tested with the same box.
+func (p Polygon) ContainsWithin(box BoundingBox, position Position) bool {
+	if len(p) == 0 || !p[0].ContainsWithin(box, position) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if hole.ContainsWithin(box, position) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Distance returns the approximate distance in km from the position to the nearest edge of the polygon.
+func (p Polygon) Distance(position Position) float64 {
+	res := math.Inf(1)
+
+	for _, ring := range p {
+		res = math.Min(res, ring.Distance(position))
+	}
+
+	return res
+}
+
+// BoundingBox returns the box of the outer ring.
+func (p Polygon) BoundingBox() BoundingBox {
+	if len(p) == 0 {
+		return BoundingBox{}
+	}
+
+	return p[0].BoundingBox()
+}
+
+// Contains reports whether the position is inside any of the polygons.
+func (m MultiPolygon) Contains(position Position) bool {
+	for _, polygon := range m {
+		if polygon.Contains(position) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Distance returns the approximate distance in km from the position to the nearest edge of any polygon.
+func (m MultiPolygon) Distance(position Position) float64 {
+	res := math.Inf(1)
+
+	for _, polygon := range m {
+		res = math.Min(res, polygon.Distance(position))
+	}
+
+	return res
+}
+
+// BoundingBox returns the box containing all polygons.
+func (m MultiPolygon) BoundingBox() BoundingBox {
+	if len(m) == 0 {
+		return BoundingBox{}
+	}
+
+	res := m[0].BoundingBox()
+
+	for _, polygon := range m[1:] {
+		res = res.Union(polygon.BoundingBox())
+	}
+
+	return res
+}
+
+// segmentDistance returns the distance from the origin to the segment between a and b.
+func segmentDistance(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = min(max(-(ax*dx+ay*dy)/length, 0), 1)
+	}
+
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
@@ -0,0 +1,195 @@
+package value
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// polygonSquare is a 2x2 degree square around the origin with a 1x1 degree hole in the north-east quarter.
+var polygonSquare = Polygon{
+	{{Latitude: -1, Longitude: -1}, {Latitude: -1, Longitude: 1}, {Latitude: 1, Longitude: 1}, {Latitude: 1, Longitude: -1}},
+	{{Latitude: 0.2, Longitude: 0.2}, {Latitude: 0.2, Longitude: 0.8}, {Latitude: 0.8, Longitude: 0.8}, {Latitude: 0.8, Longitude: 0.2}},
+}
+
+func Test_ParseGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		given string
+		exp   MultiPolygon
+		err   string
+	}{
+		{
+			name:  "polygon",
+			given: `{"type":"Polygon","coordinates":[[[10,20],[11,20],[11,21],[10,20]]]}`,
+			exp: MultiPolygon{{{
+				{Latitude: 20, Longitude: 10},
+				{Latitude: 20, Longitude: 11},
+				{Latitude: 21, Longitude: 11},
+				{Latitude: 20, Longitude: 10},
+			}}},
+		},
+		{
+			name:  "multi polygon",
+			given: `{"type":"MultiPolygon","coordinates":[[[[1,2],[3,4],[5,6]]],[[[7,8],[9,10],[11,12]]]]}`,
+			exp: MultiPolygon{
+				{{{Latitude: 2, Longitude: 1}, {Latitude: 4, Longitude: 3}, {Latitude: 6, Longitude: 5}}},
+				{{{Latitude: 8, Longitude: 7}, {Latitude: 10, Longitude: 9}, {Latitude: 12, Longitude: 11}}},
+			},
+		},
+		{
+			name:  "invalid json",
+			given: `{`,
+			err:   "invalid geometry => unexpected end of JSON input",
+		},
+		{
+			name:  "unsupported type",
+			given: `{"type":"LineString","coordinates":[[1,2],[3,4]]}`,
+			err:   `invalid geometry => unsupported type "LineString"`,
+		},
+		{
+			name:  "invalid polygon coordinates",
+			given: `{"type":"Polygon","coordinates":[1,2]}`,
+			err:   "invalid geometry => json: cannot unmarshal number",
+		},
+		{
+			name:  "invalid multi polygon coordinates",
+			given: `{"type":"MultiPolygon","coordinates":[[1,2]]}`,
+			err:   "invalid geometry => json: cannot unmarshal number",
+		},
+		{
+			name:  "short ring",
+			given: `{"type":"Polygon","coordinates":[[[1,2],[3,4]]]}`,
+			err:   "invalid geometry => ring with 2 positions",
+		},
+		{
+			name:  "short position",
+			given: `{"type":"Polygon","coordinates":[[[1,2],[3],[5,6]]]}`,
+			err:   "invalid geometry => position with 1 values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ParseGeoJSON([]byte(tt.given))
+
+			if tt.err != "" {
+				require.ErrorIs(t, err, ErrInvalidGeometry)
+				require.ErrorContains(t, err, tt.err)
+				assert.Nil(t, actual)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, actual)
+		})
+	}
+}
+
+func Test_Polygon_Contains(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		given Position
+		exp   bool
+	}{
+		{name: "inside", given: Position{Latitude: -0.5, Longitude: -0.5}, exp: true},
+		{name: "inside near hole", given: Position{Latitude: 0.5, Longitude: 0.9}, exp: true},
+		{name: "in hole", given: Position{Latitude: 0.5, Longitude: 0.5}, exp: false},
+		{name: "outside", given: Position{Latitude: 1.5, Longitude: 0}, exp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.exp, polygonSquare.Contains(tt.given))
+			assert.Equal(t, tt.exp, MultiPolygon{polygonSquare}.Contains(tt.given))
+		})
+	}
+
+	assert.False(t, Polygon{}.Contains(Position{}))
+	assert.False(t, MultiPolygon{}.Contains(Position{}))
+}
+
+func Test_Polygon_Distance(t *testing.T) {
+	t.Parallel()
+
+	kmPerDegree := EarthRadius * math.Pi / 180
+
+	assert.InDelta(t, 0.5*kmPerDegree, polygonSquare.Distance(Position{Latitude: 1.5, Longitude: 0}), 1)
+	assert.InDelta(t, 0.1*kmPerDegree, polygonSquare.Distance(Position{Latitude: 0.5, Longitude: 0.1}), 1)
+	assert.InDelta(t, 0.3*kmPerDegree, polygonSquare.Distance(Position{Latitude: 0.5, Longitude: 0.5}), 1)
+	assert.InDelta(t, math.Sqrt2*kmPerDegree, polygonSquare.Distance(Position{Latitude: -2, Longitude: -2}), 1)
+
+	multi := MultiPolygon{polygonSquare, {{{Latitude: 3, Longitude: 0}, {Latitude: 3, Longitude: 1}, {Latitude: 4, Longitude: 1}}}}
+
+	assert.InDelta(t, 0.5*kmPerDegree, multi.Distance(Position{Latitude: 2.5, Longitude: 0.5}), 1)
+	assert.True(t, math.IsInf(MultiPolygon{}.Distance(Position{}), 1))
+}
+
+func Test_Ring_Distance_Antimeridian(t *testing.T) {
+	t.Parallel()
+
+	ring := Ring{{Latitude: -17, Longitude: 177}, {Latitude: -17, Longitude: 180}, {Latitude: -19, Longitude: 180}, {Latitude: -19, Longitude: 177}}
+
+	assert.InDelta(t, 0.5*EarthRadius*math.Pi/180*math.Cos(radians(-18)), ring.Distance(Position{Latitude: -18, Longitude: -179.5}), 1)
+}
+
+func Test_MultiPolygon_BoundingBox(t *testing.T) {
+	t.Parallel()
+
+	multi := MultiPolygon{polygonSquare, {{{Latitude: 3, Longitude: 0}, {Latitude: 3, Longitude: 2}, {Latitude: 4, Longitude: 1}}}}
+
+	assert.Equal(t, BoundingBox{East: 2, West: -1, North: 4, South: -1}, multi.BoundingBox())
+	assert.Equal(t, BoundingBox{}, MultiPolygon{}.BoundingBox())
+	assert.Equal(t, BoundingBox{}, Polygon{}.BoundingBox())
+}
+
+func Test_Ring_Antimeridian(t *testing.T) {
+	t.Parallel()
+
+	ring := Ring{{Latitude: -16, Longitude: 178}, {Latitude: -16, Longitude: -179}, {Latitude: -19, Longitude: -179}, {Latitude: -19, Longitude: 178}}
+
+	assert.Equal(t, BoundingBox{East: -179, West: 178, North: -16, South: -19}, ring.BoundingBox())
+	assert.True(t, ring.Contains(Position{Latitude: -18, Longitude: 179}))
+	assert.True(t, ring.Contains(Position{Latitude: -18, Longitude: -179.5}))
+	assert.False(t, ring.Contains(Position{Latitude: -18, Longitude: 0}))
+	assert.False(t, ring.Contains(Position{Latitude: -18, Longitude: -178}))
+
+	box := ring.BoundingBox()
+
+	assert.True(t, ring.ContainsWithin(box, Position{Latitude: -18, Longitude: 179}))
+	assert.True(t, Polygon{ring}.ContainsWithin(box, Position{Latitude: -18, Longitude: -179.5}))
+	assert.False(t, Polygon{ring}.ContainsWithin(box, Position{Latitude: -15, Longitude: 179}))
+
+	multi := MultiPolygon{{ring}, {{{Latitude: -14, Longitude: -178}, {Latitude: -14, Longitude: -177}, {Latitude: -15, Longitude: -177}}}}
+
+	assert.Equal(t, BoundingBox{East: -177, West: 178, North: -14, South: -19}, multi.BoundingBox())
+}
+
+func Test_Ring_BoundingBox_Pole(t *testing.T) {
+	t.Parallel()
+
+	ring := Ring{
+		{Latitude: -70, Longitude: -180},
+		{Latitude: -65, Longitude: -90},
+		{Latitude: -70, Longitude: 0},
+		{Latitude: -65, Longitude: 90},
+		{Latitude: -70, Longitude: 180},
+		{Latitude: -90, Longitude: 180},
+		{Latitude: -90, Longitude: -180},
+	}
+
+	assert.Equal(t, BoundingBox{East: 180, West: -180, North: -65, South: -90}, ring.BoundingBox())
+	assert.True(t, ring.Contains(Position{Latitude: -80, Longitude: 45}))
+	assert.False(t, ring.Contains(Position{Latitude: -60, Longitude: 45}))
+}
@@ -1,8 +1,165 @@
 package value
 
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// EarthRadius mean radius of the Earth in km, used by spherical calculations.
+	EarthRadius = 6371.0088
+
+	wgs84SemiMajorAxis = 6378.137
+	wgs84Flattening    = 1 / 298.257223563
+	vincentyIterations = 200
+	vincentyPrecision  = 1e-12
+)
+
+var ErrVincentyNotConverged = errors.New("vincenty formula failed to converge")
+
 type Position struct {
 	// Latitude in decimal degrees (wgs84)
 	Latitude float64 `url:"lat"`
 	// Longitude in decimal degrees (wgs84)
 	Longitude float64 `url:"lng"`
 }
+
+// MarshalURLValues encodes the latitude and longitude with full precision, zero coordinates of the equator and
+// the prime meridian included.
+func (p Position) MarshalURLValues(_ string, values url.Values) {
+	values.Set("lat", strconv.FormatFloat(p.Latitude, 'f', -1, 64))
+	values.Set("lng", strconv.FormatFloat(p.Longitude, 'f', -1, 64))
+}
+
+// Distance returns the great-circle distance in km to the given position using the haversine formula,
+// the same unit GeoNames uses for the 'distance' element of nearby results.
+func (p Position) Distance(to Position) float64 {
+	lat1, lat2 := radians(p.Latitude), radians(to.Latitude)
+	dLat := lat2 - lat1
+	dLng := radians(to.Longitude - p.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// VincentyDistance returns the distance in km to the given position on the wgs84 ellipsoid using
+// the inverse Vincenty formula. Nearly antipodal points may fail to converge.
+func (p Position) VincentyDistance(to Position) (float64, error) {
+	const b = wgs84SemiMajorAxis * (1 - wgs84Flattening)
+
+	l := radians(to.Longitude - p.Longitude)
+	u1 := math.Atan((1 - wgs84Flattening) * math.Tan(radians(p.Latitude)))
+	u2 := math.Atan((1 - wgs84Flattening) * math.Tan(radians(to.Latitude)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+
+	lambda := l
+
+	for range vincentyIterations {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+
+		if sinSigma == 0 {
+			return 0, nil
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		c := wgs84Flattening / 16 * cosSqAlpha * (4 + wgs84Flattening*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = l + (1-c)*wgs84Flattening*sinAlpha*
+			(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-prev) < vincentyPrecision {
+			uSq := cosSqAlpha * (wgs84SemiMajorAxis*wgs84SemiMajorAxis - b*b) / (b * b)
+			bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := bigB * sinSigma * (cos2SigmaM + bigB/4*
+				(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+					bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+			return b * bigA * (sigma - deltaSigma), nil
+		}
+	}
+
+	return 0, ErrVincentyNotConverged
+}
+
+// Bearing returns the initial bearing in degrees (0-360, clockwise from north) to the given position.
+func (p Position) Bearing(to Position) float64 {
+	lat1, lat2 := radians(p.Latitude), radians(to.Latitude)
+	dLng := radians(to.Longitude - p.Longitude)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the position reached after travelling the given distance in km
+// along a great circle with the given initial bearing in degrees.
+func (p Position) Destination(distance float64, bearing float64) Position {
+	delta := distance / EarthRadius
+	theta := radians(bearing)
+	lat1, lng1 := radians(p.Latitude), radians(p.Longitude)
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(
+		math.Sin(theta)*math.Sin(delta)*math.Cos(lat1),
+		math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2),
+	)
+
+	return Position{
+		Latitude:  degrees(lat2),
+		Longitude: NormalizeLongitude(degrees(lng2)),
+	}
+}
+
+// Midpoint returns the half-way point along the great circle path to the given position.
+func (p Position) Midpoint(to Position) Position {
+	lat1, lat2 := radians(p.Latitude), radians(to.Latitude)
+	lng1 := radians(p.Longitude)
+	dLng := radians(to.Longitude - p.Longitude)
+
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+
+	return Position{
+		Latitude:  degrees(lat),
+		Longitude: NormalizeLongitude(degrees(lng)),
+	}
+}
+
+// NormalizeLongitude wraps the given longitude into the [-180, 180) range.
+func NormalizeLongitude(longitude float64) float64 {
+	res := math.Mod(longitude+180, 360)
+	if res < 0 {
+		res += 360
+	}
+
+	return res - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,180 @@
+package value
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coordinateDelta = 1e-6
+
+var (
+	positionLondon = Position{Latitude: 51.5074, Longitude: -0.1278}
+	positionParis  = Position{Latitude: 48.8566, Longitude: 2.3522}
+)
+
+func Test_Position_Distance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("london to paris", func(t *testing.T) {
+		t.Parallel()
+
+		assert.InDelta(t, 343.556, positionLondon.Distance(positionParis), 1e-3)
+		assert.InDelta(t, 343.556, positionParis.Distance(positionLondon), 1e-3)
+	})
+
+	t.Run("same point", func(t *testing.T) {
+		t.Parallel()
+
+		assert.InDelta(t, 0, positionLondon.Distance(positionLondon), coordinateDelta)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		given := Position{Latitude: 0, Longitude: 179.5}
+
+		assert.InDelta(t, 111.195, given.Distance(Position{Latitude: 0, Longitude: -179.5}), 1e-3)
+	})
+}
+
+func Test_Position_VincentyDistance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flinders peak to buninyong", func(t *testing.T) {
+		t.Parallel()
+
+		from := Position{Latitude: -37.95103342, Longitude: 144.42486789}
+		to := Position{Latitude: -37.65282114, Longitude: 143.92649554}
+
+		actual, err := from.VincentyDistance(to)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 54.972271, actual, 1e-6)
+	})
+
+	t.Run("same point", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := positionParis.VincentyDistance(positionParis)
+
+		require.NoError(t, err)
+		assert.Zero(t, actual)
+	})
+
+	t.Run("nearly antipodal", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Position{}.VincentyDistance(Position{Latitude: 0.5, Longitude: 179.7})
+
+		require.ErrorIs(t, err, ErrVincentyNotConverged)
+		assert.Zero(t, actual)
+	})
+}
+
+func Test_Position_MarshalURLValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		given Position
+		exp   url.Values
+	}{
+		{
+			name:  "full precision",
+			given: Position{Latitude: 51.5073509, Longitude: -0.1277583},
+			exp:   url.Values{"lat": {"51.5073509"}, "lng": {"-0.1277583"}},
+		},
+		{
+			name:  "null island",
+			given: Position{},
+			exp:   url.Values{"lat": {"0"}, "lng": {"0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			values := url.Values{}
+			tt.given.MarshalURLValues("", values)
+
+			assert.Equal(t, tt.exp, values)
+		})
+	}
+}
+
+func Test_Position_Bearing(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 148.115617, positionLondon.Bearing(positionParis), coordinateDelta)
+	assert.InDelta(t, 90, Position{}.Bearing(Position{Latitude: 0, Longitude: 1}), coordinateDelta)
+	assert.InDelta(t, 0, Position{}.Bearing(Position{Latitude: 1, Longitude: 0}), coordinateDelta)
+	assert.InDelta(t, 270, Position{}.Bearing(Position{Latitude: 0, Longitude: -1}), coordinateDelta)
+}
+
+func Test_Position_Destination(t *testing.T) {
+	t.Parallel()
+
+	t.Run("east of london", func(t *testing.T) {
+		t.Parallel()
+
+		actual := positionLondon.Destination(100, 90)
+
+		assert.InDelta(t, 51.498526, actual.Latitude, coordinateDelta)
+		assert.InDelta(t, 1.316904, actual.Longitude, coordinateDelta)
+	})
+
+	t.Run("across antimeridian", func(t *testing.T) {
+		t.Parallel()
+
+		actual := Position{Latitude: 0, Longitude: 179.5}.Destination(111.19508, 90)
+
+		assert.InDelta(t, 0, actual.Latitude, coordinateDelta)
+		assert.InDelta(t, -179.5, actual.Longitude, coordinateDelta)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		actual := positionLondon.Destination(positionLondon.Distance(positionParis), positionLondon.Bearing(positionParis))
+
+		assert.InDelta(t, positionParis.Latitude, actual.Latitude, coordinateDelta)
+		assert.InDelta(t, positionParis.Longitude, actual.Longitude, coordinateDelta)
+	})
+}
+
+func Test_Position_Midpoint(t *testing.T) {
+	t.Parallel()
+
+	actual := positionLondon.Midpoint(positionParis)
+
+	assert.InDelta(t, 50.188595, actual.Latitude, coordinateDelta)
+	assert.InDelta(t, 1.146618, actual.Longitude, coordinateDelta)
+	assert.InDelta(t, positionLondon.Distance(actual), actual.Distance(positionParis), coordinateDelta)
+
+	actual = Position{Latitude: 0, Longitude: 179}.Midpoint(Position{Latitude: 0, Longitude: -179})
+
+	assert.InDelta(t, 0, actual.Latitude, coordinateDelta)
+	assert.InDelta(t, -180, actual.Longitude, coordinateDelta)
+}
+
+func Test_NormalizeLongitude(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[float64]float64{
+		0:    0,
+		179:  179,
+		180:  -180,
+		181:  -179,
+		-181: 179,
+		540:  -180,
+		-720: 0,
+	}
+
+	for given, expected := range testCases {
+		assert.InDelta(t, expected, NormalizeLongitude(given), coordinateDelta, "given %v", given)
+	}
+}
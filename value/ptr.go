@@ -0,0 +1,18 @@
+package value
+
+// Ptr returns a pointer to the value, for optional request parameters like webservice.SearchRequest.Fuzzy
+// where nil is unset and a pointer to zero is sent.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value of the pointer, the zero value if it is nil.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+
+		return zero
+	}
+
+	return *p
+}
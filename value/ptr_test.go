@@ -0,0 +1,23 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Ptr(t *testing.T) {
+	t.Parallel()
+
+	actual := Ptr(0.0)
+
+	assert.NotNil(t, actual)
+	assert.Zero(t, *actual)
+}
+
+func Test_Deref(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 2.5, Deref(Ptr(2.5)), 0)
+	assert.Zero(t, Deref[float64](nil))
+}
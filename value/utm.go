@@ -0,0 +1,264 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	utmScaleFactor    = 0.9996
+	utmFalseEasting   = 500000
+	utmFalseNorthing  = 10000000
+	utmZoneWidth      = 6
+	utmZones          = 60
+	utmBandHeight     = 8
+	utmMinLatitude    = -80
+	utmMaxLatitude    = 84
+	utmBandLetters    = "CDEFGHJKLMNPQRSTUVWXX"
+	utmSeriesOrder    = 6
+	utmInverseEpsilon = 1e-12
+	metersPerKm       = 1000
+)
+
+var (
+	ErrInvalidUTM       = errors.New("invalid UTM coordinate")
+	ErrOutsideUTMBounds = errors.New("latitude outside of UTM bounds (80°S - 84°N)")
+)
+
+// UTM is a Universal Transverse Mercator grid reference on the wgs84 ellipsoid.
+type UTM struct {
+	// Zone longitude zone 1-60
+	Zone uint8
+	// Band latitude band letter C-X, bands N and above are on the northern hemisphere
+	Band byte
+	// Easting in meters including the 500km false easting
+	Easting float64
+	// Northing in meters, southern hemisphere values include the 10000km false northing
+	Northing float64
+}
+
+// ParseUTM parses a UTM reference written as zone, band, easting and northing, e.g. `17T 630084 4833438`.
+func ParseUTM(given string) (UTM, error) {
+	const parts = 3
+
+	fields := strings.Fields(strings.ToUpper(given))
+	if len(fields) == parts+1 {
+		fields = []string{fields[0] + fields[1], fields[2], fields[3]}
+	}
+
+	if len(fields) != parts || len(fields[0]) < 2 {
+		return UTM{}, fmt.Errorf("%w: %q", ErrInvalidUTM, given)
+	}
+
+	zone, err := strconv.ParseUint(fields[0][:len(fields[0])-1], 10, 8)
+	if err != nil {
+		return UTM{}, fmt.Errorf("%w: zone => %w", ErrInvalidUTM, err)
+	}
+
+	res := UTM{Zone: uint8(zone), Band: fields[0][len(fields[0])-1], Easting: 0, Northing: 0}
+
+	if res.Easting, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return UTM{}, fmt.Errorf("%w: easting => %w", ErrInvalidUTM, err)
+	}
+
+	if res.Northing, err = strconv.ParseFloat(fields[2], 64); err != nil {
+		return UTM{}, fmt.Errorf("%w: northing => %w", ErrInvalidUTM, err)
+	}
+
+	if err = res.validate(); err != nil {
+		return UTM{}, err
+	}
+
+	return res, nil
+}
+
+// UTM converts the position into a UTM reference, the Norway and Svalbard zone exceptions are applied.
+func (p Position) UTM() (UTM, error) {
+	if p.Latitude < utmMinLatitude || p.Latitude > utmMaxLatitude {
+		return UTM{}, ErrOutsideUTMBounds
+	}
+
+	lng := NormalizeLongitude(p.Longitude)
+	zone := utmZone(p.Latitude, lng)
+	x, y := transverseMercator(radians(p.Latitude), radians(lng-utmCentralMeridian(zone)))
+
+	x = utmScaleFactor*x + utmFalseEasting
+	y *= utmScaleFactor
+
+	if y < 0 {
+		y += utmFalseNorthing
+	}
+
+	return UTM{
+		Zone:     zone,
+		Band:     utmBand(p.Latitude),
+		Easting:  x,
+		Northing: y,
+	}, nil
+}
+
+// Position converts the UTM reference back into latitude and longitude.
+func (u UTM) Position() (Position, error) {
+	if err := u.validate(); err != nil {
+		return Position{}, err
+	}
+
+	x := (u.Easting - utmFalseEasting) / utmScaleFactor
+	y := u.Northing
+
+	if !u.North() {
+		y -= utmFalseNorthing
+	}
+
+	lat, lng := inverseTransverseMercator(x, y/utmScaleFactor)
+
+	return Position{
+		Latitude:  degrees(lat),
+		Longitude: NormalizeLongitude(degrees(lng) + utmCentralMeridian(u.Zone)),
+	}, nil
+}
+
+// North reports whether the reference is on the northern hemisphere.
+func (u UTM) North() bool {
+	return u.Band >= 'N'
+}
+
+// String formats the reference rounded to meters, e.g. `17T 630084 4833438`.
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %d %d", u.Zone, u.Band, int64(math.Floor(u.Easting)), int64(math.Floor(u.Northing)))
+}
+
+func (u UTM) validate() error {
+	if u.Zone < 1 || u.Zone > utmZones {
+		return fmt.Errorf("%w: zone %d out of range", ErrInvalidUTM, u.Zone)
+	}
+
+	if strings.IndexByte(utmBandLetters, u.Band) == -1 {
+		return fmt.Errorf("%w: unknown latitude band %q", ErrInvalidUTM, u.Band)
+	}
+
+	return nil
+}
+
+func utmZone(lat, lng float64) uint8 {
+	zone := uint8(math.Floor((lng+180)/utmZoneWidth)) + 1
+
+	switch {
+	case lat >= 56 && lat < 64 && lng >= 3 && lng < 12:
+		return 32
+	case lat >= 72 && lng >= 0 && lng < 42:
+		switch {
+		case lng < 9:
+			return 31
+		case lng < 21:
+			return 33
+		case lng < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+
+	return min(zone, utmZones)
+}
+
+func utmCentralMeridian(zone uint8) float64 {
+	return float64(zone)*utmZoneWidth - 183
+}
+
+func utmBand(lat float64) byte {
+	return utmBandLetters[int(math.Floor(lat/utmBandHeight))+10]
+}
+
+// krugerSeries returns the rectifying radius and the coefficients of the Krüger series (6th order in n)
+// used by the transverse mercator projection.
+func krugerSeries() (a float64, alpha, beta [utmSeriesOrder + 1]float64) {
+	n := wgs84Flattening / (2 - wgs84Flattening)
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+
+	a = wgs84SemiMajorAxis * metersPerKm / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+
+	alpha = [utmSeriesOrder + 1]float64{
+		0,
+		n/2 - 2.0/3*n2 + 5.0/16*n3 + 41.0/180*n4 - 127.0/288*n5 + 7891.0/37800*n6,
+		13.0/48*n2 - 3.0/5*n3 + 557.0/1440*n4 + 281.0/630*n5 - 1983433.0/1935360*n6,
+		61.0/240*n3 - 103.0/140*n4 + 15061.0/26880*n5 + 167603.0/181440*n6,
+		49561.0/161280*n4 - 179.0/168*n5 + 6601661.0/7257600*n6,
+		34729.0/80640*n5 - 3418889.0/1995840*n6,
+		212378941.0 / 319334400 * n6,
+	}
+	beta = [utmSeriesOrder + 1]float64{
+		0,
+		n/2 - 2.0/3*n2 + 37.0/96*n3 - 1.0/360*n4 - 81.0/512*n5 + 96199.0/604800*n6,
+		1.0/48*n2 + 1.0/15*n3 - 437.0/1440*n4 + 46.0/105*n5 - 1118711.0/3870720*n6,
+		17.0/480*n3 - 37.0/840*n4 - 209.0/4480*n5 + 5569.0/90720*n6,
+		4397.0/161280*n4 - 11.0/504*n5 - 830251.0/7257600*n6,
+		4583.0/161280*n5 - 108847.0/3991680*n6,
+		20648693.0 / 638668800 * n6,
+	}
+
+	return a, alpha, beta
+}
+
+// transverseMercator projects the latitude and longitude offset from the central meridian (radians)
+// into unscaled x, y meters.
+func transverseMercator(lat, lng float64) (float64, float64) {
+	a, alpha, _ := krugerSeries()
+	e := math.Sqrt(wgs84Flattening * (2 - wgs84Flattening))
+
+	tau := math.Tan(lat)
+	sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+	tauP := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+
+	xiP := math.Atan2(tauP, math.Cos(lng))
+	etaP := math.Asinh(math.Sin(lng) / math.Sqrt(tauP*tauP+math.Cos(lng)*math.Cos(lng)))
+
+	xi, eta := xiP, etaP
+
+	for j := 1; j <= utmSeriesOrder; j++ {
+		fj := float64(2 * j)
+		xi += alpha[j] * math.Sin(fj*xiP) * math.Cosh(fj*etaP)
+		eta += alpha[j] * math.Cos(fj*xiP) * math.Sinh(fj*etaP)
+	}
+
+	return a * eta, a * xi
+}
+
+// inverseTransverseMercator returns latitude and longitude offset from the central meridian (radians)
+// for the unscaled x, y meters.
+func inverseTransverseMercator(x, y float64) (float64, float64) {
+	a, _, beta := krugerSeries()
+	e := math.Sqrt(wgs84Flattening * (2 - wgs84Flattening))
+
+	eta, xi := x/a, y/a
+	xiP, etaP := xi, eta
+
+	for j := 1; j <= utmSeriesOrder; j++ {
+		fj := float64(2 * j)
+		xiP -= beta[j] * math.Sin(fj*xi) * math.Cosh(fj*eta)
+		etaP -= beta[j] * math.Cos(fj*xi) * math.Sinh(fj*eta)
+	}
+
+	sinhEtaP := math.Sinh(etaP)
+	sinXiP, cosXiP := math.Sincos(xiP)
+
+	tauP := sinXiP / math.Sqrt(sinhEtaP*sinhEtaP+cosXiP*cosXiP)
+	tau := tauP
+
+	for range vincentyIterations {
+		sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+		tauI := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+		delta := (tauP - tauI) / math.Sqrt(1+tauI*tauI) *
+			(1 + (1-e*e)*tau*tau) / ((1 - e*e) * math.Sqrt(1+tau*tau))
+		tau += delta
+
+		if math.Abs(delta) < utmInverseEpsilon {
+			break
+		}
+	}
+
+	return math.Atan(tau), math.Atan2(sinhEtaP, cosXiP)
+}
@@ -0,0 +1,114 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metersDelta = 1e-3
+
+func Test_Position_UTM(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		given    Position
+		expected UTM
+	}{
+		{
+			name:     "cn tower",
+			given:    positionCNTower,
+			expected: UTM{Zone: 17, Band: 'T', Easting: 630084.301, Northing: 4833438.586},
+		},
+		{
+			name:     "origin",
+			given:    Position{Latitude: 0, Longitude: 0},
+			expected: UTM{Zone: 31, Band: 'N', Easting: 166021.443, Northing: 0},
+		},
+		{
+			name:     "southern hemisphere",
+			given:    Position{Latitude: -33.8688, Longitude: 151.2093},
+			expected: UTM{Zone: 56, Band: 'H', Easting: 334368.634, Northing: 6250948.345},
+		},
+		{
+			name:     "norway exception",
+			given:    Position{Latitude: 60.39, Longitude: 5.32},
+			expected: UTM{Zone: 32, Band: 'V', Easting: 297230.220, Northing: 6700510.175},
+		},
+		{
+			name:     "svalbard exception",
+			given:    Position{Latitude: 78.22, Longitude: 15.65},
+			expected: UTM{Zone: 33, Band: 'X', Easting: 514813.527, Northing: 8683004.153},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := testCase.given.UTM()
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected.Zone, actual.Zone)
+			assert.Equal(t, string(testCase.expected.Band), string(actual.Band))
+			assert.InDelta(t, testCase.expected.Easting, actual.Easting, metersDelta)
+			assert.InDelta(t, testCase.expected.Northing, actual.Northing, metersDelta)
+
+			back, err := actual.Position()
+
+			require.NoError(t, err)
+			assert.InDelta(t, testCase.given.Latitude, back.Latitude, 1e-9)
+			assert.InDelta(t, testCase.given.Longitude, back.Longitude, 1e-9)
+		})
+	}
+
+	t.Run("outside bounds", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := Position{Latitude: 85, Longitude: 0}.UTM()
+
+		require.ErrorIs(t, err, ErrOutsideUTMBounds)
+		assert.Empty(t, actual)
+	})
+}
+
+func Test_ParseUTM(t *testing.T) {
+	t.Parallel()
+
+	for _, given := range []string{"17T 630084 4833438", "17 T 630084 4833438", "17t 630084.0 4833438"} {
+		actual, err := ParseUTM(given)
+
+		require.NoError(t, err, given)
+		assert.Equal(t, UTM{Zone: 17, Band: 'T', Easting: 630084, Northing: 4833438}, actual)
+		assert.Equal(t, "17T 630084 4833438", actual.String())
+	}
+
+	errorCases := map[string]string{
+		"17T 630084":           "invalid UTM coordinate: \"17T 630084\"",
+		"XT 630084 4833438":    "invalid UTM coordinate: zone => strconv.ParseUint: parsing \"X\": invalid syntax",
+		"17T X 4833438":        "invalid UTM coordinate: easting => strconv.ParseFloat: parsing \"X\": invalid syntax",
+		"17T 630084 X":         "invalid UTM coordinate: northing => strconv.ParseFloat: parsing \"X\": invalid syntax",
+		"61T 630084 4833438":   "invalid UTM coordinate: zone 61 out of range",
+		"17I 630084 4833438":   "invalid UTM coordinate: unknown latitude band 'I'",
+		"17 T 1 630084 483343": "invalid UTM coordinate: \"17 T 1 630084 483343\"",
+	}
+
+	for given, expected := range errorCases {
+		actual, err := ParseUTM(given)
+
+		require.ErrorIs(t, err, ErrInvalidUTM, given)
+		require.EqualError(t, err, expected, given)
+		assert.Empty(t, actual)
+	}
+}
+
+func Test_UTM_Position(t *testing.T) {
+	t.Parallel()
+
+	actual, err := UTM{Zone: 0, Band: 'T', Easting: 0, Northing: 0}.Position()
+
+	require.ErrorIs(t, err, ErrInvalidUTM)
+	assert.Empty(t, actual)
+}
@@ -13,7 +13,7 @@ type AddressRequest struct {
 	// Position the latitude and longitude of the search location
 	Position value.Position `url:",dive"`
 	// Radius buffer in km (default=0.2)
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// MaxRows the maximal number of rows returned by the service. Default is 1.
 	MaxRows uint32 `url:"maxRows"`
 }
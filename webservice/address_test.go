@@ -56,7 +56,7 @@ func Test_Client_Address(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:  11,
+					Radius:  value.Ptr(11.0),
 					MaxRows: 2,
 				},
 			},
@@ -140,7 +140,7 @@ func Test_Client_Address(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:  11,
+					Radius:  value.Ptr(11.0),
 					MaxRows: 2,
 				},
 			},
@@ -235,7 +235,7 @@ func Test_Client_Address(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -246,6 +246,8 @@ func Test_Client_Address(t *testing.T) {
 								given,
 								"/addressJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
@@ -0,0 +1,201 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBatchConcurrency is the number of concurrent requests of a batch.
+	DefaultBatchConcurrency = 4
+	// batchStoreTTL keeps the responses of the batch store until they are deleted.
+	batchStoreTTL = 100 * 365 * 24 * time.Hour
+)
+
+// ErrBatchStopped is the error of the requests which were not sent because the batch stopped, it wraps the
+// cause like an exceeded quota or the error of the context.
+var ErrBatchStopped = errors.New("batch stopped")
+
+// BatchResult is the outcome of one request of a batch.
+type BatchResult[T any] struct {
+	Value T
+	Err   error
+}
+
+type batchConfig struct {
+	concurrency int
+	store       Cache
+	size        int
+}
+
+type BatchOption func(*batchConfig)
+
+func newBatchConfig(opts []BatchOption) batchConfig {
+	res := batchConfig{concurrency: DefaultBatchConcurrency, store: nil, size: MaxElevationPoints}
+
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	return res
+}
+
+// WithConcurrency sets the maximal number of concurrent requests, default is DefaultBatchConcurrency.
+func WithConcurrency(concurrency int) BatchOption {
+	return func(c *batchConfig) {
+		c.concurrency = max(concurrency, 1)
+	}
+}
+
+// WithBatchSize sets the number of positions per request of the elevation batches like SRTM3Batch,
+// default is MaxElevationPoints.
+func WithBatchSize(size int) BatchOption {
+	return func(c *batchConfig) {
+		c.size = max(size, 1)
+	}
+}
+
+// WithBatchStore keeps the responses of the completed requests in the store, a DiskCache makes the batch
+// resumable: run again after a restart, the stored requests are not sent again. The store replaces the Cache
+// of the client during the batch, failed requests are never stored.
+func WithBatchStore(store Cache) BatchOption {
+	return func(c *batchConfig) {
+		c.store = store
+	}
+}
+
+// Batch sends the requests with the method of the client and returns the results in the order of the requests,
+// e.g. Batch(ctx, client, slices.Values(reqs), (*Client).Timezone). The requests share the retries, credit
+// budget and username pool of the client. An exceeded quota or budget and authorization errors stop the batch,
+// the requests not sent yet fail with ErrBatchStopped.
+func Batch[Req, Res any](
+	ctx context.Context,
+	client *Client,
+	requests iter.Seq[Req],
+	method func(*Client, context.Context, Req) (Res, error),
+	opts ...BatchOption,
+) []BatchResult[Res] {
+	cfg := newBatchConfig(opts)
+
+	if cfg.store != nil {
+		client = client.withStore(cfg.store)
+	}
+
+	var (
+		items []*BatchResult[Res]
+		wg    sync.WaitGroup
+	)
+
+	slots := newBatchSlots(cfg.concurrency)
+
+	for req := range requests {
+		item := new(BatchResult[Res])
+		items = append(items, item)
+
+		if err := slots.acquire(ctx); err != nil {
+			item.Err = fmt.Errorf("%w => %w", ErrBatchStopped, err)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			item.Value, item.Err = method(client, ctx, req)
+
+			slots.release(item.Err)
+		}()
+	}
+
+	wg.Wait()
+
+	res := make([]BatchResult[Res], 0, len(items))
+	for _, item := range items {
+		res = append(res, *item)
+	}
+
+	return res
+}
+
+// stopsBatch reports whether the error fails the other requests as well.
+func stopsBatch(err error) bool {
+	return errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrBudgetExceeded) || errors.Is(err, ErrAuthorization)
+}
+
+// batchSlots bounds the concurrent requests and holds the first error stopping the batch.
+type batchSlots struct {
+	slots chan struct{}
+	mu    sync.Mutex
+	cause error
+}
+
+func newBatchSlots(concurrency int) *batchSlots {
+	return &batchSlots{slots: make(chan struct{}, concurrency), mu: sync.Mutex{}, cause: nil}
+}
+
+// acquire waits for a free slot, it returns the cause once the batch is stopped.
+func (s *batchSlots) acquire(ctx context.Context) error {
+	if ctx.Err() != nil {
+		s.stop(context.Cause(ctx))
+	}
+
+	if err := s.err(); err != nil {
+		return err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		s.stop(context.Cause(ctx))
+
+		return s.err()
+	}
+
+	if err := s.err(); err != nil {
+		<-s.slots
+
+		return err
+	}
+
+	return nil
+}
+
+// release frees the slot of a finished request, the batch is stopped if its error fails the others as well.
+func (s *batchSlots) release(err error) {
+	if stopsBatch(err) {
+		s.stop(err)
+	}
+
+	<-s.slots
+}
+
+func (s *batchSlots) stop(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cause == nil {
+		s.cause = err
+	}
+}
+
+func (s *batchSlots) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cause
+}
+
+// withStore returns a copy of the client caching every response in the store.
+func (c *Client) withStore(store Cache) *Client {
+	res := *c
+	res.cache = store
+	res.cachePolicy = CachePolicy{TTL: batchStoreTTL, Endpoints: nil}
+
+	return &res
+}
@@ -0,0 +1,150 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Batch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("results in order", func(t *testing.T) {
+		t.Parallel()
+
+		errOdd := errors.New("odd")
+
+		var running, maxRunning atomic.Int32
+
+		method := func(_ *Client, _ context.Context, n int) (int, error) {
+			maxRunning.Store(max(maxRunning.Load(), running.Add(1)))
+			defer running.Add(-1)
+
+			// later requests finish first
+			time.Sleep(time.Duration(10-n) * time.Millisecond)
+
+			if n%2 == 1 {
+				return 0, errOdd
+			}
+
+			return n * 10, nil
+		}
+
+		actual := Batch(
+			context.Background(),
+			NewClient("demo"),
+			slices.Values([]int{0, 1, 2, 3, 4, 5, 6, 7}),
+			method,
+			WithConcurrency(3),
+		)
+
+		require.Len(t, actual, 8)
+
+		for n, res := range actual {
+			if n%2 == 1 {
+				require.ErrorIs(t, res.Err, errOdd)
+
+				continue
+			}
+
+			require.NoError(t, res.Err)
+			assert.Equal(t, n*10, res.Value)
+		}
+
+		assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	})
+
+	t.Run("resumable with store", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue(pathGet, testutil.JSONResponse([]byte(`{"geonameId":1}`)), testutil.ErrorResponse(
+			value.ErrCodeDatabaseTimeout,
+			"timeout",
+		))
+
+		client := NewClient("demo", WithBaseURL(fake.URL()))
+		store := NewMemoryCache(0)
+		requests := slices.Values([]GetRequest{{ID: 1}, {ID: 2}, {ID: 3}})
+
+		first := Batch(context.Background(), client, requests, (*Client).Get, WithConcurrency(1), WithBatchStore(store))
+
+		require.NoError(t, first[0].Err)
+		require.ErrorIs(t, first[1].Err, ErrDatabaseTimeout)
+		require.NoError(t, first[2].Err)
+		assert.Len(t, fake.Requests(pathGet), 3)
+
+		second := Batch(context.Background(), client, requests, (*Client).Get, WithBatchStore(store))
+
+		for _, res := range second {
+			require.NoError(t, res.Err)
+		}
+
+		assert.Equal(t, first[0].Value, second[0].Value)
+
+		// only the failed request is sent again
+		requested := fake.Requests(pathGet)
+		require.Len(t, requested, 4)
+		assert.Equal(t, "2", requested[3].URL.Query().Get("geonameId"))
+	})
+
+	t.Run("stopped by exceeded quota", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue(pathGet, testutil.ErrorResponse(value.ErrCodeHourlyLimitExceeded, "limit exceeded"))
+
+		actual := Batch(
+			context.Background(),
+			NewClient("demo", WithBaseURL(fake.URL())),
+			slices.Values([]GetRequest{{ID: 1}, {ID: 2}, {ID: 3}}),
+			(*Client).Get,
+			WithConcurrency(1),
+		)
+
+		require.ErrorIs(t, actual[0].Err, ErrHourlyLimitExceeded)
+		require.NotErrorIs(t, actual[0].Err, ErrBatchStopped)
+
+		for _, res := range actual[1:] {
+			require.ErrorIs(t, res.Err, ErrBatchStopped)
+			require.ErrorIs(t, res.Err, ErrQuotaExceeded)
+		}
+
+		assert.Len(t, fake.Requests(pathGet), 1)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		actual := Batch(
+			ctx,
+			NewClient("demo", WithBaseURL(fake.URL())),
+			slices.Values([]GetRequest{{ID: 1}, {ID: 2}}),
+			(*Client).Get,
+			WithConcurrency(1),
+		)
+
+		require.Len(t, actual, 2)
+
+		for _, res := range actual {
+			require.ErrorIs(t, res.Err, ErrBatchStopped)
+			require.ErrorIs(t, res.Err, context.Canceled)
+		}
+
+		assert.Empty(t, fake.Requests(pathGet))
+	})
+}
@@ -0,0 +1,163 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/platx/geonames/value"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerPolicy configures the circuit breaker, see WithCircuitBreaker.
+type BreakerPolicy struct {
+	// Threshold of consecutive overload failures opening the circuit
+	Threshold int
+	// Cooldown of the open circuit before a single trial request is let through
+	Cooldown time.Duration
+}
+
+// DefaultBreakerPolicy returns a policy opening the circuit for 30s after 5 consecutive overload failures.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+}
+
+// WithCircuitBreaker fails fast with ErrCircuitOpen once the threshold of consecutive overload failures, the
+// overloaded server error and HTTP 5xx responses without a known code, is reached. After the cooldown a trial
+// request is sent, its success closes the circuit again and its failure restarts the cooldown. Every attempt of
+// a retried request counts. Each server of WithServers has its own circuit, the request goes to the next server
+// while one is open.
+func WithCircuitBreaker(policy BreakerPolicy) Option {
+	return func(client *Client) {
+		client.breakers = newBreakerGroup(policy)
+	}
+}
+
+// breakerGroup holds the circuit breakers of the servers by base URL. A nil group allows every request.
+type breakerGroup struct {
+	policy   BreakerPolicy
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerGroup(policy BreakerPolicy) *breakerGroup {
+	return &breakerGroup{policy: policy, mu: sync.Mutex{}, breakers: map[string]*circuitBreaker{}}
+}
+
+// of returns the circuit breaker of the server.
+func (g *breakerGroup) of(baseURL string) *circuitBreaker {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	res, ok := g.breakers[baseURL]
+	if !ok {
+		res = newCircuitBreaker(g.policy)
+		g.breakers[baseURL] = res
+	}
+
+	return res
+}
+
+// circuitBreaker is closed below the failure threshold, open during the cooldown and half-open while the
+// trial request is in flight. A nil breaker allows every request.
+type circuitBreaker struct {
+	policy   BreakerPolicy
+	now      func() time.Time
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// trial is set while the trial request of the half-open circuit is in flight
+	trial bool
+}
+
+func newCircuitBreaker(policy BreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{
+		policy:   policy,
+		now:      time.Now,
+		mu:       sync.Mutex{},
+		failures: 0,
+		openedAt: time.Time{},
+		trial:    false,
+	}
+}
+
+// allow returns ErrCircuitOpen if the request must not be sent, trial tells whether it is the trial request
+// of the half-open circuit.
+func (b *circuitBreaker) allow() (trial bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.policy.Threshold {
+		return false, nil
+	}
+
+	if b.trial || b.now().Before(b.openedAt.Add(b.policy.Cooldown)) {
+		return false, ErrCircuitOpen
+	}
+
+	b.trial = true
+
+	return true, nil
+}
+
+// done records the result of an allowed request, canceled requests tell nothing about the server. Only the
+// completed trial request lets another one through.
+func (b *circuitBreaker) done(ctx context.Context, trial bool, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if !isOverload(err) {
+		b.failures = 0
+
+		return
+	}
+
+	b.failures++
+
+	if b.failures >= b.policy.Threshold {
+		b.openedAt = b.now()
+	}
+}
+
+// isOverload reports whether the error tells that the server is overloaded or failing, other transient errors
+// like database timeouts or rate limiting concern the request and not the server.
+func isOverload(err error) bool {
+	var respErr *ResponseError
+
+	if !errors.As(err, &respErr) {
+		return false
+	}
+
+	if respErr.MatchCode(value.ErrCodeServerOverloaded) {
+		return true
+	}
+
+	return respErr.Code() == 0 && respErr.StatusCode() >= http.StatusInternalServerError
+}
@@ -0,0 +1,212 @@
+package webservice
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	overloaded := &ResponseError{code: value.ErrCodeServerOverloaded}
+	ctx := context.Background()
+
+	newBreaker := func() (*circuitBreaker, *time.Time) {
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		breaker := newCircuitBreaker(BreakerPolicy{Threshold: 2, Cooldown: time.Minute})
+		breaker.now = func() time.Time { return now }
+
+		return breaker, &now
+	}
+
+	allow := func(breaker *circuitBreaker) error {
+		_, err := breaker.allow()
+
+		return err
+	}
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, _ := newBreaker()
+
+		require.NoError(t, allow(breaker))
+		breaker.done(ctx, false, overloaded)
+		require.NoError(t, allow(breaker))
+		breaker.done(ctx, false, overloaded)
+
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+	})
+
+	t.Run("success resets the failures", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, _ := newBreaker()
+
+		breaker.done(ctx, false, overloaded)
+		breaker.done(ctx, false, nil)
+		breaker.done(ctx, false, overloaded)
+
+		require.NoError(t, allow(breaker))
+	})
+
+	t.Run("permanent errors reset the failures", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, _ := newBreaker()
+
+		breaker.done(ctx, false, overloaded)
+		breaker.done(ctx, false, &ResponseError{code: value.ErrCodeAuthorization})
+		breaker.done(ctx, false, overloaded)
+
+		require.NoError(t, allow(breaker))
+	})
+
+	t.Run("canceled requests are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, _ := newBreaker()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		breaker.done(ctx, false, overloaded)
+		breaker.done(canceled, false, nil)
+		breaker.done(ctx, false, overloaded)
+
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+	})
+
+	t.Run("half open", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, now := newBreaker()
+
+		breaker.done(ctx, false, overloaded)
+		breaker.done(ctx, false, overloaded)
+
+		*now = now.Add(time.Minute)
+
+		// a single trial request while half open
+		trial, err := breaker.allow()
+		require.NoError(t, err)
+		assert.True(t, trial)
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+
+		// a failed trial restarts the cooldown
+		breaker.done(ctx, true, overloaded)
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+
+		*now = now.Add(time.Minute)
+
+		// a successful trial closes the circuit
+		trial, err = breaker.allow()
+		require.NoError(t, err)
+		assert.True(t, trial)
+		breaker.done(ctx, true, nil)
+		require.NoError(t, allow(breaker))
+		require.NoError(t, allow(breaker))
+	})
+
+	t.Run("requests sent before the trial", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, now := newBreaker()
+
+		breaker.done(ctx, false, overloaded)
+		breaker.done(ctx, false, overloaded)
+
+		*now = now.Add(time.Minute)
+
+		require.NoError(t, allow(breaker))
+
+		// the failure of a request sent while closed does not end the trial
+		breaker.done(ctx, false, overloaded)
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+	})
+
+	t.Run("only overload failures count", func(t *testing.T) {
+		t.Parallel()
+
+		breaker, _ := newBreaker()
+
+		breaker.done(ctx, false, &ResponseError{code: 0, statusCode: http.StatusBadGateway})
+		breaker.done(ctx, false, &ResponseError{code: value.ErrCodeDatabaseTimeout})
+		breaker.done(ctx, false, &ResponseError{code: 0, statusCode: http.StatusTooManyRequests})
+		breaker.done(ctx, false, &ResponseError{code: 0, statusCode: http.StatusBadGateway})
+
+		require.NoError(t, allow(breaker))
+
+		breaker.done(ctx, false, &ResponseError{code: 0, statusCode: http.StatusServiceUnavailable})
+
+		require.ErrorIs(t, allow(breaker), ErrCircuitOpen)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		var breaker *circuitBreaker
+
+		breaker.done(ctx, false, overloaded)
+		require.NoError(t, allow(breaker))
+	})
+}
+
+func Test_Client_circuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t)
+	fake.Handle("/getJSON", testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+	client := NewClient(
+		"demo",
+		WithBaseURL(fake.URL()),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Jitter: 0}),
+		WithCircuitBreaker(BreakerPolicy{Threshold: 3, Cooldown: time.Hour}),
+	)
+
+	_, err := client.Get(context.Background(), GetRequest{ID: 1})
+	require.ErrorIs(t, err, ErrServerOverloaded)
+
+	// the threshold is reached with the first attempt of the second request, the retry fails fast
+	_, err = client.Get(context.Background(), GetRequest{ID: 1})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	_, err = client.Get(context.Background(), GetRequest{ID: 1})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	assert.Len(t, fake.Requests("/getJSON"), 3)
+}
+
+func Test_Client_circuitBreaker_failover(t *testing.T) {
+	t.Parallel()
+
+	primary := testutil.NewFakeServer(t)
+	primary.Handle("/getJSON", testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+	secondary := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+	client := NewClient(
+		"demo",
+		WithServers(primary.URL(), secondary.URL()),
+		WithCircuitBreaker(BreakerPolicy{Threshold: 2, Cooldown: time.Hour}),
+	)
+
+	for range 4 {
+		_, err := client.Get(context.Background(), GetRequest{ID: 2643743})
+		require.NoError(t, err)
+	}
+
+	// the circuit of the primary server opens after two failures, the secondary one stays closed
+	assert.Len(t, primary.Requests("/getJSON"), 2)
+	assert.Len(t, secondary.Requests("/getJSON"), 4)
+}
@@ -0,0 +1,147 @@
+package webservice
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Cache stores the bodies of successful responses, keyed by the URL of the request without the username.
+// Implementations must be safe for concurrent use and treat their failures as misses.
+type Cache interface {
+	// Get returns the body of the key, false if it is missing or expired
+	Get(key string) ([]byte, bool)
+	// Set stores the body of the key for the ttl
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// defaultTTLs are the TTLs of the endpoints differing from the default, see DefaultCachePolicy.
+var defaultTTLs = map[string]time.Duration{
+	pathEarthquakes:       5 * time.Minute,
+	pathFindNearbyWeather: 10 * time.Minute,
+	pathWeather:           10 * time.Minute,
+	pathWeatherICAO:       10 * time.Minute,
+	pathTimezone:          time.Hour,
+	pathChildren:          7 * 24 * time.Hour,
+	pathContains:          7 * 24 * time.Hour,
+	pathCountryInfo:       7 * 24 * time.Hour,
+	pathHierarchy:         7 * 24 * time.Hour,
+	pathNeighbours:        7 * 24 * time.Hour,
+	pathSiblings:          7 * 24 * time.Hour,
+}
+
+// CachePolicy configures the TTLs of the cached responses, see WithCache.
+type CachePolicy struct {
+	// TTL of the endpoints without their own, 0 disables their caching
+	TTL time.Duration
+	// Endpoints are the TTLs by path like "/weatherJSON", 0 disables the caching of the endpoint
+	Endpoints map[string]time.Duration
+}
+
+// DefaultCachePolicy returns a TTL of a day, minutes for weather and earthquakes, an hour for the timezone
+// including the current time and a week for the hierarchy, children, siblings, neighbours and country info.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		TTL:       24 * time.Hour,
+		Endpoints: maps.Clone(defaultTTLs),
+	}
+}
+
+// WithCache serves repeated requests from the cache, concurrent identical requests are sent only once.
+// Cache hits neither send requests nor charge credits, error responses are never cached.
+func WithCache(cache Cache, policy CachePolicy) Option {
+	policy.Endpoints = maps.Clone(policy.Endpoints)
+
+	return func(client *Client) {
+		client.cache = cache
+		client.cachePolicy = policy
+	}
+}
+
+func (p CachePolicy) ttl(path string) time.Duration {
+	if ttl, ok := p.Endpoints[path]; ok {
+		return ttl
+	}
+
+	return p.TTL
+}
+
+// flightGroup deduplicates concurrent calls with the same key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		mu:    sync.Mutex{},
+		calls: map[string]*flight{},
+	}
+}
+
+// do calls fn unless a call of the key is in flight, whose result is returned instead, shared tells which.
+// The call runs with the values but without the cancellation of the context of its first caller, so every
+// caller returns early when its own context is done and the call is canceled once no caller is left.
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) ([]byte, error),
+) ([]byte, bool, error) {
+	g.mu.Lock()
+
+	call, shared := g.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flight{done: make(chan struct{}), body: nil, err: nil, waiters: 0, cancel: cancel}
+		g.calls[key] = call
+
+		go func() {
+			defer cancel()
+
+			call.body, call.err = fn(callCtx)
+
+			g.mu.Lock()
+			g.forget(key, call)
+			g.mu.Unlock()
+
+			close(call.done)
+		}()
+	}
+
+	call.waiters++
+
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, shared, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+
+		call.waiters--
+		if call.waiters == 0 {
+			g.forget(key, call)
+			call.cancel()
+		}
+
+		g.mu.Unlock()
+
+		return nil, shared, context.Cause(ctx)
+	}
+}
+
+// forget removes the call unless a new one of the key took its place.
+func (g *flightGroup) forget(key string, call *flight) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
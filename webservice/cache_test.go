@@ -0,0 +1,269 @@
+package webservice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Client_cache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hit", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		cache := NewMemoryCache(10)
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithCache(cache, DefaultCachePolicy()))
+
+		first, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		second, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		_, err = client.Get(context.Background(), GetRequest{ID: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Len(t, fake.Requests(pathGet), 2)
+
+		_, ok := cache.Get(fake.URL() + pathGet + "?geonameId=1")
+		assert.True(t, ok)
+	})
+
+	t.Run("shared by clients of other users", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		cache := WithCache(NewMemoryCache(10), DefaultCachePolicy())
+
+		_, err := NewClient("foo", WithBaseURL(fake.URL()), cache).Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		_, err = NewClient("bar", WithBaseURL(fake.URL()), cache).Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		assert.Len(t, fake.Requests(pathGet), 1)
+	})
+
+	t.Run("disabled endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithCache(NewMemoryCache(10), CachePolicy{
+			TTL:       time.Hour,
+			Endpoints: map[string]time.Duration{pathGet: 0},
+		}))
+
+		for range 2 {
+			_, err := client.Get(context.Background(), GetRequest{ID: 1})
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, fake.Requests(pathGet), 2)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue(pathGet, testutil.ErrorResponse(value.ErrCodeRecordNotExist, "record does not exist"))
+
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithCache(NewMemoryCache(10), DefaultCachePolicy()))
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrRecordNotExist)
+
+		_, err = client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		assert.Len(t, fake.Requests(pathGet), 2)
+	})
+
+	t.Run("concurrent identical requests", func(t *testing.T) {
+		t.Parallel()
+
+		slow := testutil.JSONResponse([]byte(`{"geonameId":1}`))
+		slow.Delay = 100 * time.Millisecond
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle(pathGet, slow)
+
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithCache(NewMemoryCache(10), DefaultCachePolicy()))
+
+		var wg sync.WaitGroup
+
+		results := make([]GeoNameDetailed, 10)
+		errs := make([]error, 10)
+
+		for i := range 10 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				results[i], errs[i] = client.Get(context.Background(), GetRequest{ID: 1})
+			}()
+		}
+
+		wg.Wait()
+
+		for i := range 10 {
+			require.NoError(t, errs[i])
+			assert.Equal(t, uint64(1), results[i].ID)
+		}
+
+		assert.Len(t, fake.Requests(pathGet), 1)
+	})
+}
+
+func Test_CachePolicy_ttl(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultCachePolicy()
+
+	assert.Equal(t, 24*time.Hour, policy.ttl(pathGet))
+	assert.Equal(t, 10*time.Minute, policy.ttl(pathWeather))
+	assert.Equal(t, 5*time.Minute, policy.ttl(pathEarthquakes))
+	assert.Equal(t, 7*24*time.Hour, policy.ttl(pathHierarchy))
+}
+
+func Test_flightGroup_do(t *testing.T) {
+	t.Parallel()
+
+	t.Run("waiting caller with done context", func(t *testing.T) {
+		t.Parallel()
+
+		group := newFlightGroup()
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		go func() {
+			_, _, _ = group.do(context.Background(), "key", func(context.Context) ([]byte, error) {
+				close(started)
+				<-release
+
+				return []byte("body"), nil
+			})
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, shared, err := group.do(ctx, "key", func(context.Context) ([]byte, error) {
+			return nil, assert.AnError
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.True(t, shared)
+
+		close(release)
+	})
+
+	t.Run("canceled first caller", func(t *testing.T) {
+		t.Parallel()
+
+		group := newFlightGroup()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		callErr := make(chan error, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leader := make(chan error, 1)
+
+		go func() {
+			_, _, err := group.do(ctx, "key", func(ctx context.Context) ([]byte, error) {
+				close(started)
+				<-release
+
+				callErr <- ctx.Err()
+
+				return []byte("body"), nil
+			})
+
+			leader <- err
+		}()
+
+		<-started
+
+		waiter := make(chan []byte, 1)
+
+		go func() {
+			body, _, _ := group.do(context.Background(), "key", func(context.Context) ([]byte, error) {
+				return nil, assert.AnError
+			})
+
+			waiter <- body
+		}()
+
+		require.Eventually(t, func() bool {
+			group.mu.Lock()
+			defer group.mu.Unlock()
+
+			return group.calls["key"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		require.ErrorIs(t, <-leader, context.Canceled)
+
+		close(release)
+
+		// the call goes on for the waiting caller
+		require.NoError(t, <-callErr)
+		assert.Equal(t, []byte("body"), <-waiter)
+	})
+
+	t.Run("canceled last caller", func(t *testing.T) {
+		t.Parallel()
+
+		group := newFlightGroup()
+		canceled := make(chan error, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, shared, err := group.do(ctx, "key", func(ctx context.Context) ([]byte, error) {
+			<-ctx.Done()
+
+			canceled <- ctx.Err()
+
+			return nil, ctx.Err()
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, shared)
+		require.ErrorIs(t, <-canceled, context.Canceled)
+	})
+
+	t.Run("sequential calls", func(t *testing.T) {
+		t.Parallel()
+
+		group := newFlightGroup()
+		calls := 0
+
+		for range 2 {
+			body, shared, err := group.do(context.Background(), "key", func(context.Context) ([]byte, error) {
+				calls++
+
+				return []byte("body"), nil
+			})
+
+			require.NoError(t, err)
+			assert.False(t, shared)
+			assert.Equal(t, []byte("body"), body)
+		}
+
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -13,6 +13,8 @@ type ChildrenRequest struct {
 	ID uint64 `url:"geonameId"`
 	// MaxRows number of rows returned, default is 200.
 	MaxRows uint32 `url:"maxRows"`
+	// StartRow used for paging results, see ChildrenAll
+	StartRow uint32 `url:"startRow"`
 	// Hierarchy allows to use other hiearchies then the default administrative hierarchy.
 	Hierarchy value.Hierarchy `url:"hierarchy"`
 }
@@ -38,3 +40,27 @@ func (c *Client) Children(ctx context.Context, req ChildrenRequest) ([]GeoName,
 
 	return res.Items, err
 }
+
+// ChildrenAll returns the pages of Children from the StartRow of the request, the page size is MaxRows or 200.
+// The totalResultsCount of the webservice is reported by Pages.Total.
+func (c *Client) ChildrenAll(ctx context.Context, req ChildrenRequest, opts ...PagesOption) *Pages[GeoName] {
+	const defaultMaxRows = 200
+
+	return newPages(func(startRow, maxRows uint32) ([]GeoName, *int, error) {
+		var res struct {
+			Items []GeoName `json:"geonames"`
+			Total *int      `json:"totalResultsCount"`
+		}
+
+		req.StartRow, req.MaxRows = startRow, maxRows
+
+		err := c.apiRequest(
+			ctx,
+			pathChildren,
+			req,
+			&res,
+		)
+
+		return res.Items, res.Total, err
+	}, req.StartRow, req.MaxRows, defaultMaxRows, opts)
+}
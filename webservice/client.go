@@ -1,15 +1,20 @@
 package webservice
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/platx/geonames/middleware"
 	"github.com/platx/geonames/value"
 )
 
@@ -23,9 +28,19 @@ type httpDoer interface {
 }
 
 type Client struct {
-	httpClient httpDoer
-	baseURL    string
-	userName   string
+	httpClient  httpDoer
+	baseURL     string
+	fallbacks   []string
+	userName    string
+	token       string
+	usernames   *UsernamePool
+	retry       RetryPolicy
+	breakers    *breakerGroup
+	quota       *accountant
+	cache       Cache
+	cachePolicy CachePolicy
+	flights     *flightGroup
+	middlewares []middleware.Middleware
 }
 
 type Option func(*Client)
@@ -38,15 +53,51 @@ func WithHTTPClient(httpClient httpDoer) Option {
 
 func WithBaseURL(baseURL string) Option {
 	return func(client *Client) {
-		baseURL = strings.TrimSpace(baseURL)
-		if idx := strings.Index(baseURL, "?"); idx != -1 {
-			baseURL = baseURL[:idx]
+		client.baseURL = normalizeBaseURL(baseURL)
+	}
+}
+
+// WithServers sets the base URLs by priority, like a premium server followed by https://secure.geonames.org.
+// A request failing on a server with a network error or a transient error response is sent to the next one.
+func WithServers(baseURLs ...string) Option {
+	return func(client *Client) {
+		if len(baseURLs) == 0 {
+			return
+		}
+
+		client.baseURL = normalizeBaseURL(baseURLs[0])
+		client.fallbacks = make([]string, 0, len(baseURLs)-1)
+
+		for _, baseURL := range baseURLs[1:] {
+			client.fallbacks = append(client.fallbacks, normalizeBaseURL(baseURL))
 		}
+	}
+}
 
-		client.baseURL = strings.TrimSuffix(baseURL, "/")
+// WithMiddleware adds middlewares intercepting every call, the first one is the outermost.
+// The Call holds the request struct and the endpoint name like "search".
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(client *Client) {
+		client.middlewares = append(client.middlewares, middlewares...)
+	}
+}
+
+// WithToken sets the token of the premium webservice, sent alongside the username.
+func WithToken(token string) Option {
+	return func(client *Client) {
+		client.token = token
 	}
 }
 
+func normalizeBaseURL(baseURL string) string {
+	baseURL = strings.TrimSpace(baseURL)
+	if idx := strings.Index(baseURL, "?"); idx != -1 {
+		baseURL = baseURL[:idx]
+	}
+
+	return strings.TrimSuffix(baseURL, "/")
+}
+
 func NewClient(userName string, opts ...Option) *Client {
 	res := &Client{
 		httpClient: &http.Client{
@@ -55,8 +106,18 @@ func NewClient(userName string, opts ...Option) *Client {
 			Jar:           nil,
 			Timeout:       defaultRequestTimeout,
 		},
-		baseURL:  defaultBaseURL,
-		userName: userName,
+		baseURL:     defaultBaseURL,
+		fallbacks:   nil,
+		userName:    userName,
+		token:       "",
+		usernames:   nil,
+		retry:       RetryPolicy{MaxAttempts: 1, BaseDelay: 0, MaxDelay: 0, Jitter: 0},
+		breakers:    nil,
+		quota:       nil,
+		cache:       nil,
+		cachePolicy: CachePolicy{TTL: 0, Endpoints: nil},
+		flights:     newFlightGroup(),
+		middlewares: nil,
 	}
 
 	for _, opt := range opts {
@@ -66,55 +127,253 @@ func NewClient(userName string, opts ...Option) *Client {
 	return res
 }
 
+// apiRequest sends the request through the middlewares and decodes the JSON response into the destination.
 func (c *Client) apiRequest(ctx context.Context, path string, req any, destination any) error {
-	httpReq, err := c.createHTTPRequest(ctx, path, req)
+	return c.request(ctx, path, req, func(body []byte) error {
+		return c.decodeJSON(bytes.NewReader(body), destination)
+	})
+}
+
+// request sends the request through the middlewares and decodes the response body.
+func (c *Client) request(ctx context.Context, path string, req any, decode func(body []byte) error) error {
+	call := &middleware.Call{
+		Endpoint: endpointName(path),
+		Request:  req,
+		Status:   0,
+		ErrCode:  0,
+		Cached:   false,
+		Duration: 0,
+	}
+
+	return middleware.Chain(c.handler(path, decode), c.middlewares...)(ctx, call)
+}
+
+// handler returns the innermost Handler of the call. Responses are served from the Cache if configured,
+// transient failures are retried according to the RetryPolicy. Only responses which are decoded are cached.
+func (c *Client) handler(path string, decode func(body []byte) error) middleware.Handler {
+	return func(ctx context.Context, call *middleware.Call) error {
+		start := time.Now()
+
+		query := url.Values{}
+
+		NewURLEncoder(query).Encode(call.Request)
+
+		body, err := c.fetch(ctx, path, query, call)
+
+		call.Duration = time.Since(start)
+
+		var respErr *ResponseError
+
+		switch {
+		case err == nil:
+			call.Status = http.StatusOK
+		case errors.As(err, &respErr):
+			call.Status = respErr.StatusCode()
+			call.ErrCode = respErr.Code()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = decode(body); err != nil {
+			return fmt.Errorf("decode response => %w", err)
+		}
+
+		if ttl := c.cachePolicy.ttl(path); c.cache != nil && !call.Cached && ttl > 0 {
+			c.cache.Set(c.cacheKey(path, query), body, ttl)
+		}
+
+		return nil
+	}
+}
+
+// endpointName returns the name of the endpoint of the path, "search" for "/searchJSON".
+func endpointName(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(path, "/"), "JSON")
+}
+
+// cacheKey returns the key of the response in the Cache, the URL without credentials.
+func (c *Client) cacheKey(path string, query url.Values) string {
+	return c.url(path) + "?" + query.Encode()
+}
+
+// fetch returns the body of the response, concurrent identical requests share one call if the Cache is set.
+// The call is marked as cached if the response was not received by its own request.
+func (c *Client) fetch(ctx context.Context, path string, query url.Values, call *middleware.Call) ([]byte, error) {
+	if c.cache == nil {
+		return c.retrying(ctx, path, query)
+	}
+
+	key := c.cacheKey(path, query)
+
+	if body, ok := c.cache.Get(key); ok {
+		call.Cached = true
+
+		return body, nil
+	}
+
+	body, shared, err := c.flights.do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		return c.retrying(ctx, path, query)
+	})
+
+	call.Cached = shared
+
+	return body, err
+}
+
+// retrying sends the request until it succeeds or the RetryPolicy gives up.
+func (c *Client) retrying(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := c.attempt(ctx, path, query)
+		if err == nil {
+			return body, nil
+		}
+
+		delay, ok := c.retry.delay(attempt, err)
+		if !ok || ctx.Err() != nil {
+			return nil, err
+		}
+
+		if err = wait(ctx, delay); err != nil {
+			return nil, fmt.Errorf("wait for retry => %w", err)
+		}
+	}
+}
+
+// attempt sends the request to the servers by priority until one succeeds or fails permanently. A request
+// failing with an exceeded credit limit is sent again with another username of the pool.
+func (c *Client) attempt(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	var (
+		body []byte
+		err  error
+	)
+
+	for _, baseURL := range append([]string{c.baseURL}, c.fallbacks...) {
+		body, err = c.try(ctx, baseURL, path, query)
+		for c.usernames.rotate(err) {
+			body, err = c.try(ctx, baseURL, path, query)
+		}
+
+		if err == nil || !canFailover(err) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return body, err
+}
+
+// canFailover reports whether the request may succeed on another server, after a network error, a transient
+// error response or an open circuit of the server.
+func canFailover(err error) bool {
+	var netErr net.Error
+
+	return isRetryable(err) || errors.As(err, &netErr) || errors.Is(err, ErrCircuitOpen)
+}
+
+// try sends the request to the server.
+func (c *Client) try(ctx context.Context, baseURL, path string, query url.Values) ([]byte, error) {
+	username := c.userName
+
+	if c.usernames != nil {
+		var err error
+		if username, err = c.usernames.acquire(); err != nil {
+			return nil, fmt.Errorf("acquire username => %w", err)
+		}
+	}
+
+	httpReq, err := c.createHTTPRequest(ctx, baseURL+path, username, query)
 	if err != nil {
-		return fmt.Errorf("create http request => %w", err)
+		return nil, fmt.Errorf("create http request => %w", err)
 	}
 
+	credits, err := c.quota.acquire(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("acquire credits => %w", err)
+	}
+
+	breaker := c.breakers.of(baseURL)
+
+	trial, err := breaker.allow()
+	if err != nil {
+		c.quota.refund(path, credits)
+
+		return nil, fmt.Errorf("send http request => %w", err)
+	}
+
+	body, err := c.send(httpReq)
+
+	breaker.done(ctx, trial, err)
+
+	if c.usernames != nil {
+		c.usernames.done(username, c.quota.credits(path), err)
+	}
+
+	return body, err
+}
+
+func (c *Client) send(httpReq *http.Request) ([]byte, error) {
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("send http request => %w", err)
+		return nil, fmt.Errorf("send http request => %w", redact(err))
 	}
 
 	defer func() {
 		_ = httpResp.Body.Close()
 	}()
 
-	if err := c.decodeResponse(httpResp, destination); err != nil {
-		return fmt.Errorf("decode response => %w", err)
+	body, err := c.readResponse(httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("decode response => %w", err)
 	}
 
-	return nil
+	return body, nil
 }
 
-func (c *Client) createHTTPRequest(ctx context.Context, path string, req any) (*http.Request, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(path), nil)
+func (c *Client) createHTTPRequest(
+	ctx context.Context,
+	rawURL, username string,
+	query url.Values,
+) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	urlValues := url.Values{}
+	urlValues := maps.Clone(query)
+
+	urlValues.Set("username", username)
 
-	NewURLEncoder(urlValues).Encode(req)
+	if c.token != "" {
+		urlValues.Set("token", c.token)
+	}
 
-	urlValues.Set("username", c.userName)
 	httpReq.URL.RawQuery = urlValues.Encode()
 
 	return httpReq, nil
 }
 
-func (c *Client) decodeResponse(httpRes *http.Response, destination any) error {
-	if httpRes.StatusCode != http.StatusOK {
-		var errResp errorResponse
-		if err := c.decodeJSON(httpRes.Body, &errResp); err != nil {
-			return err
-		}
+// readResponse returns the body of the response, unless it is a status envelope. GeoNames answers most
+// errors with HTTP 200, so the envelope is checked for every status code.
+func (c *Client) readResponse(httpRes *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var errResp errorResponse
+
+	// a body which is no status envelope is decoded into the destination by the caller, other status codes are
+	// reported as response errors whatever their body is, e.g. empty or an error page of a proxy
+	if err = c.decodeJSON(bytes.NewReader(body), &errResp); err != nil {
+		errResp.Status = nil
+	}
 
-		return &ResponseError{code: errResp.Status.Value, message: errResp.Status.Message}
+	if errResp.Status != nil || httpRes.StatusCode != http.StatusOK {
+		return nil, newResponseError(httpRes, errResp.Status)
 	}
 
-	return c.decodeJSON(httpRes.Body, destination)
+	return body, nil
 }
 
 func (c *Client) url(path string) string {
@@ -126,8 +385,10 @@ func (c *Client) decodeJSON(r io.Reader, v any) error {
 }
 
 type errorResponse struct {
-	Status struct {
-		Message string        `json:"message"`
-		Value   value.ErrCode `json:"value"`
-	} `json:"status"`
+	Status *errorStatus `json:"status"`
+}
+
+type errorStatus struct {
+	Message string        `json:"message"`
+	Value   value.ErrCode `json:"value"`
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
@@ -14,7 +15,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/platx/geonames/middleware"
 	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
 	"github.com/platx/geonames/webservice/testdata"
 )
 
@@ -168,6 +171,46 @@ func Test_Client_apiRequest(t *testing.T) {
 				err: errors.New("decode response => got error response => code: 10, message: \"user does not exist.\""),
 			},
 		},
+		{
+			name: "error response with status ok",
+			deps: deps{
+				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+					m.On("Do", mock.Anything).Return(&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       testutil.MustOpen(testdata.FS, "authorization_error.json"),
+					})
+				}),
+				userName: "test-user",
+			},
+			args: args[testRequest]{
+				ctx: context.Background(),
+				req: testRequest{},
+			},
+			exp: exp[testResult]{
+				res: testResult{},
+				err: errors.New("decode response => got error response => code: 10, message: \"user does not exist.\""),
+			},
+		},
+		{
+			name: "error status without envelope",
+			deps: deps{
+				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
+					m.On("Do", mock.Anything).Return(&http.Response{
+						StatusCode: http.StatusBadGateway,
+						Body:       io.NopCloser(strings.NewReader(`{}`)),
+					})
+				}),
+				userName: "test-user",
+			},
+			args: args[testRequest]{
+				ctx: context.Background(),
+				req: testRequest{},
+			},
+			exp: exp[testResult]{
+				res: testResult{},
+				err: errors.New("decode response => got error response => code: 0, message: \"Bad Gateway\""),
+			},
+		},
 		{
 			name: "invalid error response body",
 			deps: deps{
@@ -185,7 +228,7 @@ func Test_Client_apiRequest(t *testing.T) {
 			},
 			exp: exp[testResult]{
 				res: testResult{},
-				err: errors.New("decode response => unexpected EOF"),
+				err: errors.New(`decode response => got error response => code: 0, message: "Not Found"`),
 			},
 		},
 		{
@@ -231,6 +274,234 @@ func Test_Client_apiRequest(t *testing.T) {
 	}
 }
 
+func Test_Client_failover(t *testing.T) {
+	t.Parallel()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		responses []testutil.FakeResponse
+		primary   int
+		err       error
+	}{
+		{
+			name:      "overloaded",
+			responses: []testutil.FakeResponse{testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded")},
+			primary:   1,
+			err:       nil,
+		},
+		{
+			name:      "bad gateway",
+			responses: []testutil.FakeResponse{statusResponse(http.StatusBadGateway, "<html></html>")},
+			primary:   1,
+			err:       nil,
+		},
+		{
+			name:      "authorization",
+			responses: []testutil.FakeResponse{testutil.ErrorResponse(value.ErrCodeAuthorization, "user does not exist.")},
+			primary:   1,
+			err:       ErrAuthorization,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+			primary.Enqueue(pathGet, tt.responses...)
+
+			secondary := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+			client := NewClient("demo", WithServers(primary.URL(), secondary.URL()))
+
+			_, err := client.Get(context.Background(), GetRequest{ID: 1})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.Empty(t, secondary.Requests(pathGet))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, secondary.Requests(pathGet), 1)
+
+			// the primary server is tried first again
+			_, err = client.Get(context.Background(), GetRequest{ID: 1})
+			require.NoError(t, err)
+			assert.Len(t, primary.Requests(pathGet), tt.primary+1)
+		})
+	}
+
+	t.Run("network error", func(t *testing.T) {
+		t.Parallel()
+
+		secondary := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+		client := NewClient("demo", WithServers(closedURL+"/", " "+secondary.URL()))
+
+		assert.Equal(t, []string{secondary.URL()}, client.fallbacks)
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+		assert.Len(t, secondary.Requests(pathGet), 1)
+	})
+
+	t.Run("all servers fail", func(t *testing.T) {
+		t.Parallel()
+
+		secondary := testutil.NewFakeServer(t)
+		secondary.Handle(pathGet, testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+		_, err := NewClient("demo", WithServers(closedURL, secondary.URL())).Get(
+			context.Background(),
+			GetRequest{ID: 1},
+		)
+
+		require.ErrorIs(t, err, ErrServerOverloaded)
+	})
+}
+
+func Test_Client_credentials(t *testing.T) {
+	t.Parallel()
+
+	t.Run("token", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+		_, err := NewClient("premium", WithBaseURL(fake.URL()), WithToken("secret")).Get(
+			context.Background(),
+			GetRequest{ID: 1},
+		)
+		require.NoError(t, err)
+
+		query := fake.Requests(pathGet)[0].URL.Query()
+		assert.Equal(t, "premium", query.Get("username"))
+		assert.Equal(t, "secret", query.Get("token"))
+	})
+
+	t.Run("redacted from errors", func(t *testing.T) {
+		t.Parallel()
+
+		closed := httptest.NewServer(http.NotFoundHandler())
+		baseURL := closed.URL
+		closed.Close()
+
+		_, err := NewClient("premium", WithBaseURL(baseURL), WithToken("secret")).Get(
+			context.Background(),
+			GetRequest{ID: 1},
+		)
+
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "premium")
+		assert.NotContains(t, err.Error(), "secret")
+		assert.Contains(t, err.Error(), "geonameId=1")
+		assert.Contains(t, err.Error(), "username=REDACTED")
+	})
+}
+
+// recordCalls returns a middleware appending the finished calls.
+func recordCalls(calls *[]middleware.Call) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, call *middleware.Call) error {
+			err := next(ctx, call)
+
+			*calls = append(*calls, *call)
+
+			return err
+		}
+	}
+}
+
+func Test_Client_middleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []middleware.Call
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithMiddleware(recordCalls(&calls)))
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		require.Len(t, calls, 1)
+		assert.Equal(t, "get", calls[0].Endpoint)
+		assert.Equal(t, GetRequest{ID: 1}, calls[0].Request)
+		assert.Equal(t, http.StatusOK, calls[0].Status)
+		assert.Zero(t, calls[0].ErrCode)
+		assert.False(t, calls[0].Cached)
+		assert.Positive(t, calls[0].Duration)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []middleware.Call
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue(pathGet, testutil.ErrorResponse(value.ErrCodeRecordNotExist, "record does not exist"))
+
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithMiddleware(recordCalls(&calls)))
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrRecordNotExist)
+
+		require.Len(t, calls, 1)
+		assert.Equal(t, http.StatusOK, calls[0].Status)
+		assert.Equal(t, value.ErrCode(value.ErrCodeRecordNotExist), calls[0].ErrCode)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []middleware.Call
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient(
+			"demo",
+			WithBaseURL(fake.URL()),
+			WithCache(NewMemoryCache(10), DefaultCachePolicy()),
+			WithMiddleware(recordCalls(&calls)),
+		)
+
+		for range 2 {
+			_, err := client.Get(context.Background(), GetRequest{ID: 1})
+			require.NoError(t, err)
+		}
+
+		require.Len(t, calls, 2)
+		assert.False(t, calls[0].Cached)
+		assert.True(t, calls[1].Cached)
+	})
+
+	t.Run("rewritten request", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithMiddleware(
+			func(next middleware.Handler) middleware.Handler {
+				return func(ctx context.Context, call *middleware.Call) error {
+					call.Request = GetRequest{ID: 2}
+
+					return next(ctx, call)
+				}
+			},
+		))
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2", fake.Requests(pathGet)[0].URL.Query().Get("geonameId"))
+	})
+}
+
 type deps struct {
 	httpClient httpDoer
 	userName   string
@@ -13,7 +13,7 @@ type CountryCodeRequest struct {
 	Position value.Position `url:",dive"`
 	// Radius buffer in km for closest country in coastal areas, a positive buffer expands the positive area
 	// whereas a negative buffer reduces it
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// Language of returned 'name' element (the pseudo language code 'local' will return it in local language)
 	Language string `url:"lang"`
 }
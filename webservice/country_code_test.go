@@ -55,7 +55,7 @@ func Test_Client_CountryCode(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:   10,
+					Radius:   value.Ptr(10.0),
 					Language: "en",
 				},
 			},
@@ -72,7 +72,7 @@ func Test_Client_CountryCode(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -83,6 +83,8 @@ func Test_Client_CountryCode(t *testing.T) {
 								given,
 								"/countryCodeJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
@@ -13,7 +13,7 @@ type CountrySubdivisionRequest struct {
 	Position value.Position `url:",dive"`
 	// Radius buffer in km for closest country in coastal areas, a positive buffer expands the positive area
 	// whereas a negative buffer reduces it.
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// Language default= names in local language.
 	Language string `url:"lang"`
 	// Level administrative level (1-5).
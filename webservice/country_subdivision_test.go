@@ -56,7 +56,7 @@ func Test_Client_CountrySubdivision(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:   11,
+					Radius:   value.Ptr(11.0),
 					Level:    1,
 					Language: "en",
 				},
@@ -0,0 +1,124 @@
+package webservice
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const diskCacheExt = ".cache"
+
+var errMissingExpiry = errors.New("missing expiry")
+
+// DiskCache is a Cache storing every entry in a file of its directory, surviving restarts of the process.
+// Expired entries are removed when read or by Prune.
+type DiskCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewDiskCache returns a cache in the directory, which is created if missing.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create cache directory => %w", err)
+	}
+
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns the body of the key, a file which can not be read is a miss.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+
+	body, expires, err := readCacheFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	if !c.now().Before(expires) {
+		_ = os.Remove(path)
+
+		return nil, false
+	}
+
+	return body, true
+}
+
+// Set writes the entry to a temporary file renamed to the one of the key, failures are ignored.
+func (c *DiskCache) Set(key string, body []byte, ttl time.Duration) {
+	file, err := os.CreateTemp(c.dir, ".*.tmp")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	header := strconv.FormatInt(c.now().Add(ttl).UnixNano(), 10) + "\n"
+
+	_, err = file.WriteString(header)
+	if err == nil {
+		_, err = file.Write(body)
+	}
+
+	if closeErr := file.Close(); err != nil || closeErr != nil {
+		return
+	}
+
+	_ = os.Rename(file.Name(), c.path(key))
+}
+
+// Prune removes the expired entries.
+func (c *DiskCache) Prune() error {
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*"+diskCacheExt))
+	if err != nil {
+		return fmt.Errorf("list cache files => %w", err)
+	}
+
+	for _, path := range paths {
+		_, expires, err := readCacheFile(path)
+		if err == nil && c.now().Before(expires) {
+			continue
+		}
+
+		if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove cache file => %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+diskCacheExt)
+}
+
+// readCacheFile returns the body and the expiry of a cache file, which starts with a line of the expiry
+// in Unix nanoseconds.
+func readCacheFile(path string) ([]byte, time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	header, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, time.Time{}, errMissingExpiry
+	}
+
+	expires, err := strconv.ParseInt(string(header), 10, 64)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse expiry => %w", err)
+	}
+
+	return body, time.Unix(0, expires), nil
+}
@@ -0,0 +1,90 @@
+package webservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiskCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("persists", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "cache")
+
+		cache, err := NewDiskCache(dir)
+		require.NoError(t, err)
+
+		cache.Set("a", []byte(`{"a":1}`), time.Hour)
+
+		reopened, err := NewDiskCache(dir)
+		require.NoError(t, err)
+
+		body, ok := reopened.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte(`{"a":1}`), body)
+
+		_, ok = reopened.Get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("expiry and prune", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		dir := t.TempDir()
+
+		cache, err := NewDiskCache(dir)
+		require.NoError(t, err)
+
+		cache.now = func() time.Time { return now }
+
+		cache.Set("a", []byte("1"), time.Minute)
+		cache.Set("b", []byte("2"), time.Minute)
+		cache.Set("c", []byte("3"), time.Hour)
+
+		now = now.Add(time.Minute)
+
+		_, ok := cache.Get("a")
+		assert.False(t, ok)
+
+		require.NoError(t, cache.Prune())
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, filepath.Base(cache.path("c")), files[0].Name())
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := NewDiskCache(t.TempDir())
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(cache.path("a"), []byte("corrupt"), 0o600))
+
+		_, ok := cache.Get("a")
+		assert.False(t, ok)
+
+		require.NoError(t, cache.Prune())
+		assert.NoFileExists(t, cache.path("a"))
+	})
+
+	t.Run("invalid directory", func(t *testing.T) {
+		t.Parallel()
+
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+		_, err := NewDiskCache(file)
+
+		require.ErrorContains(t, err, "create cache directory")
+	})
+}
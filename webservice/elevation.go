@@ -0,0 +1,157 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/platx/geonames/value"
+)
+
+// MaxElevationPoints is the maximal number of positions of one elevation request.
+const MaxElevationPoints = 20
+
+// Plain text endpoints of the elevation models, they accept comma separated lats and lngs and answer one
+// elevation per line in the order of the positions.
+const (
+	pathSRTM1Text     = "/srtm1"
+	pathSRTM3Text     = "/srtm3"
+	pathAstergdemText = "/astergdem"
+	pathGTOPO30Text   = "/gtopo30"
+)
+
+// ErrElevationMismatch is returned if a response has not one elevation per requested position.
+var ErrElevationMismatch = errors.New("elevation count mismatch")
+
+// SRTM1Batch returns the srtm1 elevations of the positions in their order, see SRTM1 and elevationBatch.
+func (c *Client) SRTM1Batch(
+	ctx context.Context,
+	positions []value.Position,
+	opts ...BatchOption,
+) ([]value.Elevation, error) {
+	return c.elevationBatch(ctx, pathSRTM1Text, value.NoDataSRTM, positions, opts)
+}
+
+// SRTM3Batch returns the srtm3 elevations of the positions in their order, see SRTM3 and elevationBatch.
+func (c *Client) SRTM3Batch(
+	ctx context.Context,
+	positions []value.Position,
+	opts ...BatchOption,
+) ([]value.Elevation, error) {
+	return c.elevationBatch(ctx, pathSRTM3Text, value.NoDataSRTM, positions, opts)
+}
+
+// AstergdemBatch returns the aster gdem elevations of the positions in their order, see Astergdem and
+// elevationBatch.
+func (c *Client) AstergdemBatch(
+	ctx context.Context,
+	positions []value.Position,
+	opts ...BatchOption,
+) ([]value.Elevation, error) {
+	return c.elevationBatch(ctx, pathAstergdemText, value.NoDataAstergdem, positions, opts)
+}
+
+// GTOPO30Batch returns the gtopo30 elevations of the positions in their order, see GTOPO30 and elevationBatch.
+func (c *Client) GTOPO30Batch(
+	ctx context.Context,
+	positions []value.Position,
+	opts ...BatchOption,
+) ([]value.Elevation, error) {
+	return c.elevationBatch(ctx, pathGTOPO30Text, value.NoDataGTOPO30, positions, opts)
+}
+
+// elevationBatch splits the positions into requests of MaxElevationPoints sent concurrently like a Batch,
+// WithBatchSize raises the size for the premium webservice. The noData marker of the model is
+// value.NoElevation. The first failed request fails the whole batch, WithBatchStore keeps the completed ones
+// for a retry.
+func (c *Client) elevationBatch(
+	ctx context.Context,
+	path string,
+	noData int32,
+	positions []value.Position,
+	opts []BatchOption,
+) ([]value.Elevation, error) {
+	cfg := newBatchConfig(opts)
+
+	requests := func(yield func(elevationRequest) bool) {
+		for chunk := range slices.Chunk(positions, cfg.size) {
+			if !yield(elevationRequest{positions: chunk}) {
+				return
+			}
+		}
+	}
+
+	method := func(c *Client, ctx context.Context, req elevationRequest) ([]value.Elevation, error) {
+		return c.elevations(ctx, path, noData, req)
+	}
+
+	res := make([]value.Elevation, 0, len(positions))
+
+	for _, item := range Batch(ctx, c, requests, method, opts...) {
+		if item.Err != nil {
+			return nil, item.Err
+		}
+
+		res = append(res, item.Value...)
+	}
+
+	return res, nil
+}
+
+// elevations sends one request of the positions and parses the elevation lines of the response.
+func (c *Client) elevations(
+	ctx context.Context,
+	path string,
+	noData int32,
+	req elevationRequest,
+) ([]value.Elevation, error) {
+	var res []value.Elevation
+
+	err := c.request(ctx, path, req, func(body []byte) error {
+		lines := strings.Fields(string(body))
+
+		if len(lines) != len(req.positions) {
+			return fmt.Errorf("%w => got %d for %d positions", ErrElevationMismatch, len(lines), len(req.positions))
+		}
+
+		res = make([]value.Elevation, 0, len(lines))
+
+		for _, line := range lines {
+			meters, err := strconv.ParseInt(line, 10, 32)
+			if err != nil {
+				return err
+			}
+
+			res = append(res, value.NewElevation(int32(meters), noData))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// elevationRequest is encoded as the comma separated lists lats and lngs.
+type elevationRequest struct {
+	positions []value.Position
+}
+
+func (r elevationRequest) MarshalURLValues(_ string, values url.Values) {
+	lats := make([]string, 0, len(r.positions))
+	lngs := make([]string, 0, len(r.positions))
+
+	for _, position := range r.positions {
+		lats = append(lats, strconv.FormatFloat(position.Latitude, 'f', -1, 64))
+		lngs = append(lngs, strconv.FormatFloat(position.Longitude, 'f', -1, 64))
+	}
+
+	values.Set("lats", strings.Join(lats, ","))
+	values.Set("lngs", strings.Join(lngs, ","))
+}
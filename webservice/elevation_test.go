@@ -0,0 +1,174 @@
+package webservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func Test_Client_elevationBatch(t *testing.T) {
+	t.Parallel()
+
+	positions := []value.Position{
+		{Latitude: 50.01, Longitude: 10.2},
+		{Latitude: 51.01, Longitude: 11.2},
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 45.8326, Longitude: 6.8652},
+		{Latitude: 48.5, Longitude: 2.25},
+	}
+
+	t.Run("models", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name   string
+			path   string
+			method func(*Client, context.Context, []value.Position, ...BatchOption) ([]value.Elevation, error)
+			exp    []value.Elevation
+		}{
+			{
+				name:   "srtm1",
+				path:   pathSRTM1Text,
+				method: (*Client).SRTM1Batch,
+				exp:    []value.Elevation{{Meters: 205, Valid: true}, {Meters: 324, Valid: true}},
+			},
+			{
+				name:   "srtm3",
+				path:   pathSRTM3Text,
+				method: (*Client).SRTM3Batch,
+				exp:    []value.Elevation{{Meters: 206, Valid: true}, {Meters: 323, Valid: true}},
+			},
+			{
+				name:   "astergdem",
+				path:   pathAstergdemText,
+				method: (*Client).AstergdemBatch,
+				exp:    []value.Elevation{{Meters: 192, Valid: true}, {Meters: 316, Valid: true}},
+			},
+			{
+				name:   "gtopo30",
+				path:   pathGTOPO30Text,
+				method: (*Client).GTOPO30Batch,
+				exp:    []value.Elevation{{Meters: 263, Valid: true}, {Meters: 345, Valid: true}},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+				actual, err := tt.method(NewClient("demo", WithBaseURL(fake.URL())), context.Background(), positions[:2])
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.exp, actual)
+
+				requests := fake.Requests(tt.path)
+				require.Len(t, requests, 1)
+				assert.Equal(t, "50.01,51.01", requests[0].URL.Query().Get("lats"))
+				assert.Equal(t, "10.2,11.2", requests[0].URL.Query().Get("lngs"))
+			})
+		}
+	})
+
+	t.Run("split into requests", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Enqueue(
+			pathSRTM3Text,
+			testutil.TextResponse([]byte("206\r\n323\r\n")),
+			testutil.TextResponse([]byte("-32768\r\n4759\r\n")),
+			testutil.TextResponse([]byte("35\r\n")),
+		)
+
+		actual, err := NewClient("demo", WithBaseURL(fake.URL())).SRTM3Batch(
+			context.Background(),
+			positions,
+			WithBatchSize(2),
+			WithConcurrency(1),
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, []value.Elevation{
+			{Meters: 206, Valid: true},
+			{Meters: 323, Valid: true},
+			value.NoElevation,
+			{Meters: 4759, Valid: true},
+			{Meters: 35, Valid: true},
+		}, actual)
+
+		requests := fake.Requests(pathSRTM3Text)
+		require.Len(t, requests, 3)
+		assert.Equal(t, "0,45.8326", requests[1].URL.Query().Get("lats"))
+		assert.Equal(t, "48.5", requests[2].URL.Query().Get("lats"))
+	})
+
+	t.Run("no data of the model", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle(pathGTOPO30Text, testutil.TextResponse([]byte("-9999\r\n-32768\r\n")))
+
+		actual, err := NewClient("demo", WithBaseURL(fake.URL())).GTOPO30Batch(context.Background(), positions[:2])
+
+		require.NoError(t, err)
+		assert.Equal(t, []value.Elevation{value.NoElevation, {Meters: -32768, Valid: true}}, actual)
+	})
+
+	t.Run("count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle(pathSRTM3Text, testutil.TextResponse([]byte("206\r\n")))
+
+		_, err := NewClient("demo", WithBaseURL(fake.URL())).SRTM3Batch(context.Background(), positions[:2])
+
+		require.ErrorIs(t, err, ErrElevationMismatch)
+	})
+
+	t.Run("invalid line is not stored", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Enqueue(pathSRTM3Text, testutil.TextResponse([]byte("206\r\nnone\r\n")))
+		fake.Handle(pathSRTM3Text, testutil.TextResponse([]byte("206\r\n323\r\n")))
+
+		client := NewClient("demo", WithBaseURL(fake.URL()))
+		store := WithBatchStore(NewMemoryCache(0))
+
+		_, err := client.SRTM3Batch(context.Background(), positions[:2], store)
+		require.ErrorContains(t, err, `decode response => strconv.ParseInt: parsing "none"`)
+
+		actual, err := client.SRTM3Batch(context.Background(), positions[:2], store)
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+		assert.Len(t, fake.Requests(pathSRTM3Text), 2)
+	})
+
+	t.Run("failed request", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle(pathSRTM3Text, testutil.ErrorResponse(value.ErrCodeInvalidParameter, "invalid lat/lng"))
+
+		actual, err := NewClient("demo", WithBaseURL(fake.URL())).SRTM3Batch(context.Background(), positions)
+
+		require.ErrorIs(t, err, ErrInvalidParameter)
+		assert.Nil(t, actual)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := NewClient("demo").SRTM3Batch(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
@@ -4,10 +4,18 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// URLValueMarshaler is implemented by types encoding themselves into url.Values. The key is the name of the url
+// tag of the field, empty for `,dive` fields and the request itself.
+type URLValueMarshaler interface {
+	MarshalURLValues(key string, values url.Values)
+}
+
 // URLEncoder is a struct that can encode a struct into url.Values.
+// Zero values are not encoded, except for non-nil pointer fields which tell an explicit zero from an unset value.
 type URLEncoder struct {
 	values url.Values
 }
@@ -18,6 +26,12 @@ func NewURLEncoder(values url.Values) *URLEncoder {
 
 // Encode encodes a struct into url.Values using reflection.
 func (enc *URLEncoder) Encode(v any) {
+	if marshaler, ok := v.(URLValueMarshaler); ok {
+		marshaler.MarshalURLValues("", enc.values)
+
+		return
+	}
+
 	val := reflect.ValueOf(v)
 	valType := reflect.TypeOf(v)
 
@@ -30,29 +44,61 @@ func (enc *URLEncoder) Encode(v any) {
 			continue
 		}
 
-		if field.Type.Kind() == reflect.Struct && key == ",dive" {
+		name, opts, _ := strings.Cut(key, ",")
+
+		if enc.marshal(name, value) {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && opts == "dive" {
 			enc.Encode(value.Interface())
 
 			continue
 		}
 
-		enc.encodeValue(key, value)
+		if field.Type.Kind() == reflect.Pointer {
+			if !value.IsNil() && !enc.marshal(name, value.Elem()) {
+				enc.encodeValue(name, value.Elem())
+			}
+
+			continue
+		}
+
+		if !value.IsZero() {
+			enc.encodeValue(name, value)
+		}
 	}
 }
 
+// marshal encodes the value with its URLValueMarshaler implementation, it reports false if there is none.
+func (enc *URLEncoder) marshal(key string, value reflect.Value) bool {
+	if value.Kind() == reflect.Pointer && value.IsNil() {
+		return false
+	}
+
+	marshaler, ok := value.Interface().(URLValueMarshaler)
+	if !ok {
+		return false
+	}
+
+	marshaler.MarshalURLValues(key, enc.values)
+
+	return true
+}
+
 // encodeValue encodes a value into url.Values using reflection type switch.
 func (enc *URLEncoder) encodeValue(key string, value reflect.Value) {
 	switch value.Kind() {
 	case reflect.String:
-		enc.encodeString(key, value)
+		enc.values.Set(key, value.String())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		enc.encodeInt(key, value)
+		enc.values.Set(key, strconv.FormatInt(value.Int(), 10))
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		enc.encodeUint(key, value)
+		enc.values.Set(key, strconv.FormatUint(value.Uint(), 10))
 	case reflect.Float32, reflect.Float64:
-		enc.encodeFloat(key, value)
+		enc.values.Set(key, strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()))
 	case reflect.Bool:
-		enc.encodeBool(key, value)
+		enc.values.Set(key, strconv.FormatBool(value.Bool()))
 	case reflect.Slice:
 		enc.encodeSlice(key, value)
 	case reflect.Struct:
@@ -75,42 +121,7 @@ func (enc *URLEncoder) encodeValue(key string, value reflect.Value) {
 	}
 }
 
-// encodeString encodes a string value into url.Values. Empty string is not encoded.
-func (enc *URLEncoder) encodeString(key string, value reflect.Value) {
-	if value.String() != "" {
-		enc.values.Set(key, value.String())
-	}
-}
-
-// encodeInt encodes a signed integer value into url.Values. Zero value is not encoded.
-func (enc *URLEncoder) encodeInt(key string, value reflect.Value) {
-	if value.Int() != 0 {
-		enc.values.Set(key, strconv.FormatInt(value.Int(), 10))
-	}
-}
-
-// encodeUint encodes an unsigned integer value into url.Values. Zero value is not encoded.
-func (enc *URLEncoder) encodeUint(key string, value reflect.Value) {
-	if value.Uint() != 0 {
-		enc.values.Set(key, strconv.FormatUint(value.Uint(), 10))
-	}
-}
-
-// encodeFloat encodes a float value into url.Values. Zero value is not encoded.
-func (enc *URLEncoder) encodeFloat(key string, value reflect.Value) {
-	if value.Float() != 0 {
-		enc.values.Set(key, strconv.FormatFloat(value.Float(), 'f', -1, 32))
-	}
-}
-
-// encodeBool encodes a boolean value into url.Values. False value is not encoded.
-func (enc *URLEncoder) encodeBool(key string, value reflect.Value) {
-	if value.Bool() {
-		enc.values.Set(key, "true")
-	}
-}
-
-// encodeBool encodes a slice value into url.Values.
+// encodeSlice encodes a slice value into url.Values, one value per item.
 func (enc *URLEncoder) encodeSlice(key string, value reflect.Value) {
 	for j := range value.Len() {
 		item := value.Index(j)
@@ -118,12 +129,12 @@ func (enc *URLEncoder) encodeSlice(key string, value reflect.Value) {
 	}
 }
 
-// encodeTime encodes a time object into url.Values. Empty time is not encoded.
+// encodeTime encodes a time object into url.Values as date.
 func (enc *URLEncoder) encodeTime(key string, value reflect.Value) {
 	casted, ok := value.Interface().(time.Time)
-	if casted.IsZero() || !ok {
+	if !ok {
 		return
 	}
 
-	enc.encodeString(key, reflect.ValueOf(casted.Format(time.DateOnly)))
+	enc.values.Set(key, casted.Format(time.DateOnly))
 }
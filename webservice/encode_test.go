@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/value"
 )
 
 func Test_URLEncoder_Encode(t *testing.T) {
@@ -53,6 +55,94 @@ func Test_URLEncoder_Encode_EmptyValues(t *testing.T) {
 	require.Empty(t, values)
 }
 
+func Test_URLEncoder_Encode_FullPrecision(t *testing.T) {
+	t.Parallel()
+
+	values := url.Values{}
+
+	NewURLEncoder(values).Encode(FindNearbyRequest{
+		Position: value.Position{Latitude: 51.5073509, Longitude: -0.1277583},
+		Radius:   value.Ptr(0.25),
+	})
+
+	assert.Equal(t, "51.5073509", values.Get("lat"))
+	assert.Equal(t, "-0.1277583", values.Get("lng"))
+	assert.Equal(t, "0.25", values.Get("radius"))
+}
+
+func Test_URLEncoder_Encode_Pointers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("explicit zero", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{}
+
+		NewURLEncoder(values).Encode(pointerStruct{Float: value.Ptr[float32](0), Marshaler: &marshalerStruct{}})
+
+		assert.Equal(t, url.Values{"float": {"0"}, "marshaler": {"marshaled"}}, values)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{}
+
+		NewURLEncoder(values).Encode(pointerStruct{Float: nil, Marshaler: nil})
+
+		assert.Empty(t, values)
+	})
+}
+
+func Test_URLEncoder_Encode_Marshaler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero position", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{}
+
+		NewURLEncoder(values).Encode(OceanRequest{})
+
+		assert.Equal(t, url.Values{"lat": {"0"}, "lng": {"0"}}, values)
+	})
+
+	t.Run("zero bounding box", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{}
+
+		NewURLEncoder(values).Encode(SearchRequest{Name: "paris"})
+
+		assert.Equal(t, url.Values{"name": {"paris"}}, values)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{}
+
+		NewURLEncoder(values).Encode(marshalerStruct{})
+
+		assert.Equal(t, url.Values{"request": {"marshaled"}}, values)
+	})
+}
+
+type pointerStruct struct {
+	Float     *float32         `url:"float"`
+	Marshaler *marshalerStruct `url:"marshaler"`
+}
+
+type marshalerStruct struct{}
+
+func (marshalerStruct) MarshalURLValues(key string, values url.Values) {
+	if key == "" {
+		key = "request"
+	}
+
+	values.Set(key, "marshaled")
+}
+
 type testStruct struct {
 	StringField   string     `url:"string_field"`
 	IntField      int        `url:"int_field"`
@@ -1,14 +1,66 @@
 package webservice
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/platx/geonames/value"
 )
 
+// Sentinel errors of the webservice error codes, a ResponseError matches the one of its code with errors.Is.
+// [More info]: https://www.geonames.org/export/webservice-exception.html
+var (
+	ErrAuthorization         = errors.New("authorization exception")
+	ErrRecordNotExist        = errors.New("record does not exist")
+	ErrOther                 = errors.New("other error")
+	ErrDatabaseTimeout       = errors.New("database timeout")
+	ErrInvalidParameter      = errors.New("invalid parameter")
+	ErrNoResultFound         = errors.New("no result found")
+	ErrDuplicate             = errors.New("duplicate exception")
+	ErrPostalCodeNotFound    = errors.New("postal code not found")
+	ErrDailyLimitExceeded    = errors.New("daily limit of credits exceeded")
+	ErrHourlyLimitExceeded   = errors.New("hourly limit of credits exceeded")
+	ErrWeeklyLimitExceeded   = errors.New("weekly limit of credits exceeded")
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrServerOverloaded      = errors.New("server overloaded exception")
+	ErrServiceNotImplemented = errors.New("service not implemented")
+	ErrRadiusTooLarge        = errors.New("radius too large")
+	ErrMaxRowsTooLarge       = errors.New("maxRows too large")
+	// ErrQuotaExceeded is matched by the daily, hourly and weekly limit errors.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// ResponseError is the status envelope of the webservice, returned with any HTTP status code.
 type ResponseError struct {
 	code    value.ErrCode
 	message string
+	// statusCode of the HTTP response
+	statusCode int
+	// retryAfter of the Retry-After header, 0 without one
+	retryAfter time.Duration
+}
+
+// newResponseError returns the error of the status envelope, responses with an error status and without
+// envelope get the status text as message.
+func newResponseError(httpRes *http.Response, status *errorStatus) *ResponseError {
+	res := &ResponseError{
+		code:       0,
+		message:    http.StatusText(httpRes.StatusCode),
+		statusCode: httpRes.StatusCode,
+		retryAfter: parseRetryAfter(httpRes.Header.Get("Retry-After"), time.Now()),
+	}
+
+	if status != nil {
+		res.code = status.Value
+		res.message = status.Message
+	}
+
+	return res
 }
 
 func (e *ResponseError) Code() value.ErrCode {
@@ -19,6 +71,16 @@ func (e *ResponseError) Message() string {
 	return e.message
 }
 
+// StatusCode returns the HTTP status code of the response, GeoNames reports most errors with 200.
+func (e *ResponseError) StatusCode() int {
+	return e.statusCode
+}
+
+// RetryAfter returns the delay of the Retry-After header, 0 without one.
+func (e *ResponseError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 func (e *ResponseError) Error() string {
 	return fmt.Sprintf("got error response => code: %d, message: %q", e.Code(), e.Message())
 }
@@ -26,3 +88,135 @@ func (e *ResponseError) Error() string {
 func (e *ResponseError) MatchCode(code value.ErrCode) bool {
 	return e.code == code
 }
+
+// Unwrap returns the sentinel errors of the code, ErrQuotaExceeded included for the credit limits.
+func (e *ResponseError) Unwrap() []error {
+	var res []error
+
+	if err := codeError(e.code); err != nil {
+		res = append(res, err)
+	}
+
+	if e.IsQuota() {
+		res = append(res, ErrQuotaExceeded)
+	}
+
+	return res
+}
+
+// IsQuota reports whether the daily, hourly or weekly limit of credits is exceeded.
+func (e *ResponseError) IsQuota() bool {
+	switch e.code {
+	case value.ErrCodeDailyLimitExceeded, value.ErrCodeHourlyLimitExceeded, value.ErrCodeWeeklyLimitExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether the error is transient and the request may succeed when sent again shortly:
+// database timeouts, overloaded servers and HTTP 429 or 5xx responses without a known code.
+// Exceeded credit limits are not retryable, see IsQuota.
+func (e *ResponseError) IsRetryable() bool {
+	switch e.code {
+	case value.ErrCodeDatabaseTimeout, value.ErrCodeServerOverloaded:
+		return true
+	case 0:
+		return e.statusCode == http.StatusTooManyRequests || e.statusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// codeError returns the sentinel error of the code, nil for unknown codes.
+func codeError(code value.ErrCode) error {
+	switch code {
+	case value.ErrCodeAuthorization:
+		return ErrAuthorization
+	case value.ErrCodeRecordNotExist:
+		return ErrRecordNotExist
+	case value.ErrCodeOther:
+		return ErrOther
+	case value.ErrCodeDatabaseTimeout:
+		return ErrDatabaseTimeout
+	case value.ErrCodeInvalidParameter:
+		return ErrInvalidParameter
+	case value.ErrCodeNoResultFound:
+		return ErrNoResultFound
+	case value.ErrCodeDuplicate:
+		return ErrDuplicate
+	case value.ErrCodePostalCodeNotFound:
+		return ErrPostalCodeNotFound
+	case value.ErrCodeDailyLimitExceeded:
+		return ErrDailyLimitExceeded
+	case value.ErrCodeHourlyLimitExceeded:
+		return ErrHourlyLimitExceeded
+	case value.ErrCodeWeeklyLimitExceeded:
+		return ErrWeeklyLimitExceeded
+	case value.ErrCodeInvalidInput:
+		return ErrInvalidInput
+	case value.ErrCodeServerOverloaded:
+		return ErrServerOverloaded
+	case value.ErrCodeServiceNotImplemented:
+		return ErrServiceNotImplemented
+	case value.ErrCodeRadiusTooLarge:
+		return ErrRadiusTooLarge
+	case value.ErrCodeMaxRowsTooLarge:
+		return ErrMaxRowsTooLarge
+	default:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the delay in seconds or the HTTP date of a Retry-After header, 0 if invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseUint(header, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// redactedParams are the query parameters of the credentials.
+var redactedParams = []string{"username", "token"}
+
+// redact removes the credentials from the URL of an url.Error, which is returned by the HTTP client with the
+// query string of the request.
+func redact(err error) error {
+	var urlErr *url.Error
+
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+
+	return err
+}
+
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		res, _, _ := strings.Cut(rawURL, "?")
+
+		return res
+	}
+
+	query := parsed.Query()
+
+	for _, param := range redactedParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+		}
+	}
+
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
@@ -2,9 +2,15 @@ package webservice
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/platx/geonames/value"
 )
@@ -68,3 +74,159 @@ func Test_error_As_ResponseError(t *testing.T) {
 		assert.False(t, errors.As(source, &target))
 	})
 }
+
+func Test_ResponseError_StatusCode(t *testing.T) {
+	t.Parallel()
+
+	err := &ResponseError{statusCode: http.StatusBadGateway}
+
+	assert.Equal(t, http.StatusBadGateway, err.StatusCode())
+}
+
+func Test_ResponseError_Is(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     value.ErrCode
+		expected []error
+	}{
+		{code: value.ErrCodeAuthorization, expected: []error{ErrAuthorization}},
+		{code: value.ErrCodeRecordNotExist, expected: []error{ErrRecordNotExist}},
+		{code: value.ErrCodeOther, expected: []error{ErrOther}},
+		{code: value.ErrCodeDatabaseTimeout, expected: []error{ErrDatabaseTimeout}},
+		{code: value.ErrCodeInvalidParameter, expected: []error{ErrInvalidParameter}},
+		{code: value.ErrCodeNoResultFound, expected: []error{ErrNoResultFound}},
+		{code: value.ErrCodeDuplicate, expected: []error{ErrDuplicate}},
+		{code: value.ErrCodePostalCodeNotFound, expected: []error{ErrPostalCodeNotFound}},
+		{code: value.ErrCodeDailyLimitExceeded, expected: []error{ErrDailyLimitExceeded, ErrQuotaExceeded}},
+		{code: value.ErrCodeHourlyLimitExceeded, expected: []error{ErrHourlyLimitExceeded, ErrQuotaExceeded}},
+		{code: value.ErrCodeWeeklyLimitExceeded, expected: []error{ErrWeeklyLimitExceeded, ErrQuotaExceeded}},
+		{code: value.ErrCodeInvalidInput, expected: []error{ErrInvalidInput}},
+		{code: value.ErrCodeServerOverloaded, expected: []error{ErrServerOverloaded}},
+		{code: value.ErrCodeServiceNotImplemented, expected: []error{ErrServiceNotImplemented}},
+		{code: value.ErrCodeRadiusTooLarge, expected: []error{ErrRadiusTooLarge}},
+		{code: value.ErrCodeMaxRowsTooLarge, expected: []error{ErrMaxRowsTooLarge}},
+		{code: value.ErrCode(99), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.code)), func(t *testing.T) {
+			t.Parallel()
+
+			err := fmt.Errorf("wrapped => %w", &ResponseError{code: tt.code})
+
+			for _, expected := range tt.expected {
+				require.ErrorIs(t, err, expected)
+			}
+
+			assert.Equal(t, tt.expected, (&ResponseError{code: tt.code}).Unwrap())
+		})
+	}
+
+	assert.NotErrorIs(t, &ResponseError{code: value.ErrCodeNoResultFound}, ErrRecordNotExist)
+}
+
+func Test_ResponseError_IsQuota(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&ResponseError{code: value.ErrCodeDailyLimitExceeded}).IsQuota())
+	assert.True(t, (&ResponseError{code: value.ErrCodeHourlyLimitExceeded}).IsQuota())
+	assert.True(t, (&ResponseError{code: value.ErrCodeWeeklyLimitExceeded}).IsQuota())
+	assert.False(t, (&ResponseError{code: value.ErrCodeServerOverloaded}).IsQuota())
+}
+
+func Test_ResponseError_IsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      *ResponseError
+		expected bool
+	}{
+		{name: "database timeout", err: &ResponseError{code: value.ErrCodeDatabaseTimeout}, expected: true},
+		{name: "server overloaded", err: &ResponseError{code: value.ErrCodeServerOverloaded}, expected: true},
+		{name: "too many requests", err: &ResponseError{statusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "bad gateway", err: &ResponseError{statusCode: http.StatusBadGateway}, expected: true},
+		{name: "not found", err: &ResponseError{statusCode: http.StatusNotFound}, expected: false},
+		{
+			name:     "quota with error status",
+			err:      &ResponseError{code: value.ErrCodeHourlyLimitExceeded, statusCode: http.StatusTooManyRequests},
+			expected: false,
+		},
+		{name: "invalid parameter", err: &ResponseError{code: value.ErrCodeInvalidParameter}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.err.IsRetryable())
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{name: "empty", header: "", expected: 0},
+		{name: "seconds", header: "120", expected: 2 * time.Minute},
+		{name: "date", header: "Mon, 01 Jan 2024 12:00:30 GMT", expected: 30 * time.Second},
+		{name: "past date", header: "Mon, 01 Jan 2024 11:00:00 GMT", expected: 0},
+		{name: "negative", header: "-1", expected: 0},
+		{name: "invalid", header: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, parseRetryAfter(tt.header, now))
+		})
+	}
+}
+
+func Test_redact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name: "url error",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://secure.geonames.org/getJSON?geonameId=1&token=secret&username=demo",
+				Err: assert.AnError,
+			},
+			expected: `Get "https://secure.geonames.org/getJSON?geonameId=1&token=REDACTED&username=REDACTED": ` +
+				assert.AnError.Error(),
+		},
+		{
+			name:     "invalid url",
+			err:      &url.Error{Op: "Get", URL: "%zz?username=demo", Err: assert.AnError},
+			expected: `Get "%zz": ` + assert.AnError.Error(),
+		},
+		{
+			name:     "other error",
+			err:      assert.AnError,
+			expected: assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, redact(tt.err), tt.expected)
+		})
+	}
+}
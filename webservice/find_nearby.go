@@ -16,7 +16,7 @@ type FindNearbyRequest struct {
 	// FeatureCode default is all feature codes
 	FeatureCode []string `url:"featureCode"`
 	// Radius in km the maximal distance in km from the point specified via lat and lng that a result should be found
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// MaxRows the maximal number of rows returned by the service. Default is 10.
 	MaxRows uint32 `url:"maxRows"`
 	// LocalCountry in border areas this parameter will restrict the search on the local country, value=true
@@ -59,7 +59,7 @@ func Test_Client_FindNearbyPlaceName(t *testing.T) {
 						Longitude: -1.111,
 					},
 					Language:     "en",
-					Radius:       10,
+					Radius:       value.Ptr(10.0),
 					MaxRows:      2,
 					LocalCountry: true,
 					Cities:       value.Cities5000,
@@ -140,7 +140,7 @@ func Test_Client_FindNearbyPlaceName(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -151,6 +151,8 @@ func Test_Client_FindNearbyPlaceName(t *testing.T) {
 								given,
 								"/findNearbyPlaceNameJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
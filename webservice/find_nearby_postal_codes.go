@@ -12,7 +12,7 @@ type FindNearbyPostalCodesRequest struct {
 	// Position the latitude and longitude of the search location
 	Position value.Position `url:",dive"`
 	// Radius in km the maximal distance in km from the point specified via lat and lng that a result should be found
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// MaxRows the maximal number of rows returned by the service. Default is 5.
 	MaxRows uint32 `url:"maxRows"`
 	// Country default is all countries
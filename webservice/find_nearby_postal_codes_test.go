@@ -57,7 +57,7 @@ func Test_Client_FindNearbyPostalCodes(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:       10,
+					Radius:       value.Ptr(10.0),
 					MaxRows:      2,
 					Country:      value.CountryCodeUnitedStates,
 					LocalCountry: true,
@@ -114,7 +114,7 @@ func Test_Client_FindNearbyPostalCodes(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -125,6 +125,8 @@ func Test_Client_FindNearbyPostalCodes(t *testing.T) {
 								given,
 								"/findNearbyPostalCodesJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
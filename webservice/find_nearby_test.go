@@ -60,7 +60,7 @@ func Test_Client_FindNearby(t *testing.T) {
 					},
 					FeatureClass: []string{"A"},
 					FeatureCode:  []string{"AAAA", "AAAB"},
-					Radius:       10,
+					Radius:       value.Ptr(10.0),
 					MaxRows:      2,
 					LocalCountry: true,
 				},
@@ -140,7 +140,7 @@ func Test_Client_FindNearby(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -151,6 +151,8 @@ func Test_Client_FindNearby(t *testing.T) {
 								given,
 								"/findNearbyJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
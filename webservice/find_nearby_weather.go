@@ -12,7 +12,7 @@ type FindNearbyWeatherRequest struct {
 	// Position the service will return the station closest to this given point (reverse geocoding).
 	Position value.Position `url:",dive"`
 	// Radius search radius, only weather stations within this radius are considered. Default is about 100km.
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 }
 
 // FindNearbyWeather returns a weather station with the most recent weather observation.
@@ -55,7 +55,7 @@ func Test_Client_FindNearbyWeather(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius: 11,
+					Radius: value.Ptr(11.0),
 				},
 			},
 			exp: exp[WeatherObservationNearby]{
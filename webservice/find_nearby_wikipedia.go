@@ -14,7 +14,7 @@ type FindNearbyWikipediaRequest struct {
 	// Language ISO language code of article text
 	Language string `url:"lang"`
 	// Radius in km the maximal distance in km from the point specified via lat and lng that a result should be found
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// MaxRows the maximal number of rows in the document returned by the service. Default is 5.
 	MaxRows uint32 `url:"maxRows"`
 	// Country default is all countries
@@ -58,7 +58,7 @@ func Test_Client_FindNearbyWikipedia(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius:  10,
+					Radius:  value.Ptr(10.0),
 					MaxRows: 2,
 					Country: []value.CountryCode{value.CountryCodeUnitedKingdom, value.CountryCodeUnitedStates},
 				},
@@ -106,7 +106,7 @@ func Test_Client_FindNearbyWikipedia(t *testing.T) {
 			},
 		},
 		{
-			name: "empty without request values",
+			name: "empty at zero position",
 			deps: deps{
 				httpClient: testutil.MockHTTPClient(func(m *testutil.HTTPClientMock) {
 					m.On(
@@ -117,6 +117,8 @@ func Test_Client_FindNearbyWikipedia(t *testing.T) {
 								given,
 								"/findNearbyWikipediaJSON",
 								url.Values{
+									"lat":      []string{"0"},
+									"lng":      []string{"0"},
 									"username": []string{"test-user"},
 								},
 							)
@@ -0,0 +1,90 @@
+package webservice
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-memory Cache evicting the least recently used entry once its size is reached.
+type MemoryCache struct {
+	size    int
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// recent holds the entries, the most recently used at the front
+	recent *list.List
+}
+
+type memoryEntry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a cache of at most size entries, it is unbounded if size is 0.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		now:     time.Now,
+		mu:      sync.Mutex{},
+		entries: map[string]*list.Element{},
+		recent:  list.New(),
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := elem.Value.(*memoryEntry)
+
+	if !c.now().Before(entry.expires) {
+		c.remove(elem)
+
+		return nil, false
+	}
+
+	c.recent.MoveToFront(elem)
+
+	return entry.body, true
+}
+
+func (c *MemoryCache) Set(key string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryEntry{key: key, body: body, expires: c.now().Add(ttl)}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.recent.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.recent.PushFront(entry)
+
+	if c.size > 0 && c.recent.Len() > c.size {
+		c.remove(c.recent.Back())
+	}
+}
+
+// Len returns the number of entries, expired ones included until they are evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.recent.Len()
+}
+
+func (c *MemoryCache) remove(elem *list.Element) {
+	entry, _ := c.recent.Remove(elem).(*memoryEntry)
+
+	delete(c.entries, entry.key)
+}
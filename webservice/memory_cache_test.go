@@ -0,0 +1,71 @@
+package webservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MemoryCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("least recently used", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewMemoryCache(2)
+
+		cache.Set("a", []byte("1"), time.Hour)
+		cache.Set("b", []byte("2"), time.Hour)
+
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+
+		cache.Set("c", []byte("3"), time.Hour)
+
+		_, ok = cache.Get("b")
+		assert.False(t, ok)
+
+		body, ok := cache.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), body)
+
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewMemoryCache(2)
+
+		cache.Set("a", []byte("1"), time.Hour)
+		cache.Set("a", []byte("2"), time.Hour)
+
+		body, ok := cache.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("2"), body)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		cache := NewMemoryCache(0)
+		cache.now = func() time.Time { return now }
+
+		cache.Set("a", []byte("1"), time.Minute)
+
+		now = now.Add(59 * time.Second)
+
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Second)
+
+		_, ok = cache.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
@@ -12,7 +12,7 @@ type OceanRequest struct {
 	// Position the latitude and longitude of the search location
 	Position value.Position `url:",dive"`
 	// Radius buffer in km
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 }
 
 // Ocean returns the ocean or sea for the given latitude/longitude.
@@ -54,7 +54,7 @@ func Test_Client_Ocean(t *testing.T) {
 						Latitude:  1.111,
 						Longitude: -1.111,
 					},
-					Radius: 10,
+					Radius: value.Ptr(10.0),
 				},
 			},
 			exp: exp[Ocean]{
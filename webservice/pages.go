@@ -0,0 +1,106 @@
+package webservice
+
+import (
+	"iter"
+)
+
+const (
+	// MaxPageSize is the maximal allowed MaxRows of the paged endpoints.
+	MaxPageSize = 1000
+	// StartRowLimitFree is the maximal allowed StartRow of the free webservice.
+	StartRowLimitFree = 5000
+	// StartRowLimitPremium is the maximal allowed StartRow of the premium webservice.
+	StartRowLimitPremium = 25000
+)
+
+// Pages lazily fetches the pages of a paged endpoint while being iterated, see Client.SearchAll.
+// The page size is the MaxRows of the request capped at MaxPageSize, or the default of the endpoint.
+// It is not safe for concurrent use.
+type Pages[T any] struct {
+	// fetch requests a page and returns its items and the total count if the endpoint has one
+	fetch         func(startRow, maxRows uint32) ([]T, *int, error)
+	startRow      uint32
+	pageSize      uint32
+	startRowLimit uint32
+	total         *int
+}
+
+type pagesConfig struct {
+	startRowLimit uint32
+}
+
+type PagesOption func(*pagesConfig)
+
+// WithStartRowLimit sets the maximal StartRow of the requests, default is StartRowLimitFree.
+// Use StartRowLimitPremium for the premium webservice.
+func WithStartRowLimit(limit uint32) PagesOption {
+	return func(c *pagesConfig) {
+		c.startRowLimit = limit
+	}
+}
+
+func newPages[T any](
+	fetch func(startRow, maxRows uint32) ([]T, *int, error),
+	startRow, maxRows, defaultMaxRows uint32,
+	opts []PagesOption,
+) *Pages[T] {
+	cfg := pagesConfig{startRowLimit: StartRowLimitFree}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if maxRows == 0 {
+		maxRows = defaultMaxRows
+	}
+
+	return &Pages[T]{
+		fetch:         fetch,
+		startRow:      startRow,
+		pageSize:      min(maxRows, MaxPageSize),
+		startRowLimit: cfg.startRowLimit,
+		total:         nil,
+	}
+}
+
+// All returns the items from the StartRow of the request. A page is only requested once the items of the
+// previous one are consumed, the iteration ends after the last page, at the StartRow limit or with the first
+// error. Every call of All starts over with the first page.
+func (p *Pages[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for startRow := p.startRow; startRow <= p.startRowLimit; startRow += p.pageSize {
+			items, total, err := p.fetch(startRow, p.pageSize)
+			if err != nil {
+				var zero T
+
+				yield(zero, err)
+
+				return
+			}
+
+			if total != nil {
+				p.total = total
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) < int(p.pageSize) || (p.total != nil && int(startRow+p.pageSize) >= *p.total) {
+				return
+			}
+		}
+	}
+}
+
+// Total returns the totalResultsCount of the last fetched page, which may exceed the items reachable within
+// the StartRow limit. It reports false before the first page and for endpoints without the count.
+func (p *Pages[T]) Total() (int, bool) {
+	if p.total == nil {
+		return 0, false
+	}
+
+	return *p.total, true
+}
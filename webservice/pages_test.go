@@ -0,0 +1,269 @@
+package webservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+// page returns a response with the items of the ids under the key, the total is omitted when negative.
+func page(key string, total int, ids ...int) testutil.FakeResponse {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf(`{"geonameId":%d,"postalCode":"%d","title":"%d"}`, id, id, id))
+	}
+
+	body := fmt.Sprintf(`{%q:[%s]}`, key, strings.Join(items, ","))
+	if total >= 0 {
+		body = fmt.Sprintf(`{"totalResultsCount":%d,%q:[%s]}`, total, key, strings.Join(items, ","))
+	}
+
+	return testutil.JSONResponse([]byte(body))
+}
+
+func startRows(requests []*http.Request) []string {
+	res := make([]string, 0, len(requests))
+	for _, req := range requests {
+		res = append(res, req.URL.Query().Get("startRow"))
+	}
+
+	return res
+}
+
+func collectIDs[T any](t *testing.T, pages *Pages[T], id func(T) uint64) []uint64 {
+	t.Helper()
+
+	var res []uint64
+
+	for item, err := range pages.All() {
+		require.NoError(t, err)
+
+		res = append(res, id(item))
+	}
+
+	return res
+}
+
+func geoNameID(item GeoName) uint64 {
+	return item.ID
+}
+
+func Test_Client_SearchAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all pages", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Enqueue("/searchJSON", page("geonames", 5, 1, 2), page("geonames", 5, 3, 4), page("geonames", 5, 5))
+
+		pages := NewClient("demo", WithBaseURL(fake.URL())).SearchAll(
+			context.Background(),
+			SearchRequest{Name: "foo", MaxRows: 2},
+		)
+
+		_, ok := pages.Total()
+		assert.False(t, ok)
+
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, collectIDs(t, pages, geoNameID))
+
+		total, ok := pages.Total()
+		assert.True(t, ok)
+		assert.Equal(t, 5, total)
+
+		requests := fake.Requests("/searchJSON")
+		assert.Equal(t, []string{"", "2", "4"}, startRows(requests))
+		assert.Equal(t, "foo", requests[2].URL.Query().Get("name"))
+		assert.Equal(t, "2", requests[2].URL.Query().Get("maxRows"))
+	})
+
+	t.Run("start row of the request", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Enqueue("/searchJSON", page("geonames", 12, 11, 12))
+
+		pages := NewClient("demo", WithBaseURL(fake.URL())).SearchAll(
+			context.Background(),
+			SearchRequest{StartRow: 10, MaxRows: 5},
+		)
+
+		assert.Equal(t, []uint64{11, 12}, collectIDs(t, pages, geoNameID))
+		assert.Equal(t, []string{"10"}, startRows(fake.Requests("/searchJSON")))
+	})
+
+	t.Run("stops with the consumer", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle("/searchJSON", page("geonames", 100, 1, 2))
+
+		pages := NewClient("demo", WithBaseURL(fake.URL())).SearchAll(
+			context.Background(),
+			SearchRequest{MaxRows: 2},
+		)
+
+		for item, err := range pages.All() {
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), item.ID)
+
+			break
+		}
+
+		assert.Len(t, fake.Requests("/searchJSON"), 1)
+	})
+
+	t.Run("start row limit", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle("/searchJSON", page("geonames", 100, 1, 2))
+
+		pages := NewClient("demo", WithBaseURL(fake.URL())).SearchAll(
+			context.Background(),
+			SearchRequest{MaxRows: 2},
+			WithStartRowLimit(4),
+		)
+
+		assert.Equal(t, []uint64{1, 2, 1, 2, 1, 2}, collectIDs(t, pages, geoNameID))
+		assert.Equal(t, []string{"", "2", "4"}, startRows(fake.Requests("/searchJSON")))
+	})
+
+	t.Run("page size", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle("/searchJSON", page("geonames", 1, 1))
+
+		client := NewClient("demo", WithBaseURL(fake.URL()))
+
+		for range client.SearchAll(context.Background(), SearchRequest{}).All() {
+		}
+
+		for range client.SearchAll(context.Background(), SearchRequest{MaxRows: 5000}).All() {
+		}
+
+		requests := fake.Requests("/searchJSON")
+
+		require.Len(t, requests, 2)
+		assert.Equal(t, "100", requests[0].URL.Query().Get("maxRows"))
+		assert.Equal(t, "1000", requests[1].URL.Query().Get("maxRows"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		res := testutil.ErrorResponse(value.ErrCodeMaxRowsTooLarge, "maxRows too large")
+		res.Status = http.StatusBadRequest
+
+		fake := testutil.NewFakeServer(t)
+		fake.Enqueue("/searchJSON", page("geonames", 5, 1, 2), res)
+
+		pages := NewClient("demo", WithBaseURL(fake.URL())).SearchAll(
+			context.Background(),
+			SearchRequest{MaxRows: 2},
+		)
+
+		var (
+			ids  []uint64
+			errs []error
+		)
+
+		for item, err := range pages.All() {
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			ids = append(ids, item.ID)
+		}
+
+		assert.Equal(t, []uint64{1, 2}, ids)
+		require.Len(t, errs, 1)
+
+		var respErr *ResponseError
+
+		require.ErrorAs(t, errs[0], &respErr)
+		assert.True(t, respErr.MatchCode(value.ErrCodeMaxRowsTooLarge))
+	})
+}
+
+func Test_Client_PostalCodeSearchAll(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t)
+	fake.Enqueue("/postalCodeSearchJSON", page("postalCodes", -1, 1, 2), page("postalCodes", -1, 3))
+
+	pages := NewClient("demo", WithBaseURL(fake.URL())).PostalCodeSearchAll(
+		context.Background(),
+		PostalCodeSearchRequest{PostalCode: "1", MaxRows: 2},
+	)
+
+	var codes []string
+
+	for item, err := range pages.All() {
+		require.NoError(t, err)
+
+		codes = append(codes, item.Code)
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, codes)
+	assert.Equal(t, []string{"", "2"}, startRows(fake.Requests("/postalCodeSearchJSON")))
+
+	_, ok := pages.Total()
+	assert.False(t, ok)
+}
+
+func Test_Client_WikipediaSearchAll(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t)
+	fake.Enqueue("/wikipediaSearchJSON", page("geonames", -1, 1, 2), page("geonames", -1))
+
+	pages := NewClient("demo", WithBaseURL(fake.URL())).WikipediaSearchAll(
+		context.Background(),
+		WikipediaSearchRequest{Query: "foo", MaxRows: 2},
+	)
+
+	var titles []string
+
+	for item, err := range pages.All() {
+		require.NoError(t, err)
+
+		titles = append(titles, item.Title)
+	}
+
+	assert.Equal(t, []string{"1", "2"}, titles)
+	assert.Equal(t, []string{"", "2"}, startRows(fake.Requests("/wikipediaSearchJSON")))
+}
+
+func Test_Client_ChildrenAll(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t)
+	fake.Enqueue("/childrenJSON", page("geonames", 3, 1, 2), page("geonames", 3, 3))
+
+	pages := NewClient("demo", WithBaseURL(fake.URL())).ChildrenAll(
+		context.Background(),
+		ChildrenRequest{ID: 42, MaxRows: 2},
+	)
+
+	assert.Equal(t, []uint64{1, 2, 3}, collectIDs(t, pages, geoNameID))
+
+	total, ok := pages.Total()
+	assert.True(t, ok)
+	assert.Equal(t, 3, total)
+
+	requests := fake.Requests("/childrenJSON")
+	assert.Equal(t, []string{"", "2"}, startRows(requests))
+	assert.Equal(t, "42", requests[1].URL.Query().Get("geonameId"))
+}
@@ -23,6 +23,8 @@ type PostalCodeSearchRequest struct {
 	CountryBias value.CountryCode `url:"countryBias"`
 	// MaxRows the maximal number of rows in the document returned by the service. Default is 10.
 	MaxRows uint32 `url:"maxRows"`
+	// StartRow used for paging results, see PostalCodeSearchAll
+	StartRow uint32 `url:"startRow"`
 	// Operator default is 'AND', with the operator 'OR' not all search terms need to be matched by the response
 	Operator value.Operator `url:"operator"`
 	// Reduced default is false, when set to true only the UK outer codes respectivel the NL 4-digits are returned.
@@ -51,3 +53,30 @@ func (c *Client) PostalCodeSearch(ctx context.Context, req PostalCodeSearchReque
 
 	return res.Items, err
 }
+
+// PostalCodeSearchAll returns the pages of PostalCodeSearch from the StartRow of the request,
+// the page size is MaxRows or 10. The webservice does not report the total count.
+func (c *Client) PostalCodeSearchAll(
+	ctx context.Context,
+	req PostalCodeSearchRequest,
+	opts ...PagesOption,
+) *Pages[PostalCode] {
+	const defaultMaxRows = 10
+
+	return newPages(func(startRow, maxRows uint32) ([]PostalCode, *int, error) {
+		var res struct {
+			Items []PostalCode `json:"postalCodes"`
+		}
+
+		req.StartRow, req.MaxRows = startRow, maxRows
+
+		err := c.apiRequest(
+			ctx,
+			pathPostalCodeSearch,
+			req,
+			&res,
+		)
+
+		return res.Items, nil, err
+	}, req.StartRow, req.MaxRows, defaultMaxRows, opts)
+}
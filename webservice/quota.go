@@ -0,0 +1,323 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// FreeHourlyCredits is the hourly credit limit of the free webservice.
+	FreeHourlyCredits = 1000
+	// FreeDailyCredits is the daily credit limit of the free webservice.
+	FreeDailyCredits = 10000
+)
+
+// ErrBudgetExceeded is returned without sending the request when its credits exceed the remaining budget.
+var ErrBudgetExceeded = errors.New("credit budget exceeded")
+
+// defaultCredits are the credits of the endpoints costing more than one, see QuotaPolicy.Credits.
+var defaultCredits = map[string]int{
+	pathAddress:              2,
+	pathGeoCodeAddress:       2,
+	pathStreetNameLookup:     2,
+	pathFindNearbyWikipedia:  2,
+	pathWikipediaBoundingBox: 2,
+	pathWikipediaSearch:      2,
+}
+
+// QuotaPolicy configures the credit accounting of the client, see WithQuota.
+type QuotaPolicy struct {
+	// HourlyCredits is the budget of any rolling hour, 0 is unlimited
+	HourlyCredits int
+	// DailyCredits is the budget of any rolling 24 hours, 0 is unlimited
+	DailyCredits int
+	// Wait blocks the requests until the budget allows them instead of failing with ErrBudgetExceeded
+	Wait bool
+	// Credits overrides the credits of the endpoints by path like "/searchJSON", other endpoints cost one
+	Credits map[string]int
+}
+
+// DefaultQuotaPolicy returns a failing fast policy with the budgets of the free webservice.
+func DefaultQuotaPolicy() QuotaPolicy {
+	return QuotaPolicy{
+		HourlyCredits: FreeHourlyCredits,
+		DailyCredits:  FreeDailyCredits,
+		Wait:          false,
+		Credits:       nil,
+	}
+}
+
+// WithQuota charges the credits of every sent request, retries included, against the hourly and daily budget.
+// A request exceeding the remaining budget waits until enough credits are available or fails with
+// ErrBudgetExceeded, depending on QuotaPolicy.Wait. GeoNames enforces the limits per username, so the clients
+// created with the same returned Option share one budget.
+func WithQuota(policy QuotaPolicy) Option {
+	quota := newAccountant(policy)
+
+	return func(client *Client) {
+		client.quota = quota
+	}
+}
+
+// Usage is a snapshot of the credit accounting of a client.
+type Usage struct {
+	// HourlyCredits charged within the last hour
+	HourlyCredits int
+	// HourlyBudget of the policy, 0 is unlimited
+	HourlyBudget int
+	// DailyCredits charged within the last 24 hours
+	DailyCredits int
+	// DailyBudget of the policy, 0 is unlimited
+	DailyBudget int
+	// TotalCredits charged since the creation of the client
+	TotalCredits int
+	// Endpoints are the credits charged since the creation of the client by path
+	Endpoints map[string]int
+	// Rejected is the number of requests failed with ErrBudgetExceeded
+	Rejected int
+}
+
+// Usage returns the credits charged by the client, a zero Usage without WithQuota.
+func (c *Client) Usage() Usage {
+	return c.quota.usage()
+}
+
+// charge is the credits of a request at the time it was sent.
+type charge struct {
+	at      time.Time
+	credits int
+}
+
+// accountant keeps the charges of the last hour and 24 hours. A nil accountant allows every request.
+type accountant struct {
+	policy    QuotaPolicy
+	now       func() time.Time
+	wait      func(ctx context.Context, delay time.Duration) error
+	mu        sync.Mutex
+	hourly    window
+	daily     window
+	total     int
+	endpoints map[string]int
+	rejected  int
+}
+
+// window keeps the time ordered charges of a rolling window with their running total.
+type window struct {
+	length  time.Duration
+	charges []*charge
+	used    int
+}
+
+func newAccountant(policy QuotaPolicy) *accountant {
+	policy.Credits = maps.Clone(policy.Credits)
+
+	return &accountant{
+		policy:    policy,
+		now:       time.Now,
+		wait:      wait,
+		mu:        sync.Mutex{},
+		hourly:    window{length: time.Hour, charges: nil, used: 0},
+		daily:     window{length: 24 * time.Hour, charges: nil, used: 0},
+		total:     0,
+		endpoints: map[string]int{},
+		rejected:  0,
+	}
+}
+
+// credits returns the credits of a request to the endpoint.
+func (a *accountant) credits(path string) int {
+	if a == nil {
+		return endpointCredits(nil, path)
+	}
+
+	return endpointCredits(a.policy.Credits, path)
+}
+
+// endpointCredits returns the credits of a request to the endpoint, the overrides take precedence over
+// the defaults.
+func endpointCredits(overrides map[string]int, path string) int {
+	if credits, ok := overrides[path]; ok {
+		return credits
+	}
+
+	if credits, ok := defaultCredits[path]; ok {
+		return credits
+	}
+
+	return 1
+}
+
+// acquire charges the credits of a request to the endpoint, waiting for the budget if the policy says so.
+// The returned charge is the handle to refund, it has no credits without accountant.
+func (a *accountant) acquire(ctx context.Context, path string) (*charge, error) {
+	if a == nil {
+		return &charge{at: time.Time{}, credits: 0}, nil
+	}
+
+	credits := a.credits(path)
+
+	for {
+		res, delay, err := a.reserve(path, credits)
+		if err != nil || res != nil {
+			return res, err
+		}
+
+		if err = a.wait(ctx, delay); err != nil {
+			return nil, fmt.Errorf("wait for credits => %w", err)
+		}
+	}
+}
+
+// reserve charges the credits if the budget allows them, otherwise it returns the delay until it does.
+func (a *accountant) reserve(path string, credits int) (*charge, time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.prune(now)
+
+	if !a.fits(credits) {
+		a.rejected++
+
+		return nil, 0, fmt.Errorf("%d credits of %s => %w", credits, path, ErrBudgetExceeded)
+	}
+
+	ready := maxTime(
+		a.hourly.available(now, a.policy.HourlyCredits, credits),
+		a.daily.available(now, a.policy.DailyCredits, credits),
+	)
+
+	if ready.After(now) {
+		if !a.policy.Wait {
+			a.rejected++
+
+			return nil, 0, fmt.Errorf("%d credits of %s => %w", credits, path, ErrBudgetExceeded)
+		}
+
+		return nil, ready.Sub(now), nil
+	}
+
+	res := &charge{at: now, credits: credits}
+
+	a.hourly.add(res)
+	a.daily.add(res)
+	a.total += credits
+	a.endpoints[path] += credits
+
+	return res, 0, nil
+}
+
+// fits reports whether the credits fit into the empty budgets.
+func (a *accountant) fits(credits int) bool {
+	return (a.policy.HourlyCredits == 0 || credits <= a.policy.HourlyCredits) &&
+		(a.policy.DailyCredits == 0 || credits <= a.policy.DailyCredits)
+}
+
+// prune drops the charges which left the windows.
+func (a *accountant) prune(now time.Time) {
+	a.hourly.prune(now)
+	a.daily.prune(now)
+}
+
+// refund reverts the charge returned by acquire of the endpoint for a request which was not sent.
+func (a *accountant) refund(path string, c *charge) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.daily.remove(c) {
+		a.hourly.remove(c)
+		a.total -= c.credits
+		a.endpoints[path] -= c.credits
+	}
+}
+
+func (a *accountant) usage() Usage {
+	if a == nil {
+		return Usage{}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.prune(now)
+
+	return Usage{
+		HourlyCredits: a.hourly.used,
+		HourlyBudget:  a.policy.HourlyCredits,
+		DailyCredits:  a.daily.used,
+		DailyBudget:   a.policy.DailyCredits,
+		TotalCredits:  a.total,
+		Endpoints:     maps.Clone(a.endpoints),
+		Rejected:      a.rejected,
+	}
+}
+
+func (w *window) add(c *charge) {
+	w.charges = append(w.charges, c)
+	w.used += c.credits
+}
+
+// prune drops the charges which left the window from the front of the queue.
+func (w *window) prune(now time.Time) {
+	idx := 0
+	for idx < len(w.charges) && !w.charges[idx].at.After(now.Add(-w.length)) {
+		w.used -= w.charges[idx].credits
+		idx++
+	}
+
+	clear(w.charges[:idx])
+	w.charges = w.charges[idx:]
+}
+
+// available returns the time from which the credits fit into the budget, now if they fit already. Only the
+// oldest charges which have to leave the window are visited.
+func (w *window) available(now time.Time, budget, credits int) time.Time {
+	if budget == 0 || w.used+credits <= budget {
+		return now
+	}
+
+	used := w.used
+
+	for _, c := range w.charges {
+		// the credits fit once the charge leaves the window
+		used -= c.credits
+		if used+credits <= budget {
+			return c.at.Add(w.length)
+		}
+	}
+
+	return now
+}
+
+// remove drops the charge and reports whether it was in the window. Refunded charges are recent, so the
+// search starts at the back.
+func (w *window) remove(c *charge) bool {
+	for i := len(w.charges) - 1; i >= 0; i-- {
+		if w.charges[i] == c {
+			w.charges = slices.Delete(w.charges, i, i+1)
+			w.used -= c.credits
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,313 @@
+package webservice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+// testClock is a fake clock advanced by the waits it records.
+type testClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestAccountant(policy QuotaPolicy) (*accountant, *testClock) {
+	clock := &testClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), waits: []time.Duration{}}
+
+	res := newAccountant(policy)
+	res.now = func() time.Time { return clock.now }
+	res.wait = func(_ context.Context, delay time.Duration) error {
+		clock.waits = append(clock.waits, delay)
+		clock.advance(delay)
+
+		return nil
+	}
+
+	return res, clock
+}
+
+// acquire returns the error of the acquired credits.
+func acquire(ctx context.Context, quota *accountant, path string) error {
+	_, err := quota.acquire(ctx, path)
+
+	return err
+}
+
+func Test_accountant_credits(t *testing.T) {
+	t.Parallel()
+
+	quota := newAccountant(QuotaPolicy{Credits: map[string]int{pathGeoNameSearch: 3, pathAddress: 1}})
+
+	assert.Equal(t, 1, quota.credits(pathGet))
+	assert.Equal(t, 2, quota.credits(pathWikipediaSearch))
+	assert.Equal(t, 3, quota.credits(pathGeoNameSearch))
+	assert.Equal(t, 1, quota.credits(pathAddress))
+}
+
+func Test_accountant_acquire(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		quota, clock := newTestAccountant(QuotaPolicy{HourlyCredits: 3, DailyCredits: 5})
+
+		require.NoError(t, acquire(ctx, quota, pathGet))
+		require.NoError(t, acquire(ctx, quota, pathWikipediaSearch))
+		require.ErrorIs(t, acquire(ctx, quota, pathWikipediaSearch), ErrBudgetExceeded)
+
+		// the hourly budget is rolling
+		clock.advance(time.Hour)
+		require.NoError(t, acquire(ctx, quota, pathWikipediaSearch))
+
+		// the daily budget is exhausted
+		clock.advance(time.Hour)
+		require.ErrorIs(t, acquire(ctx, quota, pathGet), ErrBudgetExceeded)
+
+		clock.advance(22 * time.Hour)
+		require.NoError(t, acquire(ctx, quota, pathGet))
+
+		assert.Empty(t, clock.waits)
+		assert.Equal(t, Usage{
+			HourlyCredits: 1,
+			HourlyBudget:  3,
+			DailyCredits:  3,
+			DailyBudget:   5,
+			TotalCredits:  6,
+			Endpoints:     map[string]int{pathGet: 2, pathWikipediaSearch: 4},
+			Rejected:      2,
+		}, quota.usage())
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		t.Parallel()
+
+		quota, clock := newTestAccountant(QuotaPolicy{HourlyCredits: 2, Wait: true})
+
+		require.NoError(t, acquire(ctx, quota, pathGet))
+		clock.advance(10 * time.Minute)
+		require.NoError(t, acquire(ctx, quota, pathGet))
+		clock.advance(10 * time.Minute)
+
+		require.NoError(t, acquire(ctx, quota, pathGet))
+		require.NoError(t, acquire(ctx, quota, pathGet))
+
+		assert.Equal(t, []time.Duration{40 * time.Minute, 10 * time.Minute}, clock.waits)
+		assert.Equal(t, 0, quota.usage().Rejected)
+	})
+
+	t.Run("credits exceed the budget", func(t *testing.T) {
+		t.Parallel()
+
+		quota, clock := newTestAccountant(QuotaPolicy{HourlyCredits: 1, Wait: true})
+
+		require.ErrorIs(t, acquire(ctx, quota, pathWikipediaSearch), ErrBudgetExceeded)
+		assert.Empty(t, clock.waits)
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		t.Parallel()
+
+		quota := newAccountant(QuotaPolicy{HourlyCredits: 1, Wait: true})
+
+		require.NoError(t, acquire(ctx, quota, pathGet))
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := acquire(canceled, quota, pathGet)
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "wait for credits")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		var quota *accountant
+
+		require.NoError(t, acquire(ctx, quota, pathGet))
+		quota.refund(pathGet, nil)
+		assert.Equal(t, Usage{}, quota.usage())
+	})
+}
+
+func Test_accountant_refund(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	quota, clock := newTestAccountant(QuotaPolicy{HourlyCredits: 3, Credits: map[string]int{pathGeoNameSearch: 1}})
+
+	first, err := quota.acquire(ctx, pathGeoNameSearch)
+	require.NoError(t, err)
+
+	clock.advance(30 * time.Minute)
+
+	_, err = quota.acquire(ctx, pathGet)
+	require.NoError(t, err)
+
+	// the refunded charge is the first one, the one of the other endpoint with the same credits is kept
+	quota.refund(pathGeoNameSearch, first)
+
+	clock.advance(45 * time.Minute)
+
+	usage := quota.usage()
+	assert.Equal(t, 1, usage.HourlyCredits)
+	assert.Equal(t, 1, usage.TotalCredits)
+	assert.Equal(t, map[string]int{pathGeoNameSearch: 0, pathGet: 1}, usage.Endpoints)
+
+	// a second refund of the same charge is ignored
+	quota.refund(pathGeoNameSearch, first)
+	assert.Equal(t, 1, quota.usage().TotalCredits)
+}
+
+func Test_window(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	w := window{length: time.Hour, charges: nil, used: 0}
+	charges := make([]*charge, 0)
+
+	for i := range 4 {
+		c := &charge{at: start.Add(time.Duration(i) * 10 * time.Minute), credits: i + 1}
+		charges = append(charges, c)
+		w.add(c)
+	}
+
+	now := start.Add(40 * time.Minute)
+
+	assert.Equal(t, 10, w.used)
+	assert.Equal(t, now, w.available(now, 0, 5))
+	assert.Equal(t, now, w.available(now, 12, 2))
+	assert.Equal(t, start.Add(70*time.Minute), w.available(now, 10, 3))
+
+	assert.True(t, w.remove(charges[2]))
+	assert.False(t, w.remove(charges[2]))
+	assert.Equal(t, 7, w.used)
+
+	w.prune(start.Add(70 * time.Minute))
+
+	assert.Equal(t, []*charge{charges[3]}, w.charges)
+	assert.Equal(t, 4, w.used)
+}
+
+func Test_Client_quota(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithQuota(QuotaPolicy{HourlyCredits: 1}))
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		_, err = client.Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrBudgetExceeded)
+
+		assert.Len(t, fake.Requests(pathGet), 1)
+		assert.Equal(t, map[string]int{pathGet: 1}, client.Usage().Endpoints)
+	})
+
+	t.Run("shared option", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		quota := WithQuota(QuotaPolicy{HourlyCredits: 1})
+
+		_, err := NewClient("demo", WithBaseURL(fake.URL()), quota).Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		_, err = NewClient("demo", WithBaseURL(fake.URL()), quota).Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrBudgetExceeded)
+	})
+
+	t.Run("retries are charged", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue(pathGet, testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+		client := NewClient(
+			"demo",
+			WithBaseURL(fake.URL()),
+			WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Jitter: 0}),
+			WithQuota(QuotaPolicy{HourlyCredits: 10}),
+		)
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, client.Usage().HourlyCredits)
+	})
+
+	t.Run("refund of requests rejected by the circuit breaker", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t)
+		fake.Handle(pathGet, testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"))
+
+		client := NewClient(
+			"demo",
+			WithBaseURL(fake.URL()),
+			WithCircuitBreaker(BreakerPolicy{Threshold: 1, Cooldown: time.Hour}),
+			WithQuota(QuotaPolicy{HourlyCredits: 10}),
+		)
+
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrServerOverloaded)
+
+		_, err = client.Get(context.Background(), GetRequest{ID: 1})
+		require.ErrorIs(t, err, ErrCircuitOpen)
+
+		assert.Equal(t, 1, client.Usage().HourlyCredits)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithQuota(QuotaPolicy{HourlyCredits: 20}))
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+
+		for range 50 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if _, err := client.Get(context.Background(), GetRequest{ID: 1}); err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 20, succeeded)
+		assert.Len(t, fake.Requests(pathGet), 20)
+		assert.Equal(t, 30, client.Usage().Rejected)
+	})
+}
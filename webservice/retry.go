@@ -0,0 +1,108 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures the retries of transient failures, see WithRetry.
+type RetryPolicy struct {
+	// MaxAttempts of a request including the first one, retries are disabled below 2
+	MaxAttempts int
+	// BaseDelay before the first retry, doubled for every further one
+	BaseDelay time.Duration
+	// MaxDelay between two attempts, a longer Retry-After of the response ends the retries
+	MaxDelay time.Duration
+	// Jitter is the randomized fraction of the delay between 0 and 1, spreading the retries of concurrent requests
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with delays from 500ms up to 10s and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WithRetry retries the requests failing with transient errors: database timeouts, overloaded servers,
+// HTTP 429 or 5xx responses without a known code, network timeouts and reset connections.
+// Other errors like ErrAuthorization, ErrInvalidParameter or an exceeded quota are never retried.
+// Retries are disabled by default.
+func WithRetry(policy RetryPolicy) Option {
+	return func(client *Client) {
+		client.retry = policy
+	}
+}
+
+// delay returns the wait before the next attempt, false if the failed attempt is not retried.
+// The Retry-After of the response takes precedence over the exponential backoff.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !isRetryable(err) {
+		return 0, false
+	}
+
+	var respErr *ResponseError
+
+	if errors.As(err, &respErr) && respErr.RetryAfter() > 0 {
+		return respErr.RetryAfter(), respErr.RetryAfter() <= p.MaxDelay
+	}
+
+	res := p.BaseDelay
+	for range attempt - 1 {
+		if res >= p.MaxDelay/2 {
+			res = p.MaxDelay
+
+			break
+		}
+
+		res *= 2
+	}
+
+	res = min(res, p.MaxDelay)
+
+	if p.Jitter > 0 {
+		res -= time.Duration(float64(res) * min(p.Jitter, 1) * rand.Float64())
+	}
+
+	return res, true
+}
+
+// isRetryable reports whether the error is transient and the request may succeed when sent again.
+func isRetryable(err error) bool {
+	var (
+		respErr *ResponseError
+		netErr  net.Error
+	)
+
+	switch {
+	case errors.As(err, &respErr):
+		return respErr.IsRetryable()
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNRESET):
+		return true
+	case errors.As(err, &netErr):
+		return netErr.Timeout()
+	default:
+		return false
+	}
+}
+
+// wait blocks for the delay or until the context is done.
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
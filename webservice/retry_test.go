@@ -0,0 +1,309 @@
+package webservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func statusResponse(status int, body string) testutil.FakeResponse {
+	res := testutil.JSONResponse([]byte(body))
+	res.Status = status
+
+	return res
+}
+
+func Test_Client_retry(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Jitter: 0.5}
+
+	truncated := testutil.ErrorResponse(value.ErrCodeDatabaseTimeout, "timeout")
+	truncated.Truncate = 5
+
+	retryAfter := statusResponse(http.StatusServiceUnavailable, "{}")
+	retryAfter.Header.Set("Retry-After", "60")
+
+	tests := []struct {
+		name      string
+		responses []testutil.FakeResponse
+		requests  int
+		err       error
+	}{
+		{
+			name:      "database timeout",
+			responses: []testutil.FakeResponse{testutil.ErrorResponse(value.ErrCodeDatabaseTimeout, "timeout")},
+			requests:  2,
+			err:       nil,
+		},
+		{
+			name:      "html error page",
+			responses: []testutil.FakeResponse{statusResponse(http.StatusBadGateway, "<html>bad gateway</html>")},
+			requests:  2,
+			err:       nil,
+		},
+		{
+			name:      "empty body",
+			responses: []testutil.FakeResponse{statusResponse(http.StatusServiceUnavailable, "")},
+			requests:  2,
+			err:       nil,
+		},
+		{
+			name:      "json without envelope",
+			responses: []testutil.FakeResponse{statusResponse(http.StatusBadGateway, `{"status":"down"}`)},
+			requests:  2,
+			err:       nil,
+		},
+		{
+			name: "empty body attempts exhausted",
+			responses: []testutil.FakeResponse{
+				statusResponse(http.StatusServiceUnavailable, ""),
+				statusResponse(http.StatusServiceUnavailable, ""),
+				statusResponse(http.StatusServiceUnavailable, ""),
+			},
+			requests: 3,
+			err:      &ResponseError{code: 0, message: "Service Unavailable", statusCode: 503, retryAfter: 0},
+		},
+		{
+			name:      "truncated body",
+			responses: []testutil.FakeResponse{truncated},
+			requests:  2,
+			err:       nil,
+		},
+		{
+			name: "attempts exhausted",
+			responses: []testutil.FakeResponse{
+				testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"),
+				testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"),
+				testutil.ErrorResponse(value.ErrCodeServerOverloaded, "overloaded"),
+			},
+			requests: 3,
+			err:      ErrServerOverloaded,
+		},
+		{
+			name:      "authorization",
+			responses: []testutil.FakeResponse{testutil.ErrorResponse(value.ErrCodeAuthorization, "user does not exist.")},
+			requests:  1,
+			err:       ErrAuthorization,
+		},
+		{
+			name:      "invalid parameter",
+			responses: []testutil.FakeResponse{testutil.ErrorResponse(value.ErrCodeInvalidParameter, "invalid lat")},
+			requests:  1,
+			err:       ErrInvalidParameter,
+		},
+		{
+			name:      "quota",
+			responses: []testutil.FakeResponse{testutil.RateLimitResponse(time.Millisecond)},
+			requests:  1,
+			err:       ErrQuotaExceeded,
+		},
+		{
+			name:      "retry after exceeds max delay",
+			responses: []testutil.FakeResponse{retryAfter},
+			requests:  1,
+			err:       &ResponseError{code: 0, message: "Service Unavailable", statusCode: 503, retryAfter: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+			fake.Enqueue("/getJSON", tt.responses...)
+
+			client := NewClient("demo", WithBaseURL(fake.URL()), WithRetry(policy))
+
+			res, err := client.Get(context.Background(), GetRequest{ID: 1})
+
+			assert.Len(t, fake.Requests("/getJSON"), tt.requests)
+
+			var respErr *ResponseError
+
+			switch {
+			case tt.err == nil:
+				require.NoError(t, err)
+				assert.NotZero(t, res.ID)
+			case errors.As(tt.err, &respErr):
+				var actual *ResponseError
+
+				require.ErrorAs(t, err, &actual)
+				assert.Equal(t, respErr, actual)
+			default:
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue("/getJSON", testutil.ErrorResponse(value.ErrCodeDatabaseTimeout, "timeout"))
+
+		_, err := NewClient("demo", WithBaseURL(fake.URL())).Get(context.Background(), GetRequest{ID: 1})
+
+		require.ErrorIs(t, err, ErrDatabaseTimeout)
+		assert.Len(t, fake.Requests("/getJSON"), 1)
+	})
+
+	t.Run("network timeout", func(t *testing.T) {
+		t.Parallel()
+
+		slow := testutil.JSONResponse([]byte(`{}`))
+		slow.Delay = 200 * time.Millisecond
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue("/getJSON", slow)
+
+		client := NewClient(
+			"demo",
+			WithBaseURL(fake.URL()),
+			WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+			WithRetry(policy),
+		)
+
+		res, err := client.Get(context.Background(), GetRequest{ID: 1})
+
+		require.NoError(t, err)
+		assert.NotZero(t, res.ID)
+		assert.Len(t, fake.Requests("/getJSON"), 2)
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		t.Parallel()
+
+		fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+		fake.Enqueue("/getJSON", testutil.ErrorResponse(value.ErrCodeDatabaseTimeout, "timeout"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		client := NewClient("demo", WithBaseURL(fake.URL()), WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Hour,
+			MaxDelay:    time.Hour,
+			Jitter:      0,
+		}))
+
+		_, err := client.Get(ctx, GetRequest{ID: 1})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "wait for retry")
+		assert.Len(t, fake.Requests("/getJSON"), 1)
+	})
+}
+
+func Test_RetryPolicy_delay(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second, Jitter: 0}
+	transient := &ResponseError{code: value.ErrCodeServerOverloaded}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		err      error
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "first retry", attempt: 1, err: transient, expected: time.Second, ok: true},
+		{name: "second retry", attempt: 2, err: transient, expected: 2 * time.Second, ok: true},
+		{name: "third retry", attempt: 3, err: transient, expected: 4 * time.Second, ok: true},
+		{name: "capped", attempt: 4, err: transient, expected: 5 * time.Second, ok: true},
+		{name: "capped without overflow", attempt: 9, err: transient, expected: 5 * time.Second, ok: true},
+		{name: "attempts exhausted", attempt: 10, err: transient, expected: 0, ok: false},
+		{
+			name:     "retry after",
+			attempt:  1,
+			err:      &ResponseError{code: value.ErrCodeServerOverloaded, retryAfter: 3 * time.Second},
+			expected: 3 * time.Second,
+			ok:       true,
+		},
+		{
+			name:     "retry after exceeds max delay",
+			attempt:  1,
+			err:      &ResponseError{code: value.ErrCodeServerOverloaded, retryAfter: time.Minute},
+			expected: time.Minute,
+			ok:       false,
+		},
+		{
+			name:     "not retryable",
+			attempt:  1,
+			err:      &ResponseError{code: value.ErrCodeAuthorization},
+			expected: 0,
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, ok := policy.delay(tt.attempt, tt.err)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+
+	t.Run("jitter", func(t *testing.T) {
+		t.Parallel()
+
+		policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.5}
+
+		for range 100 {
+			actual, ok := policy.delay(2, transient)
+
+			assert.True(t, ok)
+			assert.GreaterOrEqual(t, actual, time.Second)
+			assert.LessOrEqual(t, actual, 2*time.Second)
+		}
+	})
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func Test_isRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "server overloaded", err: &ResponseError{code: value.ErrCodeServerOverloaded}, expected: true},
+		{name: "authorization", err: &ResponseError{code: value.ErrCodeAuthorization}, expected: false},
+		{name: "wrapped", err: fmt.Errorf("decode => %w", &ResponseError{statusCode: 503}), expected: true},
+		{name: "unexpected eof", err: fmt.Errorf("decode => %w", io.ErrUnexpectedEOF), expected: true},
+		{name: "connection reset", err: fmt.Errorf("send => %w", syscall.ECONNRESET), expected: true},
+		{name: "timeout", err: fmt.Errorf("send => %w", timeoutError{}), expected: true},
+		{name: "circuit open", err: ErrCircuitOpen, expected: false},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "other", err: assert.AnError, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, isRetryable(tt.err))
+		})
+	}
+}
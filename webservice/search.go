@@ -55,8 +55,8 @@ type SearchRequest struct {
 	// Operator default is 'AND', with the operator 'OR' not all search terms need to be matched by the response
 	Operator value.Operator `url:"operator"`
 	// Fuzzy default is '1', defines the fuzziness of the search terms. float between 0 and 1.
-	// The search term is only applied to the name attribute.
-	Fuzzy float32 `url:"fuzzy"`
+	// The search term is only applied to the name attribute. Nil is unset, value.Ptr sends any value including 0.
+	Fuzzy *float32 `url:"fuzzy"`
 	// BoundingBox only features within the box are returned.
 	BoundingBox value.BoundingBox `url:",dive"`
 	// OrderBy in combination with the name_startsWith, if set to 'relevance' than the result is sorted by relevance.
@@ -79,3 +79,27 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) ([]GeoName, erro
 
 	return res.Items, err
 }
+
+// SearchAll returns the pages of Search from the StartRow of the request, the page size is MaxRows or 100.
+// The totalResultsCount of the webservice is reported by Pages.Total.
+func (c *Client) SearchAll(ctx context.Context, req SearchRequest, opts ...PagesOption) *Pages[GeoName] {
+	const defaultMaxRows = 100
+
+	return newPages(func(startRow, maxRows uint32) ([]GeoName, *int, error) {
+		var res struct {
+			Items []GeoName `json:"geonames"`
+			Total *int      `json:"totalResultsCount"`
+		}
+
+		req.StartRow, req.MaxRows = startRow, maxRows
+
+		err := c.apiRequest(
+			ctx,
+			pathGeoNameSearch,
+			req,
+			&res,
+		)
+
+		return res.Items, res.Total, err
+	}, req.StartRow, req.MaxRows, defaultMaxRows, opts)
+}
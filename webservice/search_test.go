@@ -99,7 +99,7 @@ func Test_Client_GeoNameSearch(t *testing.T) {
 					NameRequired:   true,
 					Tag:            "tag1",
 					Operator:       value.OperatorAnd,
-					Fuzzy:          0.5,
+					Fuzzy:          value.Ptr[float32](0.5),
 					BoundingBox: value.BoundingBox{
 						West:  1.0,
 						East:  2.0,
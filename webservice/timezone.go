@@ -13,7 +13,7 @@ type TimezoneRequest struct {
 	// Position the latitude and longitude of the search location
 	Position value.Position `url:",dive"`
 	// Radius is a buffer in km for closest timezone in coastal areas.
-	Radius int32 `url:"radius"`
+	Radius *float64 `url:"radius"`
 	// Language for countryName
 	Language string `url:"lang"`
 	// Date for sunrise/sunset.
@@ -57,7 +57,7 @@ func Test_Client_Timezone(t *testing.T) {
 						Latitude:  1.11,
 						Longitude: -1.11,
 					},
-					Radius:   11,
+					Radius:   value.Ptr(11.0),
 					Language: "en",
 					Date:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
 				},
@@ -0,0 +1,184 @@
+package webservice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/platx/geonames/value"
+)
+
+// ErrUsernamesExhausted is returned without sending the request when every username of the pool exceeded its
+// credit limit, it matches ErrQuotaExceeded.
+var ErrUsernamesExhausted = fmt.Errorf("all usernames exhausted => %w", ErrQuotaExceeded)
+
+// UsernameSelection is the strategy of a UsernamePool.
+type UsernameSelection int
+
+const (
+	// RoundRobin uses the usernames in turn.
+	RoundRobin UsernameSelection = iota
+	// QuotaAware uses the username which spent the fewest credits within the last hour.
+	QuotaAware
+)
+
+// UsernamePool spreads the requests over several GeoNames accounts, see WithUsernamePool. A username whose
+// hourly, daily or weekly credit limit is exceeded is skipped for the hour, day or week and the request is
+// sent again with another one. It is safe for concurrent use and may be shared by several clients.
+type UsernamePool struct {
+	selection UsernameSelection
+	now       func() time.Time
+	mu        sync.Mutex
+	users     []*poolUser
+	next      int
+}
+
+type poolUser struct {
+	name      string
+	charges   []charge
+	exhausted time.Time
+}
+
+// NewUsernamePool returns a pool of the usernames using the selection strategy.
+func NewUsernamePool(selection UsernameSelection, usernames ...string) *UsernamePool {
+	users := make([]*poolUser, 0, len(usernames))
+	for _, name := range usernames {
+		users = append(users, &poolUser{name: name, charges: nil, exhausted: time.Time{}})
+	}
+
+	return &UsernamePool{
+		selection: selection,
+		now:       time.Now,
+		mu:        sync.Mutex{},
+		users:     users,
+		next:      0,
+	}
+}
+
+// WithUsernamePool sends the requests with the usernames of the pool instead of the one of NewClient.
+func WithUsernamePool(pool *UsernamePool) Option {
+	return func(client *Client) {
+		client.usernames = pool
+	}
+}
+
+// Usernames returns the usernames which did not exceed their credit limit.
+func (p *UsernamePool) Usernames() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	var res []string
+
+	for _, user := range p.users {
+		if user.available(now) {
+			res = append(res, user.name)
+		}
+	}
+
+	return res
+}
+
+// acquire returns the username of the next request.
+func (p *UsernamePool) acquire() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	var res *poolUser
+
+	for i := range p.users {
+		user := p.users[(p.next+i)%len(p.users)]
+		if !user.available(now) {
+			continue
+		}
+
+		if p.selection == RoundRobin {
+			res = user
+
+			break
+		}
+
+		if res == nil || user.used(now) < res.used(now) {
+			res = user
+		}
+	}
+
+	if res == nil {
+		return "", ErrUsernamesExhausted
+	}
+
+	p.next++
+
+	return res.name, nil
+}
+
+// done records the credits of a sent request and skips the username if its limit is exceeded. The credits are
+// told by the client since the pool may be shared by clients of different QuotaPolicy.Credits.
+func (p *UsernamePool) done(name string, credits int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	for _, user := range p.users {
+		if user.name != name {
+			continue
+		}
+
+		user.charges = append(user.charges, charge{at: now, credits: credits})
+		user.used(now)
+
+		var respErr *ResponseError
+
+		if errors.As(err, &respErr) && respErr.IsQuota() {
+			user.exhausted = now.Add(quotaPeriod(respErr.Code()))
+		}
+	}
+}
+
+// rotate reports whether the request failed with an exceeded limit and another username is available.
+func (p *UsernamePool) rotate(err error) bool {
+	if p == nil || !errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrUsernamesExhausted) {
+		return false
+	}
+
+	return len(p.Usernames()) > 0
+}
+
+// quotaPeriod returns the period of the exceeded credit limit.
+func quotaPeriod(code value.ErrCode) time.Duration {
+	switch code {
+	case value.ErrCodeWeeklyLimitExceeded:
+		return 7 * 24 * time.Hour
+	case value.ErrCodeDailyLimitExceeded:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+func (u *poolUser) available(now time.Time) bool {
+	return !now.Before(u.exhausted)
+}
+
+// used returns the credits spent within the last hour, older charges are dropped.
+func (u *poolUser) used(now time.Time) int {
+	idx := 0
+	for idx < len(u.charges) && !u.charges[idx].at.After(now.Add(-time.Hour)) {
+		idx++
+	}
+
+	u.charges = u.charges[idx:]
+
+	var res int
+
+	for _, c := range u.charges {
+		res += c.credits
+	}
+
+	return res
+}
@@ -0,0 +1,155 @@
+package webservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platx/geonames/testutil"
+	"github.com/platx/geonames/value"
+)
+
+func acquireAll(t *testing.T, pool *UsernamePool, n int) []string {
+	t.Helper()
+
+	res := make([]string, 0, n)
+
+	for range n {
+		name, err := pool.acquire()
+		require.NoError(t, err)
+
+		res = append(res, name)
+	}
+
+	return res
+}
+
+func Test_UsernamePool(t *testing.T) {
+	t.Parallel()
+
+	hourly := &ResponseError{code: value.ErrCodeHourlyLimitExceeded}
+
+	t.Run("round robin", func(t *testing.T) {
+		t.Parallel()
+
+		pool := NewUsernamePool(RoundRobin, "a", "b", "c")
+
+		assert.Equal(t, []string{"a", "b", "c", "a"}, acquireAll(t, pool, 4))
+	})
+
+	t.Run("quota aware", func(t *testing.T) {
+		t.Parallel()
+
+		pool := NewUsernamePool(QuotaAware, "a", "b")
+
+		pool.done("a", 2, nil)
+		pool.done("b", 1, nil)
+
+		assert.Equal(t, []string{"b", "b"}, acquireAll(t, pool, 2))
+
+		pool.done("b", 1, nil)
+
+		// ties are broken in turn
+		assert.Equal(t, []string{"a", "b"}, acquireAll(t, pool, 2))
+	})
+
+	t.Run("exhausted usernames", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		pool := NewUsernamePool(RoundRobin, "a", "b")
+		pool.now = func() time.Time { return now }
+
+		pool.done("a", 1, hourly)
+
+		assert.Equal(t, []string{"b"}, pool.Usernames())
+		assert.Equal(t, []string{"b", "b"}, acquireAll(t, pool, 2))
+
+		pool.done("b", 1, &ResponseError{code: value.ErrCodeDailyLimitExceeded})
+
+		_, err := pool.acquire()
+		require.ErrorIs(t, err, ErrUsernamesExhausted)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+
+		now = now.Add(time.Hour)
+
+		assert.Equal(t, []string{"a"}, pool.Usernames())
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		t.Parallel()
+
+		pool := NewUsernamePool(RoundRobin, "a", "b")
+
+		assert.False(t, pool.rotate(nil))
+		assert.False(t, pool.rotate(&ResponseError{code: value.ErrCodeServerOverloaded}))
+		assert.True(t, pool.rotate(hourly))
+
+		pool.done("a", 1, hourly)
+		pool.done("b", 1, hourly)
+
+		assert.False(t, pool.rotate(hourly))
+
+		var empty *UsernamePool
+
+		assert.False(t, empty.rotate(hourly))
+	})
+}
+
+func Test_Client_usernamePool(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+	fake.Enqueue(pathGet, testutil.RateLimitResponse(time.Hour))
+
+	client := NewClient(
+		"unused",
+		WithBaseURL(fake.URL()),
+		WithUsernamePool(NewUsernamePool(RoundRobin, "a", "b")),
+	)
+
+	for range 3 {
+		_, err := client.Get(context.Background(), GetRequest{ID: 1})
+		require.NoError(t, err)
+	}
+
+	usernames := make([]string, 0, 4)
+	for _, req := range fake.Requests(pathGet) {
+		usernames = append(usernames, req.URL.Query().Get("username"))
+	}
+
+	// the exceeded limit of a is answered by b
+	assert.Equal(t, []string{"a", "b", "b", "b"}, usernames)
+}
+
+func Test_Client_usernamePool_credits(t *testing.T) {
+	t.Parallel()
+
+	fake := testutil.NewFakeServer(t, testutil.WithWebserviceFixtures())
+
+	client := NewClient(
+		"unused",
+		WithBaseURL(fake.URL()),
+		WithUsernamePool(NewUsernamePool(QuotaAware, "a", "b")),
+		WithQuota(QuotaPolicy{HourlyCredits: 0, DailyCredits: 0, Wait: false, Credits: map[string]int{pathGet: 10}}),
+	)
+
+	_, err := client.Get(context.Background(), GetRequest{ID: 1})
+	require.NoError(t, err)
+
+	_, err = client.Search(context.Background(), SearchRequest{Query: "london"})
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), GetRequest{ID: 1})
+	require.NoError(t, err)
+
+	requests := fake.Requests(pathGet)
+	require.Len(t, requests, 2)
+
+	// a spent the 10 credits of the overridden endpoint, b only one
+	assert.Equal(t, "b", requests[1].URL.Query().Get("username"))
+}
@@ -15,6 +15,8 @@ type WikipediaSearchRequest struct {
 	Language string `url:"lang"`
 	// MaxRows maximal number of rows returned (default = 10)
 	MaxRows uint32 `url:"maxRows"`
+	// StartRow used for paging results, see WikipediaSearchAll
+	StartRow uint32 `url:"startRow"`
 }
 
 // WikipediaSearch returns the wikipedia entries found for the searchterm.
@@ -33,3 +35,30 @@ func (c *Client) WikipediaSearch(ctx context.Context, req WikipediaSearchRequest
 
 	return res.Items, err
 }
+
+// WikipediaSearchAll returns the pages of WikipediaSearch from the StartRow of the request,
+// the page size is MaxRows or 10. The webservice does not report the total count.
+func (c *Client) WikipediaSearchAll(
+	ctx context.Context,
+	req WikipediaSearchRequest,
+	opts ...PagesOption,
+) *Pages[Wikipedia] {
+	const defaultMaxRows = 10
+
+	return newPages(func(startRow, maxRows uint32) ([]Wikipedia, *int, error) {
+		var res struct {
+			Items []Wikipedia `json:"geonames"`
+		}
+
+		req.StartRow, req.MaxRows = startRow, maxRows
+
+		err := c.apiRequest(
+			ctx,
+			pathWikipediaSearch,
+			req,
+			&res,
+		)
+
+		return res.Items, nil, err
+	}, req.StartRow, req.MaxRows, defaultMaxRows, opts)
+}